@@ -0,0 +1,37 @@
+package clock
+
+import "time"
+
+// adaptiveSchedule is a Schedule whose intervals are chosen by a callback
+// as it goes. It is stateful, so it only serves the one ScheduleTicker.
+type adaptiveSchedule struct {
+	d       time.Duration
+	next    func(tick time.Time, d time.Duration) time.Duration
+	started bool
+}
+
+func (s *adaptiveSchedule) Next(t time.Time) time.Time {
+	if s.started {
+		s.d = s.next(t, s.d)
+	}
+	s.started = true
+	if s.d <= 0 {
+		return time.Time{}
+	}
+	return t.Add(s.d)
+}
+
+// NewAdaptiveTicker returns a ScheduleTicker that first ticks d after the
+// current time on c, and after each tick calls next with the tick's time and
+// the interval that led to it to choose the interval to the following one,
+// as adaptive polling backs off while idle and speeds up when busy. If next
+// returns a non-positive interval, the ticker ends and C is closed. next
+// is called from the ticker's timer callback, so on a mock it runs during
+// the advance that reaches the tick, and the intervals are the same on every
+// run. It panics if d is not positive.
+func NewAdaptiveTicker(c MockableClock, d time.Duration, next func(tick time.Time, d time.Duration) time.Duration) *ScheduleTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewAdaptiveTicker")
+	}
+	return NewScheduleTicker(c, &adaptiveSchedule{d: d, next: next})
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveTicker(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	var seen []time.Duration
+	ticker := NewAdaptiveTicker(mock, time.Second, func(tick time.Time, d time.Duration) time.Duration {
+		seen = append(seen, d)
+		if d >= 4*time.Second {
+			return 0
+		}
+		return 2 * d
+	})
+
+	for _, at := range []time.Duration{1, 3, 7} {
+		mock.Add(at*time.Second - mock.Now().Sub(start))
+		assert.Equal(t, start.Add(at*time.Second), <-ticker.C)
+	}
+	mock.Add(time.Minute)
+	_, ok := <-ticker.C
+	assert.False(t, ok)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, seen)
+
+	assert.PanicsWithValue(t, "non-positive interval for NewAdaptiveTicker", func() {
+		NewAdaptiveTicker(mock, 0, nil)
+	})
+}
+
+func TestAdaptiveTicker_Stop(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	calls := 0
+	ticker := NewAdaptiveTicker(mock, time.Second, func(time.Time, time.Duration) time.Duration {
+		calls++
+		return time.Second
+	})
+	mock.Add(time.Second)
+	<-ticker.C
+	ticker.Stop()
+	mock.Add(time.Minute)
+	assert.Equal(t, 1, calls)
+}
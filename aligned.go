@@ -0,0 +1,80 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// alignedTicker implements a ticker whose ticks fall on multiples of its
+// interval, rather than at multiples of the interval after its creation, on
+// top of a clock's AfterFunc.
+type alignedTicker struct {
+	mu      sync.Mutex
+	clock   MockableClock
+	c       chan time.Time
+	d       time.Duration
+	timer   MockableTimer
+	stopped bool
+}
+
+// AlignTicker returns a ticker whose ticks fall on multiples of d, built from
+// c's Now and AfterFunc. The realtime clock uses it for NewAlignedTicker, and
+// other clock implementations can use it to satisfy MockableClock.
+func AlignTicker(c MockableClock, d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewAlignedTicker")
+	}
+	a := &alignedTicker{clock: c, c: make(chan time.Time, 1), d: d}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.schedule()
+	return WrapTicker(a.c, a)
+}
+
+// schedule arms the timer for the next boundary. It must be called with mu held.
+func (a *alignedTicker) schedule() {
+	now := a.clock.Now()
+	a.timer = a.clock.AfterFunc(now.Truncate(a.d).Add(a.d).Sub(now), a.fire)
+}
+
+func (a *alignedTicker) fire() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopped {
+		return
+	}
+	select {
+	case a.c <- a.clock.Now():
+	default:
+	}
+	a.schedule()
+}
+
+func (a *alignedTicker) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopped = true
+	a.timer.Stop()
+}
+
+func (a *alignedTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.timer.Stop()
+	a.d = d
+	a.stopped = false
+	a.schedule()
+}
+
+// NewAlignedTicker creates a new ticker whose ticks fall on multiples of d,
+// measured from the zero time. For intervals that divide a day, this is the
+// top of each second, minute, hour etc. in UTC.
+func (m *UnsynchronizedMock) NewAlignedTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewAlignedTicker")
+	}
+	return m.newTicker(d, CoalesceTicks, 1, true, m.callers())
+}
@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the clock's aligned ticker ticks on interval boundaries.
+func TestClock_AlignedTicker(t *testing.T) {
+	ticker := New().NewAlignedTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 0; i < 2; i++ {
+		tick := <-ticker.C
+		// allow margin for thread scheduling
+		if offset := tick.Sub(tick.Truncate(20 * time.Millisecond)); offset > 5*time.Millisecond {
+			t.Fatalf("tick %v is %v past the boundary", tick, offset)
+		}
+	}
+}
+
+// Ensure that the mock's aligned ticker ticks on interval boundaries.
+func TestMock_AlignedTicker(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	clock.Set(time.Date(2021, 5, 11, 10, 59, 20, 0, time.UTC))
+
+	ticker := clock.NewAlignedTicker(time.Minute)
+	defer ticker.Stop()
+
+	clock.Add(40 * time.Second)
+	assert.Equal(t, time.Date(2021, 5, 11, 11, 0, 0, 0, time.UTC), (<-ticker.C).UTC())
+
+	clock.Add(time.Minute)
+	assert.Equal(t, time.Date(2021, 5, 11, 11, 1, 0, 0, time.UTC), (<-ticker.C).UTC())
+
+	// Reset realigns to the new interval
+	ticker.Reset(time.Hour)
+	clock.Add(time.Hour)
+	assert.Equal(t, time.Date(2021, 5, 11, 12, 0, 0, 0, time.UTC), (<-ticker.C).UTC())
+}
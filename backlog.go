@@ -0,0 +1,115 @@
+package clock
+
+import "time"
+
+// BacklogPolicy controls what a mock ticker does with a tick when its channel
+// is full because the consumer has fallen behind.
+type BacklogPolicy int
+
+const (
+	// CoalesceTicks drops the tick, as time.Ticker does. The consumer sees
+	// only the ticks that fit in the channel.
+	CoalesceTicks BacklogPolicy = iota
+	// QueueTicks holds the tick and delivers it, in order, once the consumer
+	// has made room. The consumer sees every tick, some of them late.
+	QueueTicks
+	// BlockTicks blocks the advancing clock until the consumer has made room
+	// for the tick, or the ticker is stopped. A consumer that stops reading
+	// without stopping the ticker hangs the advance.
+	BlockTicks
+)
+
+func (p BacklogPolicy) String() string {
+	switch p {
+	case CoalesceTicks:
+		return "CoalesceTicks"
+	case QueueTicks:
+		return "QueueTicks"
+	case BlockTicks:
+		return "BlockTicks"
+	}
+	return "BacklogPolicy(?)"
+}
+
+// NewTickerWithBacklog creates a new ticker whose channel holds size ticks,
+// and which handles further ticks according to policy. NewTicker is
+// equivalent to NewTickerWithBacklog(d, CoalesceTicks, 1). It panics if size
+// is negative.
+func (m *UnsynchronizedMock) NewTickerWithBacklog(d time.Duration, policy BacklogPolicy, size int) *Ticker {
+	if size < 0 {
+		panic("negative size for NewTickerWithBacklog")
+	}
+	return m.newTicker(d, policy, size, false, m.callers())
+}
+
+// deliver sends a tick to the consumer according to the ticker's policy.
+func (t *internalTicker) deliver(now time.Time) {
+	switch t.policy {
+	case BlockTicks:
+		select {
+		case t.c <- now:
+		case <-t.stop:
+		}
+	case QueueTicks:
+		t.mock.mu.Lock()
+		defer t.mock.mu.Unlock()
+		if len(t.backlog) == 0 {
+			select {
+			case t.c <- now:
+				return
+			default:
+			}
+		}
+		t.backlog = append(t.backlog, now)
+		if t.pumpDone == nil {
+			t.pumpDone = make(chan struct{})
+			go t.pump(t.pumpDone)
+		}
+	default:
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+}
+
+// pump delivers queued ticks as the consumer makes room for them, until the
+// backlog is empty or done is closed.
+func (t *internalTicker) pump(done chan struct{}) {
+	for {
+		t.mock.mu.Lock()
+		if len(t.backlog) == 0 {
+			if t.pumpDone == done {
+				t.pumpDone = nil
+			}
+			t.mock.mu.Unlock()
+			return
+		}
+		next := t.backlog[0]
+		t.mock.mu.Unlock()
+
+		select {
+		case t.c <- next:
+			t.mock.mu.Lock()
+			if t.pumpDone != done {
+				// the backlog was dropped while we were delivering
+				t.mock.mu.Unlock()
+				return
+			}
+			t.backlog = t.backlog[1:]
+			t.mock.mu.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+// dropBacklog discards undelivered ticks. It must be called with the mock's
+// mu held.
+func (t *internalTicker) dropBacklog() {
+	t.backlog = nil
+	if t.pumpDone != nil {
+		close(t.pumpDone)
+		t.pumpDone = nil
+	}
+}
@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the default policy drops ticks the consumer isn't ready for.
+func TestMock_Ticker_Coalesce(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ticker := clock.NewTickerWithBacklog(1*time.Second, CoalesceTicks, 2)
+	defer ticker.Stop()
+
+	clock.Add(5 * time.Second)
+	assert.Equal(t, time.Unix(1, 0), <-ticker.C)
+	assert.Equal(t, time.Unix(2, 0), <-ticker.C)
+	select {
+	case tick := <-ticker.C:
+		t.Fatalf("unexpected tick %v", tick)
+	default:
+	}
+}
+
+// Ensure that queued ticks are all delivered, in order, once the consumer reads.
+func TestMock_Ticker_Queue(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ticker := clock.NewTickerWithBacklog(1*time.Second, QueueTicks, 1)
+	defer ticker.Stop()
+
+	clock.Add(5 * time.Second)
+	for i := int64(1); i <= 5; i++ {
+		select {
+		case tick := <-ticker.C:
+			assert.Equal(t, time.Unix(i, 0), tick)
+		case <-time.After(1 * time.Second):
+			t.Fatalf("tick %d never delivered", i)
+		}
+	}
+}
+
+// Ensure that stopping a ticker discards its queued ticks.
+func TestMock_Ticker_Queue_Stop(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ticker := clock.NewTickerWithBacklog(1*time.Second, QueueTicks, 1)
+
+	clock.Add(5 * time.Second)
+	ticker.Stop()
+	<-ticker.C
+	select {
+	case tick := <-ticker.C:
+		t.Fatalf("unexpected tick %v", tick)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// Ensure that the blocking policy holds the clock until the consumer catches up.
+func TestMock_Ticker_Block(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ticker := clock.NewTickerWithBacklog(1*time.Second, BlockTicks, 0)
+	defer ticker.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		clock.Add(3 * time.Second)
+		close(done)
+	}()
+
+	for i := int64(1); i <= 3; i++ {
+		assert.Equal(t, time.Unix(i, 0), <-ticker.C)
+	}
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("mock.Add hung")
+	}
+}
+
+// Ensure that stopping a blocking ticker releases an advance waiting on a
+// consumer that stopped reading.
+func TestMock_Ticker_Block_Stop(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ticker := clock.NewTickerWithBacklog(1*time.Second, BlockTicks, 0)
+
+	done := make(chan struct{})
+	go func() {
+		clock.Add(3 * time.Second)
+		close(done)
+	}()
+	<-ticker.C
+	ticker.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("mock.Add hung after Stop")
+	}
+	assert.Equal(t, time.Unix(3, 0), clock.Now())
+}
+
+// Ensure that a negative size is rejected with a clear message.
+func TestMock_NewTickerWithBacklog_NegativeSize(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	assert.PanicsWithValue(t, "negative size for NewTickerWithBacklog", func() {
+		clock.NewTickerWithBacklog(time.Second, BlockTicks, -1)
+	})
+}
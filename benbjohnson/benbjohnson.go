@@ -0,0 +1,87 @@
+// Package benbjohnson adapts clocks from github.com/benbjohnson/clock to
+// MockableClock, so that code written against either package can share a
+// single source of time during a migration.
+package benbjohnson
+
+import (
+	"context"
+	"time"
+
+	bclock "github.com/benbjohnson/clock"
+	"github.com/kraney/clock"
+)
+
+// adapter implements clock.MockableClock on top of a benbjohnson clock.
+type adapter struct {
+	c bclock.Clock
+}
+
+// FromClock returns a MockableClock that reads and schedules on c. Passing a
+// *bclock.Mock lets code using this package be driven by the benbjohnson
+// mock, alongside code that uses it directly.
+func FromClock(c bclock.Clock) clock.MockableClock {
+	return &adapter{c: c}
+}
+
+// ToClock returns a benbjohnson clock for c, and whether one is available.
+// The benbjohnson Timer and Ticker types can only be created by that
+// package's own clocks, so only clocks that already have a benbjohnson
+// equivalent can be converted: the realtime clock, and clocks returned by
+// FromClock. To share virtual time with code that needs a benbjohnson clock,
+// create a *bclock.Mock and wrap it with FromClock instead.
+func ToClock(c clock.MockableClock) (bclock.Clock, bool) {
+	if a, ok := c.(*adapter); ok {
+		return a.c, true
+	}
+	if clock.IsRealtime(c) {
+		return bclock.New(), true
+	}
+	return nil, false
+}
+
+func (a *adapter) After(d time.Duration) <-chan time.Time { return a.c.After(d) }
+
+func (a *adapter) AfterAt(t time.Time) <-chan time.Time { return a.c.After(a.c.Until(t)) }
+
+func (a *adapter) AfterFunc(d time.Duration, f func()) clock.MockableTimer {
+	return clock.WrapTimer(nil, a.c.AfterFunc(d, f))
+}
+
+func (a *adapter) AtFunc(t time.Time, f func()) clock.MockableTimer {
+	return a.AfterFunc(a.c.Until(t), f)
+}
+
+func (a *adapter) Now() time.Time { return a.c.Now() }
+
+func (a *adapter) Since(t time.Time) time.Duration { return a.c.Since(t) }
+
+func (a *adapter) Sleep(d time.Duration) { a.c.Sleep(d) }
+
+func (a *adapter) SleepUntil(t time.Time) { a.c.Sleep(a.c.Until(t)) }
+
+func (a *adapter) SleepContext(ctx context.Context, d time.Duration) error {
+	t := a.c.Timer(d)
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
+
+func (a *adapter) Tick(d time.Duration) <-chan time.Time { return a.c.Tick(d) }
+
+func (a *adapter) NewTicker(d time.Duration) *clock.Ticker {
+	t := a.c.Ticker(d)
+	return clock.WrapTicker(t.C, t)
+}
+
+func (a *adapter) NewAlignedTicker(d time.Duration) *clock.Ticker {
+	return clock.AlignTicker(a, d)
+}
+
+func (a *adapter) NewTimer(d time.Duration) *clock.Timer {
+	t := a.c.Timer(d)
+	return clock.WrapTimer(t.C, t)
+}
@@ -0,0 +1,63 @@
+package benbjohnson
+
+import (
+	"testing"
+	"time"
+
+	bclock "github.com/benbjohnson/clock"
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that timers created through the adapter are driven by the
+// benbjohnson mock.
+func TestFromClock_Timer(t *testing.T) {
+	mock := bclock.NewMock()
+	c := FromClock(mock)
+	assert.Equal(t, mock.Now(), c.Now())
+
+	timer := c.NewTimer(10 * time.Second)
+	mock.Add(5 * time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("timer fired early")
+	default:
+	}
+	assert.True(t, timer.Reset(10*time.Second))
+
+	mock.Add(10 * time.Second)
+	select {
+	case <-timer.C:
+	default:
+		t.Fatal("timer did not fire")
+	}
+	assert.False(t, timer.Stop())
+}
+
+// Ensure that tickers created through the adapter tick with the mock.
+func TestFromClock_Ticker(t *testing.T) {
+	mock := bclock.NewMock()
+	ticker := FromClock(mock).NewTicker(time.Second)
+	defer ticker.Stop()
+
+	mock.Add(time.Second)
+	select {
+	case <-ticker.C:
+	default:
+		t.Fatal("ticker did not tick")
+	}
+}
+
+// Ensure that clocks convert back only when a benbjohnson clock exists.
+func TestToClock(t *testing.T) {
+	mock := bclock.NewMock()
+	c, ok := ToClock(FromClock(mock))
+	assert.True(t, ok)
+	assert.Equal(t, mock, c)
+
+	_, ok = ToClock(clock.New())
+	assert.True(t, ok)
+
+	_, ok = ToClock(clock.NewUnsynchronizedMock())
+	assert.False(t, ok)
+}
@@ -0,0 +1,158 @@
+package clock
+
+import "time"
+
+// businessDays bounds how far ahead a BusinessCalendar looks for working
+// time, so a calendar with none doesn't search forever.
+const businessDays = 2 * 366
+
+// BusinessCalendar describes working hours, for SLA and escalation logic
+// that counts only business time.
+type BusinessCalendar struct {
+	// Open and Close are the start and end of the working day, as wall
+	// clock offsets from midnight, such as 9*time.Hour and 17*time.Hour.
+	Open, Close time.Duration
+	// Weekdays are the working days. If empty, they are Monday to Friday.
+	Weekdays []time.Weekday
+	// Holidays are days on which there is no working time. Only their
+	// dates are used, in Location.
+	Holidays []time.Time
+	// Location is the zone the working hours are in. If nil, the location
+	// of the times passed to the calendar's methods is used.
+	Location *time.Location
+}
+
+// IsOpen reports whether t is within working hours.
+func (cal *BusinessCalendar) IsOpen(t time.Time) bool {
+	open, close, ok := cal.hours(t)
+	return ok && !t.Before(open) && t.Before(close)
+}
+
+// NextOpen returns t if it is within working hours, and otherwise the start
+// of the next working day. It returns the zero time if there is no working
+// time in the following two years.
+func (cal *BusinessCalendar) NextOpen(t time.Time) time.Time {
+	day := t
+	for i := 0; i < businessDays; i++ {
+		if open, close, ok := cal.hours(day); ok && t.Before(close) {
+			if t.Before(open) {
+				return open
+			}
+			return t
+		}
+		day = cal.in(day)
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 12, 0, 0, 0, day.Location())
+	}
+	return time.Time{}
+}
+
+// Add returns the time at which d of working time has passed since t, such
+// as the deadline for an SLA measured in business hours. A d of zero
+// returns NextOpen(t).
+func (cal *BusinessCalendar) Add(t time.Time, d time.Duration) time.Time {
+	for t = cal.NextOpen(t); !t.IsZero(); t = cal.NextOpen(t) {
+		_, close, _ := cal.hours(t)
+		left := close.Sub(t)
+		if d <= left {
+			return t.Add(d)
+		}
+		d -= left
+		t = close
+	}
+	return t
+}
+
+// Elapsed returns the working time between from and to.
+func (cal *BusinessCalendar) Elapsed(from, to time.Time) time.Duration {
+	var total time.Duration
+	for t := cal.NextOpen(from); !t.IsZero() && t.Before(to); t = cal.NextOpen(t) {
+		_, close, _ := cal.hours(t)
+		if close.After(to) {
+			close = to
+		}
+		total += close.Sub(t)
+		t = close
+	}
+	return total
+}
+
+// hours returns the working hours of the day containing t, and whether it
+// is a working day.
+func (cal *BusinessCalendar) hours(t time.Time) (open, close time.Time, ok bool) {
+	t = cal.in(t)
+	y, m, d := t.Date()
+	if !cal.isWeekday(t.Weekday()) {
+		return time.Time{}, time.Time{}, false
+	}
+	for _, h := range cal.Holidays {
+		if hy, hm, hd := cal.in(h).Date(); hy == y && hm == m && hd == d {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	// time.Date normalizes the offsets as wall clock times, so working hours
+	// keep to the clock on days with a daylight saving change.
+	open = time.Date(y, m, d, 0, 0, 0, int(cal.Open), t.Location())
+	close = time.Date(y, m, d, 0, 0, 0, int(cal.Close), t.Location())
+	return open, close, open.Before(close)
+}
+
+func (cal *BusinessCalendar) isWeekday(wd time.Weekday) bool {
+	if len(cal.Weekdays) == 0 {
+		return wd != time.Saturday && wd != time.Sunday
+	}
+	for _, w := range cal.Weekdays {
+		if w == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func (cal *BusinessCalendar) in(t time.Time) time.Time {
+	if cal.Location == nil {
+		return t
+	}
+	return t.In(cal.Location)
+}
+
+// BusinessScheduler applies a BusinessCalendar to the time on a clock, so
+// that code with deadlines in business time can be tested by advancing a
+// mock across nights, weekends and holidays.
+type BusinessScheduler struct {
+	clock MockableClock
+	cal   BusinessCalendar
+}
+
+// NewBusinessScheduler returns a BusinessScheduler that reads the time from
+// c. It panics if cal's working day is not positive.
+func NewBusinessScheduler(c MockableClock, cal BusinessCalendar) *BusinessScheduler {
+	if cal.Close <= cal.Open {
+		panic("non-positive working hours for NewBusinessScheduler")
+	}
+	return &BusinessScheduler{clock: c, cal: cal}
+}
+
+// IsOpen reports whether the clock is within working hours.
+func (s *BusinessScheduler) IsOpen() bool { return s.cal.IsOpen(s.clock.Now()) }
+
+// Next returns the next business moment: the clock's Now if it is within
+// working hours, and otherwise the start of the next working day.
+func (s *BusinessScheduler) Next() time.Time { return s.cal.NextOpen(s.clock.Now()) }
+
+// Deadline returns the time at which d of working time will have passed.
+func (s *BusinessScheduler) Deadline(d time.Duration) time.Time {
+	return s.cal.Add(s.clock.Now(), d)
+}
+
+// Elapsed returns the working time that has passed since t.
+func (s *BusinessScheduler) Elapsed(t time.Time) time.Duration {
+	return s.cal.Elapsed(t, s.clock.Now())
+}
+
+// AfterFunc runs f on the clock once d of working time has passed, such as
+// to escalate a ticket that hasn't been answered within its SLA. The
+// deadline is fixed when AfterFunc is called; Reset on the returned timer
+// takes a plain duration.
+func (s *BusinessScheduler) AfterFunc(d time.Duration, f func()) MockableTimer {
+	return s.clock.AtFunc(s.Deadline(d), f)
+}
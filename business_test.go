@@ -0,0 +1,82 @@
+package clock
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 2024-03-28 is a Thursday, and 2024-03-29 is Good Friday.
+var testCalendar = BusinessCalendar{
+	Open:     9 * time.Hour,
+	Close:    17 * time.Hour,
+	Holidays: []time.Time{time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC)},
+}
+
+func businessTime(month time.Month, day, hour, min int) time.Time {
+	return time.Date(2024, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestBusinessCalendar(t *testing.T) {
+	cal := testCalendar
+	assert.True(t, cal.IsOpen(businessTime(time.March, 28, 9, 0)))
+	assert.False(t, cal.IsOpen(businessTime(time.March, 28, 17, 0)))
+	assert.False(t, cal.IsOpen(businessTime(time.March, 29, 12, 0)), "open on a holiday")
+	assert.False(t, cal.IsOpen(businessTime(time.March, 30, 12, 0)), "open on a weekend")
+
+	assert.Equal(t, businessTime(time.March, 28, 10, 0), cal.NextOpen(businessTime(time.March, 28, 10, 0)))
+	assert.Equal(t, businessTime(time.March, 28, 9, 0), cal.NextOpen(businessTime(time.March, 28, 7, 0)))
+	assert.Equal(t, businessTime(time.April, 1, 9, 0), cal.NextOpen(businessTime(time.March, 28, 17, 0)))
+
+	assert.Equal(t, businessTime(time.March, 28, 17, 0), cal.Add(businessTime(time.March, 28, 13, 0), 4*time.Hour))
+	assert.Equal(t, businessTime(time.April, 1, 13, 0), cal.Add(businessTime(time.March, 28, 13, 0), 8*time.Hour))
+	assert.Equal(t, businessTime(time.April, 1, 9, 0), cal.Add(businessTime(time.March, 29, 8, 0), 0))
+
+	assert.Equal(t, 12*time.Hour, cal.Elapsed(businessTime(time.March, 28, 9, 0), businessTime(time.April, 1, 13, 0)))
+	assert.Equal(t, time.Duration(0), cal.Elapsed(businessTime(time.March, 29, 0, 0), businessTime(time.April, 1, 0, 0)))
+
+	weekends := BusinessCalendar{Open: 10 * time.Hour, Close: 16 * time.Hour, Weekdays: []time.Weekday{time.Saturday, time.Sunday}}
+	assert.Equal(t, businessTime(time.March, 30, 10, 0), weekends.NextOpen(businessTime(time.March, 28, 12, 0)))
+
+	assert.True(t, (&BusinessCalendar{}).NextOpen(businessTime(time.March, 28, 12, 0)).IsZero(), "found time in an empty calendar")
+}
+
+// Ensure that business hours keep to the wall clock across daylight saving
+// changes.
+func TestBusinessCalendar_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	cal := BusinessCalendar{Open: 9 * time.Hour, Close: 17 * time.Hour, Location: ny}
+	friday := time.Date(2024, 3, 8, 16, 0, 0, 0, ny)
+	assert.Equal(t, "Mar 11 09:00 EDT", cal.NextOpen(friday.Add(time.Hour)).Format("Jan 2 15:04 MST"))
+	assert.Equal(t, "Mar 11 10:00 EDT", cal.Add(friday, 2*time.Hour).In(ny).Format("Jan 2 15:04 MST"))
+}
+
+func TestBusinessScheduler(t *testing.T) {
+	mock := NewMock(t, 0)
+	mock.Set(businessTime(time.March, 28, 13, 0))
+	mock.ExpectStarts(1)
+	s := NewBusinessScheduler(mock, testCalendar)
+	assert.True(t, s.IsOpen())
+	assert.Equal(t, businessTime(time.March, 28, 13, 0), s.Next())
+
+	var escalated int32
+	start := mock.Now()
+	s.AfterFunc(8*time.Hour, func() { atomic.StoreInt32(&escalated, 1) })
+
+	// across the evening, the holiday and the weekend
+	mock.Set(businessTime(time.April, 1, 12, 59))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&escalated))
+	assert.True(t, s.IsOpen())
+	assert.Equal(t, 8*time.Hour-time.Minute, s.Elapsed(start))
+	mock.Add(time.Minute)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&escalated))
+
+	assert.PanicsWithValue(t, "non-positive working hours for NewBusinessScheduler", func() {
+		NewBusinessScheduler(mock, BusinessCalendar{Open: 9 * time.Hour, Close: 9 * time.Hour})
+	})
+}
@@ -0,0 +1,175 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheItem[V any] struct {
+	value   V
+	expires time.Time // zero if the item never expires
+}
+
+// Cache is a map whose entries expire after a time-to-live measured on a
+// MockableClock. Expired entries are never returned, and are removed lazily
+// when they are looked up, or periodically once StartEviction is called.
+type Cache[K comparable, V any] struct {
+	clock MockableClock
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	items   map[K]cacheItem[V]
+	onEvict func(K, V)
+	ticker  *Ticker
+	done    chan struct{}
+}
+
+// NewCache returns an empty Cache timed by c, whose entries expire ttl after
+// they are set unless another TTL is given. A non-positive ttl means entries
+// don't expire by default.
+func NewCache[K comparable, V any](c MockableClock, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{clock: c, ttl: ttl, items: map[K]cacheItem[V]{}}
+}
+
+// OnEvict registers a function that is called with each entry that is
+// removed because it expired.
+func (c *Cache[K, V]) OnEvict(fn func(K, V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = fn
+}
+
+// Set stores value under key with the cache's default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores value under key, to expire after ttl. A non-positive ttl
+// means the entry doesn't expire.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	item := cacheItem[V]{value: value}
+	if ttl > 0 {
+		item.expires = c.clock.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = item
+}
+
+// Get returns the value stored under key, and whether it was present and
+// unexpired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	item, ok := c.items[key]
+	if ok && item.expired(now) {
+		delete(c.items, key)
+		onEvict := c.onEvict
+		c.mu.Unlock()
+		if onEvict != nil {
+			onEvict(key, item.value)
+		}
+		var zero V
+		return zero, false
+	}
+	c.mu.Unlock()
+	return item.value, ok
+}
+
+// TTL returns how long the entry stored under key has left before it
+// expires, and whether it was present and unexpired. Entries that never
+// expire report a zero TTL.
+func (c *Cache[K, V]) TTL(key K) (time.Duration, bool) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[key]
+	if !ok || item.expired(now) {
+		return 0, false
+	}
+	if item.expires.IsZero() {
+		return 0, true
+	}
+	return item.expires.Sub(now), true
+}
+
+// Delete removes the entry stored under key, if any.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Len returns the number of entries stored in the cache, including expired
+// entries that have not yet been evicted.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// EvictExpired removes every expired entry and returns how many there were.
+func (c *Cache[K, V]) EvictExpired() int {
+	now := c.clock.Now()
+	c.mu.Lock()
+	type evicted struct {
+		key   K
+		value V
+	}
+	var expired []evicted
+	for k, item := range c.items {
+		if item.expired(now) {
+			expired = append(expired, evicted{k, item.value})
+			delete(c.items, k)
+		}
+	}
+	onEvict := c.onEvict
+	c.mu.Unlock()
+
+	if onEvict != nil {
+		for _, e := range expired {
+			onEvict(e.key, e.value)
+		}
+	}
+	return len(expired)
+}
+
+// StartEviction starts a goroutine that calls EvictExpired on every tick of
+// a ticker with the given interval, until Stop is called. It creates one
+// ticker on the clock.
+func (c *Cache[K, V]) StartEviction(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ticker != nil {
+		return
+	}
+	c.ticker = c.clock.NewTicker(interval)
+	c.done = make(chan struct{})
+	go func(ticker *Ticker, done chan struct{}) {
+		for {
+			select {
+			case <-ticker.C:
+				c.EvictExpired()
+			case <-done:
+				return
+			}
+		}
+	}(c.ticker, c.done)
+}
+
+// Stop stops the eviction goroutine started by StartEviction, if any.
+func (c *Cache[K, V]) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ticker == nil {
+		return
+	}
+	c.ticker.Stop()
+	close(c.done)
+	c.ticker = nil
+	c.done = nil
+}
+
+func (item cacheItem[V]) expired(now time.Time) bool {
+	return !item.expires.IsZero() && !now.Before(item.expires)
+}
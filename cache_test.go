@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that entries expire lazily as the mock advances.
+func TestCache_Get(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	cache := NewCache[string, int](clock, 10*time.Second)
+
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, 20*time.Second)
+	cache.SetWithTTL("c", 3, 0)
+
+	clock.Add(9 * time.Second)
+	v, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	ttl, ok := cache.TTL("b")
+	assert.True(t, ok)
+	assert.Equal(t, 11*time.Second, ttl)
+
+	clock.Add(1 * time.Second)
+	_, ok = cache.Get("a")
+	assert.False(t, ok, "entry should expire at its TTL")
+	assert.Equal(t, 2, cache.Len())
+
+	clock.Add(1 * time.Hour)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	v, ok = cache.Get("c")
+	assert.True(t, ok, "entry without TTL should not expire")
+	assert.Equal(t, 3, v)
+
+	cache.Delete("c")
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+}
+
+// Ensure that the eviction ticker removes expired entries.
+func TestCache_StartEviction(t *testing.T) {
+	clock := NewMock(t, 1)
+	confirm := NewFailOnUnexpectedCheckpoint(CheckpointName("evicted"), t)
+	cache := NewCache[int, string](clock, 15*time.Second)
+	cache.OnEvict(func(k int, v string) { confirm.Done() })
+	cache.StartEviction(10 * time.Second)
+	defer cache.Stop()
+
+	cache.Set(1, "one")
+	cache.Set(2, "two")
+	cache.SetWithTTL(3, "three", time.Minute)
+
+	// The first tick is before the entries expire.
+	clock.Add(10 * time.Second)
+	assert.Equal(t, 3, cache.Len())
+
+	confirm.Add(2)
+	clock.Add(10 * time.Second)
+	confirm.Wait()
+	assert.Equal(t, 1, cache.Len())
+
+	assert.Equal(t, 0, cache.EvictExpired())
+}
@@ -0,0 +1,87 @@
+package clock
+
+import "time"
+
+// calendarSchedule is a Schedule that runs at a wall-clock time of day on
+// the days that match.
+type calendarSchedule struct {
+	hour, min int
+	loc       *time.Location       // zone to evaluate in, if set
+	matches   func(time.Time) bool // whether to run on a day
+}
+
+// Daily returns a Schedule that runs at hour:min every day in loc, or in the
+// location of the times passed to Next if loc is nil. Unlike a cron
+// expression, which skips times that a daylight saving change makes
+// nonexistent, it runs late by the length of the gap on those days, and on
+// days when the time occurs twice, it runs only once.
+func Daily(hour, min int, loc *time.Location) Schedule {
+	return &calendarSchedule{hour: hour, min: min, loc: loc, matches: func(time.Time) bool { return true }}
+}
+
+// Weekly returns a Schedule that runs at hour:min every week on weekday, in
+// loc, with daylight saving changes handled as by Daily.
+func Weekly(weekday time.Weekday, hour, min int, loc *time.Location) Schedule {
+	return &calendarSchedule{hour: hour, min: min, loc: loc, matches: func(day time.Time) bool {
+		return day.Weekday() == weekday
+	}}
+}
+
+// Monthly returns a Schedule that runs at hour:min every month on day, in
+// loc, with daylight saving changes handled as by Daily. In months with
+// fewer days, it runs on the last day of the month.
+func Monthly(day, hour, min int, loc *time.Location) Schedule {
+	return &calendarSchedule{hour: hour, min: min, loc: loc, matches: func(d time.Time) bool {
+		last := time.Date(d.Year(), d.Month()+1, 0, 12, 0, 0, 0, d.Location()).Day()
+		return d.Day() == day || (day > last && d.Day() == last)
+	}}
+}
+
+// Next returns the first time after t that the schedule runs, in t's
+// location.
+func (s *calendarSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	if s.loc != nil {
+		loc = s.loc
+	}
+	local := t.In(loc)
+	// Days are stepped at noon, which every day has exactly once.
+	day := time.Date(local.Year(), local.Month(), local.Day(), 12, 0, 0, 0, loc)
+	for i := 0; i < 63; i++ {
+		if s.matches(day) {
+			next := time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.min, 0, 0, loc)
+			if next.Hour() != s.hour || next.Minute() != s.min {
+				// The time falls in a daylight saving gap, and time.Date has
+				// normalized it to before the gap, by the offset in effect
+				// before it. Applying that offset to the wall time instead
+				// lands the same distance past the gap.
+				_, offset := next.Zone()
+				wall := time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.min, 0, 0, time.UTC)
+				next = wall.Add(-time.Duration(offset) * time.Second).In(loc)
+			}
+			if next.After(t) {
+				return next.In(t.Location())
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// ScheduleDaily calls fn at hour:min every day in loc, timed by c, as
+// scheduled by Daily. Stop the returned entry to stop it.
+func ScheduleDaily(c MockableClock, hour, min int, loc *time.Location, fn func()) *CronEntry {
+	return NewCron(c).AddSchedule(Daily(hour, min, loc), fn)
+}
+
+// ScheduleWeekly calls fn at hour:min every week on weekday in loc, timed by
+// c, as scheduled by Weekly.
+func ScheduleWeekly(c MockableClock, weekday time.Weekday, hour, min int, loc *time.Location, fn func()) *CronEntry {
+	return NewCron(c).AddSchedule(Weekly(weekday, hour, min, loc), fn)
+}
+
+// ScheduleMonthly calls fn at hour:min every month on day in loc, timed by
+// c, as scheduled by Monthly.
+func ScheduleMonthly(c MockableClock, day, hour, min int, loc *time.Location, fn func()) *CronEntry {
+	return NewCron(c).AddSchedule(Monthly(day, hour, min, loc), fn)
+}
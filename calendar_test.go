@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a daily schedule runs once a day across both daylight saving
+// transitions, late by the gap when its time doesn't exist.
+func TestScheduleDaily_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Date(2024, time.March, 9, 0, 0, 0, 0, ny))
+
+	var runs []string
+	entry := ScheduleDaily(mock, 2, 30, ny, func() {
+		runs = append(runs, mock.Now().In(ny).Format("Jan 2 15:04 MST"))
+	})
+	mock.Add(3 * 24 * time.Hour)
+	mock.Set(time.Date(2024, time.November, 2, 0, 0, 0, 0, ny))
+	runs = runs[:3]
+	mock.Add(2 * 24 * time.Hour)
+	entry.Stop()
+	mock.Add(24 * time.Hour)
+
+	assert.Equal(t, []string{
+		"Mar 9 02:30 EST", "Mar 10 03:30 EDT", "Mar 11 02:30 EDT",
+		"Nov 2 02:30 EDT", "Nov 3 02:30 EST",
+	}, runs)
+}
+
+// Ensure that a time that occurs twice runs only the first time.
+func TestDaily_Ambiguous(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := Daily(1, 30, ny)
+	first := s.Next(time.Date(2024, time.November, 3, 0, 0, 0, 0, ny))
+	assert.Equal(t, "Nov 3 01:30 EDT", first.Format("Jan 2 15:04 MST"))
+	assert.Equal(t, "Nov 4 01:30 EST", s.Next(first).Format("Jan 2 15:04 MST"))
+}
+
+// Ensure that weekly and monthly schedules pick the right days.
+func TestWeekly_Monthly(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC) // a Wednesday
+	assert.Equal(t, time.Date(2024, time.February, 5, 9, 0, 0, 0, time.UTC),
+		Weekly(time.Monday, 9, 0, nil).Next(start))
+	assert.Equal(t, time.Date(2024, time.January, 31, 18, 0, 0, 0, time.UTC),
+		Weekly(time.Wednesday, 18, 0, nil).Next(start))
+
+	monthly := Monthly(31, 9, 0, time.UTC)
+	next := monthly.Next(start)
+	assert.Equal(t, time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC), monthly.Next(next))
+}
@@ -0,0 +1,51 @@
+package clock
+
+import "time"
+
+// RecvTimeout receives from ch, giving up after d on c. It returns the value
+// and true if one was received, or the zero value and false if the wait
+// timed out or ch was closed. It replaces a select on ch and time.After(d),
+// and on a mock starts one timer, so the timeout can be tested by advancing
+// the mock. A non-positive d doesn't wait, or start a timer, at all.
+func RecvTimeout[T any](c MockableClock, ch <-chan T, d time.Duration) (T, bool) {
+	if d <= 0 {
+		select {
+		case v, ok := <-ch:
+			return v, ok
+		default:
+			var zero T
+			return zero, false
+		}
+	}
+	t := c.NewTimer(d)
+	defer t.Stop()
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-t.C:
+		var zero T
+		return zero, false
+	}
+}
+
+// SendTimeout sends v on ch, giving up after d on c, and reports whether v
+// was sent. Like RecvTimeout, it starts one timer on a mock, and none if d
+// is not positive.
+func SendTimeout[T any](c MockableClock, ch chan<- T, v T, d time.Duration) bool {
+	if d <= 0 {
+		select {
+		case ch <- v:
+			return true
+		default:
+			return false
+		}
+	}
+	t := c.NewTimer(d)
+	defer t.Stop()
+	select {
+	case ch <- v:
+		return true
+	case <-t.C:
+		return false
+	}
+}
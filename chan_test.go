@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecvTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ch := make(chan int, 1)
+
+	ch <- 1
+	mock.ExpectStarts(1)
+	v, ok := RecvTimeout(mock, ch, time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	_, ok = RecvTimeout(mock, ch, 0)
+	assert.False(t, ok)
+
+	done := make(chan bool)
+	mock.ExpectStarts(1)
+	go func() {
+		_, ok := RecvTimeout(mock, ch, time.Second)
+		done <- ok
+	}()
+	mock.Add(time.Second, WaitBefore)
+	assert.False(t, <-done)
+
+	close(ch)
+	mock.ExpectStarts(1)
+	_, ok = RecvTimeout(mock, ch, time.Second)
+	assert.False(t, ok)
+}
+
+func TestSendTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ch := make(chan int, 1)
+
+	mock.ExpectStarts(1)
+	assert.True(t, SendTimeout(mock, ch, 1, time.Second))
+	assert.False(t, SendTimeout(mock, ch, 2, 0))
+
+	done := make(chan bool)
+	mock.ExpectStarts(1)
+	go func() { done <- SendTimeout(mock, ch, 2, time.Second) }()
+	mock.Add(time.Second, WaitBefore)
+	assert.False(t, <-done)
+
+	mock.ExpectStarts(1)
+	go func() { done <- SendTimeout(mock, ch, 3, time.Second) }()
+	mock.Wait()
+	assert.Equal(t, 1, <-ch)
+	assert.True(t, <-done)
+	assert.Equal(t, 3, <-ch)
+}
@@ -1,6 +1,7 @@
 package clock
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 )
@@ -11,6 +12,10 @@ type Checkpoint interface {
 	Add(delta int)
 	Done()
 	Wait()
+	// Outstanding returns how many Done calls are still expected.
+	Outstanding() int
+	// Expected returns the total added since Wait last returned.
+	Expected() int
 }
 
 // OptionalCheckpoint provides waitgroup-like functionality with assistance
@@ -18,6 +23,10 @@ type Checkpoint interface {
 type OptionalCheckpoint struct {
 	name        CheckpointName
 	outstanding chan int
+
+	mu       sync.Mutex // guards the counts below, which mirror outstanding
+	pending  int
+	expected int
 }
 
 func NewOptionalCheckPoint(name CheckpointName) *OptionalCheckpoint {
@@ -30,10 +39,17 @@ func NewOptionalCheckPoint(name CheckpointName) *OptionalCheckpoint {
 }
 
 func (s *OptionalCheckpoint) Add(delta int) {
+	s.mu.Lock()
+	s.pending += delta
+	s.expected += delta
+	s.mu.Unlock()
 	s.updateOutstanding(delta)
 }
 
 func (s *OptionalCheckpoint) Done() {
+	s.mu.Lock()
+	s.pending--
+	s.mu.Unlock()
 	s.updateOutstanding(-1)
 }
 
@@ -43,6 +59,29 @@ func (s *OptionalCheckpoint) Wait() {
 		update := <-s.outstanding
 		os += update
 	}
+	s.reset()
+}
+
+// Outstanding returns how many Done calls are still expected. It is
+// negative if Done has been called more times than expected.
+func (s *OptionalCheckpoint) Outstanding() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+func (s *OptionalCheckpoint) Expected() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expected
+}
+
+// reset ends a wait that has been satisfied.
+func (s *OptionalCheckpoint) reset() {
+	s.mu.Lock()
+	s.pending = 0
+	s.expected = 0
+	s.mu.Unlock()
 	s.outstanding <- 0
 }
 
@@ -50,6 +89,12 @@ func (s *OptionalCheckpoint) String() string {
 	return string(s.name)
 }
 
+// Verify fails tb if Done has been called fewer times than expected.
+func (s *OptionalCheckpoint) Verify(tb testing.TB) {
+	tb.Helper()
+	verify(tb, s.name, s.Outstanding())
+}
+
 func (s *OptionalCheckpoint) updateOutstanding(delta int) {
 	for {
 		select {
@@ -67,11 +112,13 @@ func (s *OptionalCheckpoint) updateOutstanding(delta int) {
 // FailOnUnexpectedCheckpoint extends SimpleSyncPoint so that excess calls do Done fail a
 // test.
 type FailOnUnexpectedCheckpoint struct {
-	name     CheckpointName
-	mu       sync.Mutex
-	wg       sync.WaitGroup
-	expected int
-	t        *testing.T
+	name        CheckpointName
+	mu          sync.Mutex
+	wg          sync.WaitGroup
+	outstanding int
+	expected    int
+	t           *testing.T
+	fn          func(CheckpointName) // called instead of failing t, if set
 }
 
 func NewFailOnUnexpectedCheckpoint(name CheckpointName, t *testing.T) *FailOnUnexpectedCheckpoint {
@@ -83,23 +130,42 @@ func NewFailOnUnexpectedCheckpoint(name CheckpointName, t *testing.T) *FailOnUne
 	}
 }
 
+// NewFailOnUnexpectedCheckpointFunc returns a checkpoint that calls fn with
+// its name on each unexpected call to Done, instead of failing a test, for
+// use outside of go test. PanicOnUnexpected is a ready-made fn.
+func NewFailOnUnexpectedCheckpointFunc(name CheckpointName, fn func(CheckpointName)) *FailOnUnexpectedCheckpoint {
+	return &FailOnUnexpectedCheckpoint{name: name, fn: fn}
+}
+
+// PanicOnUnexpected panics about an unexpected event on the named
+// checkpoint.
+func PanicOnUnexpected(name CheckpointName) {
+	panic(fmt.Sprintf("unexpected %v event", name))
+}
+
 func (t *FailOnUnexpectedCheckpoint) Add(delta int) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	t.outstanding = t.outstanding + delta
 	t.expected = t.expected + delta
 	t.wg.Add(delta)
 }
 
 func (t *FailOnUnexpectedCheckpoint) Done() {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-	if t.expected <= 0 {
+	if t.outstanding <= 0 {
+		t.mu.Unlock()
+		if t.fn != nil {
+			t.fn(t.name)
+			return
+		}
 		t.t.Helper()
 		t.t.Errorf("unexpected %v event", t.name)
 		return
 	}
-	t.expected--
+	t.outstanding--
 	t.wg.Done()
+	t.mu.Unlock()
 }
 
 func (t *FailOnUnexpectedCheckpoint) Wait() {
@@ -107,9 +173,121 @@ func (t *FailOnUnexpectedCheckpoint) Wait() {
 	t.wg.Wait()
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	t.outstanding = 0
 	t.expected = 0
 }
 
+func (t *FailOnUnexpectedCheckpoint) Outstanding() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.outstanding
+}
+
+func (t *FailOnUnexpectedCheckpoint) Expected() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.expected
+}
+
+// Verify fails tb if Done has been called fewer times than expected.
+func (t *FailOnUnexpectedCheckpoint) Verify(tb testing.TB) {
+	tb.Helper()
+	verify(tb, t.name, t.Outstanding())
+}
+
 func (t *FailOnUnexpectedCheckpoint) String() string {
 	return string(t.name)
 }
+
+// CheckpointGroup aggregates child checkpoints, such as one per cooperating
+// timer consumer, so they can be expected and waited on together. Groups can
+// contain groups.
+type CheckpointGroup struct {
+	name     CheckpointName
+	children []Checkpoint
+}
+
+func NewCheckpointGroup(name CheckpointName, children ...Checkpoint) *CheckpointGroup {
+	return &CheckpointGroup{name: name, children: children}
+}
+
+// Add adds delta to every child.
+func (g *CheckpointGroup) Add(delta int) {
+	for _, cp := range g.children {
+		cp.Add(delta)
+	}
+}
+
+// Done calls Done on every child.
+func (g *CheckpointGroup) Done() {
+	for _, cp := range g.children {
+		cp.Done()
+	}
+}
+
+// Wait blocks until every child's wait is satisfied.
+func (g *CheckpointGroup) Wait() {
+	for _, cp := range g.children {
+		cp.Wait()
+	}
+}
+
+// Outstanding returns the sum of the children's outstanding counts.
+func (g *CheckpointGroup) Outstanding() int {
+	n := 0
+	for _, cp := range g.children {
+		n += cp.Outstanding()
+	}
+	return n
+}
+
+// Expected returns the sum of the children's expected counts.
+func (g *CheckpointGroup) Expected() int {
+	n := 0
+	for _, cp := range g.children {
+		n += cp.Expected()
+	}
+	return n
+}
+
+// Verify fails tb for each child that has had Done called fewer times than
+// expected.
+func (g *CheckpointGroup) Verify(tb testing.TB) {
+	tb.Helper()
+	verifyAll(tb, g.children)
+}
+
+func (g *CheckpointGroup) String() string {
+	return string(g.name)
+}
+
+// VerifyAtCleanup verifies each checkpoint when tb and its subtests finish,
+// so that a Done call that never arrived fails the test, rather than going
+// unnoticed because nothing waited for it.
+func VerifyAtCleanup(tb testing.TB, cps ...Checkpoint) {
+	tb.Cleanup(func() { verifyAll(tb, cps) })
+}
+
+// verifier is implemented by checkpoints with their own Verify, such as
+// groups, which verify each of their children.
+type verifier interface {
+	Verify(tb testing.TB)
+}
+
+func verifyAll(tb testing.TB, cps []Checkpoint) {
+	tb.Helper()
+	for _, cp := range cps {
+		if v, ok := cp.(verifier); ok {
+			v.Verify(tb)
+			continue
+		}
+		verify(tb, checkpointName(cp), cp.Outstanding())
+	}
+}
+
+func verify(tb testing.TB, name CheckpointName, outstanding int) {
+	tb.Helper()
+	if outstanding > 0 {
+		tb.Errorf("%v: %d expected Done calls never arrived", name, outstanding)
+	}
+}
@@ -97,3 +97,104 @@ func TestFailOnUnexpectedCheckpoint(t *testing.T) {
 	assert.Equal(t, true, called, "wait did not block")
 	assert.False(t, experiment.Failed(), "failure without unexpected")
 }
+
+func TestFailOnUnexpectedCheckpointFunc(t *testing.T) {
+	var unexpected []CheckpointName
+	cp := NewFailOnUnexpectedCheckpointFunc(testCheckpoint, func(name CheckpointName) {
+		unexpected = append(unexpected, name)
+	})
+
+	cp.Add(1)
+	cp.Done()
+	cp.Wait()
+	assert.Empty(t, unexpected)
+
+	cp.Done()
+	assert.Equal(t, []CheckpointName{testCheckpoint}, unexpected)
+
+	cp = NewFailOnUnexpectedCheckpointFunc(testCheckpoint, PanicOnUnexpected)
+	assert.PanicsWithValue(t, "unexpected TestCheckpoint event", cp.Done)
+}
+
+func TestCheckpoint_Verify(t *testing.T) {
+	experiment := &testing.T{}
+	cp := NewOptionalCheckPoint(testCheckpoint)
+	cp.Add(2)
+	cp.Done()
+	cp.Verify(experiment)
+	assert.True(t, experiment.Failed(), "lack of failure on missing Done")
+
+	experiment = &testing.T{}
+	cp.Done()
+	cp.Verify(experiment)
+	assert.False(t, experiment.Failed(), "failure without missing Done")
+
+	experiment = &testing.T{}
+	strict := NewFailOnUnexpectedCheckpoint(testCheckpoint, experiment)
+	strict.Add(1)
+	strict.Verify(experiment)
+	assert.True(t, experiment.Failed(), "lack of failure on missing Done")
+}
+
+func TestVerifyAtCleanup(t *testing.T) {
+	experiment := &recordingTB{TB: t}
+	cp := NewOptionalCheckPoint(testCheckpoint)
+	VerifyAtCleanup(experiment, cp)
+	cp.Add(1)
+	experiment.runCleanups()
+	assert.Equal(t, []string{"TestCheckpoint: 1 expected Done calls never arrived"}, experiment.logs)
+}
+
+func TestCheckpoint_Outstanding(t *testing.T) {
+	for _, cp := range []Checkpoint{
+		NewOptionalCheckPoint(testCheckpoint),
+		NewFailOnUnexpectedCheckpoint(testCheckpoint, &testing.T{}),
+	} {
+		cp.Add(3)
+		cp.Done()
+		assert.Equal(t, 2, cp.Outstanding())
+		assert.Equal(t, 3, cp.Expected())
+
+		// counts can be read while a wait is blocked
+		waited := make(chan struct{})
+		go func() {
+			cp.Wait()
+			close(waited)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 2, cp.Outstanding())
+		cp.Done()
+		cp.Done()
+		<-waited
+		assert.Equal(t, 0, cp.Outstanding())
+		assert.Equal(t, 0, cp.Expected())
+	}
+}
+
+func TestCheckpointGroup(t *testing.T) {
+	producer := NewOptionalCheckPoint("producer")
+	consumer := NewOptionalCheckPoint("consumer")
+	inner := NewCheckpointGroup("inner", consumer)
+	group := NewCheckpointGroup("group", producer, inner)
+
+	group.Add(1)
+	assert.Equal(t, 1, producer.Outstanding())
+	assert.Equal(t, 1, consumer.Outstanding())
+	assert.Equal(t, 2, group.Outstanding())
+	assert.Equal(t, 2, group.Expected())
+
+	experiment := &recordingTB{TB: t}
+	producer.Done()
+	group.Verify(experiment)
+	assert.Equal(t, []string{"consumer: 1 expected Done calls never arrived"}, experiment.logs)
+
+	var waited bool
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		waited = true
+		consumer.Done()
+	}()
+	group.Wait()
+	assert.True(t, waited, "wait did not block")
+	assert.Equal(t, 0, group.Outstanding())
+}
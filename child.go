@@ -0,0 +1,46 @@
+package clock
+
+import (
+	"math"
+	"time"
+)
+
+// Child returns a clock that is advanced along with the mock, but reads
+// offset ahead of it and runs scale times as fast, counting from the mock's
+// current time. Several children of one mock simulate nodes that observe
+// related but different times, driven from a single test. A scale of 1.001
+// gains a millisecond every second.
+//
+// Durations given to the child's timers, tickers and sleeps are measured on
+// the child, so they fire when the child's time reaches their deadlines. To
+// keep delivery as synchronous as the mock's own, the values they send are
+// the mock's times at which they fired, not the child's. Simulation models
+// nodes as independent mocks instead, whose timers deliver their own times.
+func (m *UnsynchronizedMock) Child(offset time.Duration, scale float64) MockableClock {
+	if scale <= 0 {
+		panic("non-positive scale for Child")
+	}
+	origin := m.current()
+	return Chain(m, ClockMiddleware{
+		OnNow: func(t time.Time) time.Time {
+			return origin.Add(offset + time.Duration(float64(t.Sub(origin))*scale))
+		},
+		OnSleep: func(d time.Duration) time.Duration {
+			return unscale(d, scale)
+		},
+		OnTimerCreate: func(kind TimerKind, d time.Duration) time.Duration {
+			return unscale(d, scale)
+		},
+	})
+}
+
+// unscale converts d on a child running scale times as fast to a duration
+// on its parent. A duration too long to convert saturates, so that a child
+// slower than its parent doesn't turn it into one that has already passed.
+func unscale(d time.Duration, scale float64) time.Duration {
+	f := float64(d) / scale
+	if f >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(f)
+}
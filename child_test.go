@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that children follow the parent's advances at their own offset and
+// rate, with their timers measured on their own time.
+func TestMock_Child(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ahead := mock.Child(time.Minute, 1)
+	fast := mock.Child(0, 2)
+	timer := fast.NewTimer(10 * time.Second)
+
+	mock.Add(5 * time.Second)
+	assert.Equal(t, time.Unix(65, 0), ahead.Now())
+	assert.Equal(t, time.Unix(10, 0), fast.Now())
+	assert.Equal(t, 5*time.Second, ahead.Since(time.Unix(60, 0)))
+	assert.Equal(t, time.Unix(5, 0), <-timer.C)
+
+	assert.PanicsWithValue(t, "non-positive scale for Child", func() { mock.Child(0, 0) })
+}
+
+// Ensure that a slow child's longest timers don't overflow into ones that
+// fire at once.
+func TestMock_Child_HugeDuration(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	slow := mock.Child(0, 0.5)
+	timer := slow.NewTimer(math.MaxInt64)
+
+	mock.Add(time.Nanosecond)
+	select {
+	case v := <-timer.C:
+		t.Fatalf("timer fired at %v", v)
+	default:
+	}
+	assert.True(t, timer.Stop())
+}
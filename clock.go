@@ -1,6 +1,7 @@
 package clock
 
 import (
+	"context"
 	"time"
 )
 
@@ -9,21 +10,56 @@ import (
 // is a real-time clock which simply wraps the time package's functions. The
 // second is a mock clock which will only change when
 // programmatically adjusted.
+//
+// MockableClock is composed of smaller interfaces, so that code which only
+// needs part of it can accept, and be tested with, a narrower one. Timers and
+// tickers from other implementations can be returned using WrapTimer and
+// WrapTicker.
 type MockableClock interface {
-	After(d time.Duration) <-chan time.Time
-	AfterFunc(d time.Duration, f func()) MockableTimer
+	NowClock
+	SleepClock
+	TimerClock
+	TickerClock
+}
+
+// NowClock is the part of MockableClock that reads the time.
+type NowClock interface {
 	Now() time.Time
 	Since(t time.Time) time.Duration
+}
+
+// SleepClock is the part of MockableClock that blocks the caller.
+type SleepClock interface {
 	Sleep(d time.Duration)
+	SleepContext(ctx context.Context, d time.Duration) error
+	SleepUntil(t time.Time)
+}
+
+// TimerClock is the part of MockableClock that creates single events.
+type TimerClock interface {
+	After(d time.Duration) <-chan time.Time
+	AfterAt(t time.Time) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) MockableTimer
+	AtFunc(t time.Time, f func()) MockableTimer
+	NewTimer(d time.Duration) *Timer
+}
+
+// TickerClock is the part of MockableClock that creates repeating events.
+type TickerClock interface {
 	Tick(d time.Duration) <-chan time.Time
 	NewTicker(d time.Duration) *Ticker
-	NewTimer(d time.Duration) *Timer
+	NewAlignedTicker(d time.Duration) *Ticker
 }
 
 // MockableTimer is an interface replacement for *time.Timer that can be mocked
 type MockableTimer interface {
 	Stop() bool
 	Reset(d time.Duration) bool
+	// Reschedule changes the timer to expire at t.
+	Reschedule(t time.Time) bool
+	// Chan returns the channel the timer fires on, which is nil for timers
+	// created by AfterFunc.
+	Chan() <-chan time.Time
 }
 
 // clock implements a real-time clock by simply wrapping the time package functions.
@@ -36,23 +72,43 @@ func SetSystemClock(clock MockableClock) {
 }
 
 func After(d time.Duration) <-chan time.Time            { return systemClock.After(d) }
+func AfterAt(t time.Time) <-chan time.Time              { return systemClock.AfterAt(t) }
 func AfterFunc(d time.Duration, f func()) MockableTimer { return systemClock.AfterFunc(d, f) }
+func AtFunc(t time.Time, f func()) MockableTimer        { return systemClock.AtFunc(t, f) }
 func Now() time.Time                                    { return systemClock.Now() }
 func Since(t time.Time) time.Duration                   { return systemClock.Since(t) }
 func Sleep(d time.Duration)                             { systemClock.Sleep(d) }
+func SleepUntil(t time.Time)                            { systemClock.SleepUntil(t) }
 func Tick(d time.Duration) <-chan time.Time             { return systemClock.Tick(d) }
 func NewTicker(d time.Duration) *Ticker                 { return systemClock.NewTicker(d) }
+func NewAlignedTicker(d time.Duration) *Ticker          { return systemClock.NewAlignedTicker(d) }
 func NewTimer(d time.Duration) *Timer                   { return systemClock.NewTimer(d) }
 
+func SleepContext(ctx context.Context, d time.Duration) error {
+	return systemClock.SleepContext(ctx, d)
+}
+
 // New returns an instance of a real-time clock.
 func New() MockableClock {
 	return &clock{}
 }
 
+// IsRealtime reports whether c is a real-time clock returned by New.
+func IsRealtime(c MockableClock) bool {
+	_, ok := c.(*clock)
+	return ok
+}
+
 func (c *clock) After(d time.Duration) <-chan time.Time { return time.After(d) }
 
+func (c *clock) AfterAt(t time.Time) <-chan time.Time { return time.After(time.Until(t)) }
+
 func (c *clock) AfterFunc(d time.Duration, f func()) MockableTimer {
-	return &Timer{timer: time.AfterFunc(d, f)}
+	return WrapTimer(nil, time.AfterFunc(d, f))
+}
+
+func (c *clock) AtFunc(t time.Time, f func()) MockableTimer {
+	return WrapTimer(nil, time.AfterFunc(time.Until(t), f))
 }
 
 func (c *clock) Now() time.Time { return time.Now() }
@@ -61,14 +117,37 @@ func (c *clock) Since(t time.Time) time.Duration { return time.Since(t) }
 
 func (c *clock) Sleep(d time.Duration) { time.Sleep(d) }
 
+func (c *clock) SleepUntil(t time.Time) { time.Sleep(time.Until(t)) }
+
+func (c *clock) SleepContext(ctx context.Context, d time.Duration) error {
+	t := AcquireTimer(c, d)
+	defer ReleaseTimer(t)
+	return sleepContext(ctx, t)
+}
+
 func (c *clock) Tick(d time.Duration) <-chan time.Time { return time.Tick(d) }
 
 func (c *clock) NewTicker(d time.Duration) *Ticker {
 	t := time.NewTicker(d)
-	return &Ticker{C: t.C, ticker: t}
+	return WrapTicker(t.C, t)
+}
+
+func (c *clock) NewAlignedTicker(d time.Duration) *Ticker {
+	return AlignTicker(c, d)
 }
 
 func (c *clock) NewTimer(d time.Duration) *Timer {
 	t := time.NewTimer(d)
-	return &Timer{C: t.C, timer: t}
+	return WrapTimer(t.C, t)
+}
+
+// sleepContext waits for t to fire or ctx to be done, whichever is first.
+func sleepContext(ctx context.Context, t *Timer) error {
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
 }
@@ -1,6 +1,7 @@
 package clock
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -94,6 +95,47 @@ func TestClock_Sleep(t *testing.T) {
 	}
 }
 
+// Ensure that the clock's SleepContext returns early when the context is done.
+func TestClock_SleepContext(t *testing.T) {
+	if err := New().SleepContext(context.Background(), 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := New().SleepContext(ctx, time.Hour); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("too late")
+	}
+}
+
+// Ensure that the clock's AfterAt and SleepUntil wake at an absolute time.
+func TestClock_AfterAt(t *testing.T) {
+	deadline := time.Now().Add(20 * time.Millisecond)
+	if now := <-New().AfterAt(deadline); now.Before(deadline) {
+		t.Fatal("too early")
+	}
+
+	deadline = time.Now().Add(20 * time.Millisecond)
+	New().SleepUntil(deadline)
+	if time.Now().Before(deadline) {
+		t.Fatal("too early")
+	}
+}
+
+// Ensure that the clock's AtFunc runs at an absolute time.
+func TestClock_AtFunc(t *testing.T) {
+	deadline := time.Now().Add(20 * time.Millisecond)
+	done := make(chan time.Time)
+	New().AtFunc(deadline, func() { done <- time.Now() })
+	if now := <-done; now.Before(deadline) {
+		t.Fatal("too early")
+	}
+}
+
 // Ensure that the clock ticks correctly.
 func TestClock_Tick(t *testing.T) {
 	var ok bool
@@ -264,5 +306,42 @@ func TestClock_Timer_Reset_Unlock(t *testing.T) {
 	wg.Wait()
 }
 
+// fixedClock implements only NowClock.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                  { return time.Time(c) }
+func (c fixedClock) Since(t time.Time) time.Duration { return time.Time(c).Sub(t) }
+
+// stubTimer records calls made to a wrapped timer.
+type stubTimer struct{ stops, resets int }
+
+func (s *stubTimer) Stop() bool                 { s.stops++; return true }
+func (s *stubTimer) Reset(d time.Duration) bool { s.resets++; return false }
+
+// Ensure that narrow interfaces can be stubbed on their own, and that
+// wrapped timers delegate to their backend.
+func TestClock_NarrowInterfaces(t *testing.T) {
+	var now NowClock = fixedClock(time.Unix(100, 0))
+	if d := now.Since(time.Unix(40, 0)); d != time.Minute {
+		t.Fatalf("unexpected duration: %v", d)
+	}
+
+	var full MockableClock = New()
+	var _ NowClock = full
+	var _ SleepClock = full
+	var _ TimerClock = full
+	var _ TickerClock = full
+
+	stub := &stubTimer{}
+	c := make(chan time.Time)
+	timer := WrapTimer(c, stub)
+	if !timer.Stop() || timer.Reset(time.Second) {
+		t.Fatal("results not passed through")
+	}
+	if stub.stops != 1 || stub.resets != 1 {
+		t.Fatalf("unexpected calls: %+v", stub)
+	}
+}
+
 func warn(v ...interface{})              { fmt.Fprintln(os.Stderr, v...) }
 func warnf(msg string, v ...interface{}) { fmt.Fprintf(os.Stderr, msg+"\n", v...) }
@@ -0,0 +1,107 @@
+// Package clockassert provides test assertions about timers on a mock clock.
+// Each one drives the mock itself, stepping from one pending deadline to the
+// next, so tests don't need to advance the clock and synchronize with it by
+// hand.
+//
+// Like testify's assertions, they report failures with t.Errorf and return
+// whether they passed.
+//
+// The channels passed to them must be fed synchronously by the mock's
+// advance: the C of a timer or ticker made by it, or a channel an AfterFunc
+// callback sends on, without the AsyncAfterFuncs option. A channel fed by
+// another goroutine, such as one that forwards from a decorated clock, may
+// not have received by the time an assertion checks it, and the assertions
+// don't wait for it.
+package clockassert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// FiredWithin advances mock by up to d and asserts that ch receives a value
+// by then. It stops advancing as soon as ch receives.
+func FiredWithin(t testing.TB, mock clock.Controller, ch <-chan time.Time, d time.Duration) bool {
+	t.Helper()
+	fired := false
+	walk(mock, d, func() bool {
+		fired = received(ch) > 0
+		return !fired
+	})
+	if !fired {
+		t.Errorf("channel did not receive within %v of virtual time", d)
+	}
+	return fired
+}
+
+// NeverFires advances mock by d and asserts that ch receives nothing
+// meanwhile.
+func NeverFires(t testing.TB, mock clock.Controller, ch <-chan time.Time, d time.Duration) bool {
+	t.Helper()
+	var at time.Time
+	walk(mock, d, func() bool {
+		if received(ch) > 0 {
+			at = mock.Now()
+			return false
+		}
+		return true
+	})
+	if !at.IsZero() {
+		t.Errorf("channel received at %v, within %v of virtual time", at, d)
+		return false
+	}
+	return true
+}
+
+// TickCount advances mock by d and asserts that ticker ticks want times
+// meanwhile. It receives the ticks itself, stepping to each deadline in turn
+// so that none are dropped for want of a receiver.
+func TickCount(t testing.TB, mock clock.Controller, ticker *clock.Ticker, d time.Duration, want int) bool {
+	t.Helper()
+	got := 0
+	walk(mock, d, func() bool {
+		got += received(ticker.C)
+		return true
+	})
+	if got != want {
+		t.Errorf("ticker ticked %d times within %v of virtual time, want %d", got, d, want)
+		return false
+	}
+	return true
+}
+
+// walk advances mock by d, stopping at each pending deadline on the way to
+// call check, and stops early if check returns false.
+func walk(mock clock.Controller, d time.Duration, check func() bool) {
+	end := mock.Now().Add(d)
+	if !check() {
+		return
+	}
+	for now := mock.Now(); now.Before(end); now = mock.Now() {
+		next := end
+		for _, timer := range mock.PendingTimers() {
+			if timer.Deadline.After(now) && timer.Deadline.Before(next) {
+				next = timer.Deadline
+			}
+		}
+		mock.Add(next.Sub(now))
+		if !check() {
+			return
+		}
+	}
+}
+
+// received drains ch without blocking and returns how many values it held.
+func received(ch <-chan time.Time) int {
+	n := 0
+	for {
+		select {
+		case <-ch:
+			n++
+		default:
+			return n
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package clockassert
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTB captures failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// Ensure that FiredWithin stops advancing once the channel receives.
+func TestFiredWithin(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	start := mock.Now()
+	timer := mock.NewTimer(3 * time.Second)
+	mock.NewTicker(time.Second)
+
+	assert.True(t, FiredWithin(t, mock, timer.C, time.Minute))
+	assert.Equal(t, 3*time.Second, mock.Since(start))
+
+	rec := &recordingTB{TB: t}
+	assert.False(t, FiredWithin(rec, mock, mock.NewTimer(time.Hour).C, time.Minute))
+	assert.Len(t, rec.errors, 1)
+}
+
+// Ensure that NeverFires reports a channel that receives.
+func TestNeverFires(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	timer := mock.NewTimer(time.Minute)
+	assert.True(t, NeverFires(t, mock, timer.C, 59*time.Second))
+
+	rec := &recordingTB{TB: t}
+	assert.False(t, NeverFires(rec, mock, timer.C, time.Second))
+	assert.Len(t, rec.errors, 1)
+}
+
+// Ensure that TickCount counts every tick, even when the mock moves further
+// than the interval.
+func TestTickCount(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	ticker := mock.NewTicker(time.Second)
+	defer ticker.Stop()
+	assert.True(t, TickCount(t, mock, ticker, 10*time.Second, 10))
+
+	rec := &recordingTB{TB: t}
+	assert.False(t, TickCount(rec, mock, ticker, 2*time.Second, 3))
+	assert.Len(t, rec.errors, 1)
+}
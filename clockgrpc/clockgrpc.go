@@ -0,0 +1,216 @@
+// Package clockgrpc shares virtual time between test processes over gRPC, so
+// that multi-binary integration tests have a single notion of "now".
+//
+// One process, the coordinator, serves the VirtualTime service with a Server
+// wrapped around its own mock clock. Every other process calls Follow with its
+// mock, which is then set to the coordinator's time whenever it changes.
+// Advance and Set return only after every follower has applied the change, so
+// all the mocks move in lockstep.
+//
+// Interceptor provides client interceptors whose timeouts, retries and
+// hedging run on a clock, so a client's handling of slow and failing servers
+// can be tested in virtual time.
+package clockgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/kraney/clock"
+	"github.com/kraney/clock/clockgrpc/clockpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the VirtualTime service on top of the coordinator's mock.
+type Server struct {
+	clockpb.UnimplementedVirtualTimeServer
+
+	mu   sync.Mutex // serializes changes of time
+	mock clock.Controllable
+	seq  uint64 // sequence number of the last change
+
+	followersMu sync.Mutex
+	followers   map[*follower]struct{}
+}
+
+type follower struct {
+	name    string
+	updates chan *clockpb.FollowResponse
+	acks    chan uint64
+	done    chan struct{}
+}
+
+// NewServer returns a Server whose time is kept by m.
+func NewServer(m clock.Controllable) *Server {
+	return &Server{mock: m, followers: map[*follower]struct{}{}}
+}
+
+// Now returns the coordinator's current time.
+func (s *Server) Now(ctx context.Context, req *clockpb.NowRequest) (*clockpb.NowResponse, error) {
+	return &clockpb.NowResponse{Now: timestamppb.New(s.mock.Now())}, nil
+}
+
+// Advance moves the coordinator's mock forward, then waits for every
+// follower to do the same.
+func (s *Server) Advance(ctx context.Context, req *clockpb.AdvanceRequest) (*clockpb.NowResponse, error) {
+	if err := req.GetDuration().CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	d := req.GetDuration().AsDuration()
+	if d < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative duration")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mock.Add(d)
+	return s.broadcast(ctx)
+}
+
+// Set moves the coordinator's mock to the given time, then waits for every
+// follower to do the same.
+func (s *Server) Set(ctx context.Context, req *clockpb.SetRequest) (*clockpb.NowResponse, error) {
+	if err := req.GetTime().CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mock.Set(req.GetTime().AsTime())
+	return s.broadcast(ctx)
+}
+
+// broadcast sends the mock's time to every follower and waits for them to
+// acknowledge it. It must be called with mu held.
+func (s *Server) broadcast(ctx context.Context) (*clockpb.NowResponse, error) {
+	s.seq++
+	now := timestamppb.New(s.mock.Now())
+	update := &clockpb.FollowResponse{Seq: s.seq, Now: now}
+
+	s.followersMu.Lock()
+	followers := make([]*follower, 0, len(s.followers))
+	for f := range s.followers {
+		followers = append(followers, f)
+	}
+	s.followersMu.Unlock()
+
+	for _, f := range followers {
+		select {
+		case f.updates <- update:
+		case <-f.done:
+		}
+	}
+	for _, f := range followers {
+		if err := f.wait(ctx, s.seq); err != nil {
+			return nil, status.Errorf(status.FromContextError(err).Code(), "follower %q: %v", f.name, err)
+		}
+	}
+	return &clockpb.NowResponse{Now: now}, nil
+}
+
+// wait blocks until the follower acknowledges seq or leaves.
+func (f *follower) wait(ctx context.Context, seq uint64) error {
+	for {
+		select {
+		case ack := <-f.acks:
+			if ack >= seq {
+				return nil
+			}
+		case <-f.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Follow streams changes of time to a follower until it disconnects.
+func (s *Server) Follow(stream clockpb.VirtualTime_FollowServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	f := &follower{
+		name:    req.GetName(),
+		updates: make(chan *clockpb.FollowResponse, 1),
+		acks:    make(chan uint64, 1),
+		done:    make(chan struct{}),
+	}
+
+	// Register under mu, so the follower starts from the current time and
+	// sees every later change.
+	s.mu.Lock()
+	f.updates <- &clockpb.FollowResponse{Seq: s.seq, Now: timestamppb.New(s.mock.Now())}
+	s.followersMu.Lock()
+	s.followers[f] = struct{}{}
+	s.followersMu.Unlock()
+	s.mu.Unlock()
+
+	defer func() {
+		s.followersMu.Lock()
+		delete(s.followers, f)
+		s.followersMu.Unlock()
+		close(f.done)
+	}()
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case <-f.acks:
+			default:
+			}
+			f.acks <- req.GetAck()
+		}
+	}()
+
+	for {
+		select {
+		case update := <-f.updates:
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Follow keeps m in step with the coordinator reached through client, until
+// ctx is done or the stream fails. name identifies this process in the
+// coordinator's errors. Follow blocks, so it is usually run on its own
+// goroutine.
+func Follow(ctx context.Context, client clockpb.VirtualTimeClient, name string, m clock.Controllable) error {
+	stream, err := client.Follow(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&clockpb.FollowRequest{Name: name}); err != nil {
+		return err
+	}
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if now := update.GetNow().AsTime(); !now.Equal(m.Now()) {
+			m.Set(now)
+		}
+		if err := stream.Send(&clockpb.FollowRequest{Ack: update.GetSeq()}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package clockgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/kraney/clock/clockgrpc/clockpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func serve(t *testing.T, s *Server) clockpb.VirtualTimeClient {
+	return dial(t, s)
+}
+
+// dial serves s over an in-memory connection and returns a client for it.
+func dial(t *testing.T, s clockpb.VirtualTimeServer, opts ...grpc.DialOption) clockpb.VirtualTimeClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	clockpb.RegisterVirtualTimeServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return clockpb.NewVirtualTimeClient(conn)
+}
+
+// Ensure that followers move in lockstep with the coordinator.
+func TestServer_Follow(t *testing.T) {
+	coordinator := clock.NewUnsynchronizedMock()
+	client := serve(t, NewServer(coordinator))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	coordinator.Add(time.Minute)
+	followers := []*clock.UnsynchronizedMock{clock.NewUnsynchronizedMock(), clock.NewUnsynchronizedMock()}
+	for _, m := range followers {
+		go Follow(ctx, client, "follower", m)
+	}
+	// Followers start from the coordinator's time.
+	for _, m := range followers {
+		for !m.Now().Equal(time.Unix(60, 0)) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	fired := followers[0].After(time.Minute)
+	resp, err := client.Advance(ctx, &clockpb.AdvanceRequest{Duration: durationpb.New(time.Minute)})
+	assert.NoError(t, err)
+	assert.True(t, time.Unix(120, 0).Equal(resp.Now.AsTime()))
+	// Advance returns only once every follower has applied it.
+	for _, m := range followers {
+		assert.True(t, time.Unix(120, 0).Equal(m.Now()))
+	}
+	select {
+	case <-fired:
+	default:
+		t.Fatal("follower timer did not fire")
+	}
+
+	_, err = client.Set(ctx, &clockpb.SetRequest{Time: timestamppb.New(time.Unix(1000, 0))})
+	assert.NoError(t, err)
+	for _, m := range followers {
+		assert.True(t, time.Unix(1000, 0).Equal(m.Now()))
+	}
+
+	now, err := client.Now(ctx, &clockpb.NowRequest{})
+	assert.NoError(t, err)
+	assert.True(t, time.Unix(1000, 0).Equal(now.Now.AsTime()))
+}
+
+// Ensure that invalid changes of time are rejected.
+func TestServer_Advance_Negative(t *testing.T) {
+	client := serve(t, NewServer(clock.NewUnsynchronizedMock()))
+	_, err := client.Advance(context.Background(), &clockpb.AdvanceRequest{Duration: durationpb.New(-time.Second)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: clock.proto
+
+package clockpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VirtualTime_Now_FullMethodName     = "/kraney.clock.v1.VirtualTime/Now"
+	VirtualTime_Advance_FullMethodName = "/kraney.clock.v1.VirtualTime/Advance"
+	VirtualTime_Set_FullMethodName     = "/kraney.clock.v1.VirtualTime/Set"
+	VirtualTime_Follow_FullMethodName  = "/kraney.clock.v1.VirtualTime/Follow"
+)
+
+// VirtualTimeClient is the client API for VirtualTime service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VirtualTime shares one notion of virtual "now" between test processes. A
+// coordinator serves it, and each process follows it with its own mock clock.
+type VirtualTimeClient interface {
+	// Now returns the coordinator's current time.
+	Now(ctx context.Context, in *NowRequest, opts ...grpc.CallOption) (*NowResponse, error)
+	// Advance moves time forward by a duration, and returns once every
+	// follower has applied the change.
+	Advance(ctx context.Context, in *AdvanceRequest, opts ...grpc.CallOption) (*NowResponse, error)
+	// Set moves time to an instant, and returns once every follower has
+	// applied the change.
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*NowResponse, error)
+	// Follow streams every change of time to a follower, starting with the
+	// current time. The follower acknowledges each update once applied.
+	Follow(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FollowRequest, FollowResponse], error)
+}
+
+type virtualTimeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVirtualTimeClient(cc grpc.ClientConnInterface) VirtualTimeClient {
+	return &virtualTimeClient{cc}
+}
+
+func (c *virtualTimeClient) Now(ctx context.Context, in *NowRequest, opts ...grpc.CallOption) (*NowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NowResponse)
+	err := c.cc.Invoke(ctx, VirtualTime_Now_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualTimeClient) Advance(ctx context.Context, in *AdvanceRequest, opts ...grpc.CallOption) (*NowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NowResponse)
+	err := c.cc.Invoke(ctx, VirtualTime_Advance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualTimeClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*NowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NowResponse)
+	err := c.cc.Invoke(ctx, VirtualTime_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualTimeClient) Follow(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FollowRequest, FollowResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VirtualTime_ServiceDesc.Streams[0], VirtualTime_Follow_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FollowRequest, FollowResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VirtualTime_FollowClient = grpc.BidiStreamingClient[FollowRequest, FollowResponse]
+
+// VirtualTimeServer is the server API for VirtualTime service.
+// All implementations must embed UnimplementedVirtualTimeServer
+// for forward compatibility.
+//
+// VirtualTime shares one notion of virtual "now" between test processes. A
+// coordinator serves it, and each process follows it with its own mock clock.
+type VirtualTimeServer interface {
+	// Now returns the coordinator's current time.
+	Now(context.Context, *NowRequest) (*NowResponse, error)
+	// Advance moves time forward by a duration, and returns once every
+	// follower has applied the change.
+	Advance(context.Context, *AdvanceRequest) (*NowResponse, error)
+	// Set moves time to an instant, and returns once every follower has
+	// applied the change.
+	Set(context.Context, *SetRequest) (*NowResponse, error)
+	// Follow streams every change of time to a follower, starting with the
+	// current time. The follower acknowledges each update once applied.
+	Follow(grpc.BidiStreamingServer[FollowRequest, FollowResponse]) error
+	mustEmbedUnimplementedVirtualTimeServer()
+}
+
+// UnimplementedVirtualTimeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVirtualTimeServer struct{}
+
+func (UnimplementedVirtualTimeServer) Now(context.Context, *NowRequest) (*NowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Now not implemented")
+}
+func (UnimplementedVirtualTimeServer) Advance(context.Context, *AdvanceRequest) (*NowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Advance not implemented")
+}
+func (UnimplementedVirtualTimeServer) Set(context.Context, *SetRequest) (*NowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedVirtualTimeServer) Follow(grpc.BidiStreamingServer[FollowRequest, FollowResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Follow not implemented")
+}
+func (UnimplementedVirtualTimeServer) mustEmbedUnimplementedVirtualTimeServer() {}
+func (UnimplementedVirtualTimeServer) testEmbeddedByValue()                     {}
+
+// UnsafeVirtualTimeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VirtualTimeServer will
+// result in compilation errors.
+type UnsafeVirtualTimeServer interface {
+	mustEmbedUnimplementedVirtualTimeServer()
+}
+
+func RegisterVirtualTimeServer(s grpc.ServiceRegistrar, srv VirtualTimeServer) {
+	// If the following call pancis, it indicates UnimplementedVirtualTimeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VirtualTime_ServiceDesc, srv)
+}
+
+func _VirtualTime_Now_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualTimeServer).Now(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualTime_Now_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualTimeServer).Now(ctx, req.(*NowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualTime_Advance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdvanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualTimeServer).Advance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualTime_Advance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualTimeServer).Advance(ctx, req.(*AdvanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualTime_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualTimeServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VirtualTime_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualTimeServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VirtualTime_Follow_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VirtualTimeServer).Follow(&grpc.GenericServerStream[FollowRequest, FollowResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VirtualTime_FollowServer = grpc.BidiStreamingServer[FollowRequest, FollowResponse]
+
+// VirtualTime_ServiceDesc is the grpc.ServiceDesc for VirtualTime service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VirtualTime_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kraney.clock.v1.VirtualTime",
+	HandlerType: (*VirtualTimeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Now",
+			Handler:    _VirtualTime_Now_Handler,
+		},
+		{
+			MethodName: "Advance",
+			Handler:    _VirtualTime_Advance_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _VirtualTime_Set_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Follow",
+			Handler:       _VirtualTime_Follow_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "clock.proto",
+}
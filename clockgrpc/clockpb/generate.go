@@ -0,0 +1,5 @@
+// Package clockpb contains the protocol buffer and gRPC definitions of the
+// VirtualTime service.
+package clockpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative clock.proto
@@ -0,0 +1,211 @@
+package clockgrpc
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/kraney/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Interceptor provides client interceptors that apply timeouts, retries and
+// hedging to calls, measuring them all on a clock. With a mock clock, a
+// client's deadline, retry and hedging behavior can be tested by advancing
+// virtual time instead of waiting.
+//
+// Install it with grpc.WithUnaryInterceptor(i.Unary) and
+// grpc.WithStreamInterceptor(i.Stream).
+type Interceptor struct {
+	// Clock measures timeouts, hedging delays and waits between retries.
+	Clock clock.MockableClock
+
+	// Timeout, if positive, limits each attempt of a call, and for streams,
+	// the life of the stream. An attempt that times out fails with
+	// codes.DeadlineExceeded.
+	Timeout time.Duration
+
+	// Retry, if set, decides how long to wait between attempts and when to
+	// give up. Streams are only retried while they are being established.
+	Retry clock.BackoffPolicy
+	// RetryCodes lists the status codes that are retried. If empty, only
+	// codes.Unavailable is.
+	RetryCodes []codes.Code
+
+	// Hedge, if positive, starts a second attempt of a unary call if the
+	// first hasn't finished after this long. The first attempt to succeed,
+	// or to fail with a code that isn't retried, is used, and the other is
+	// canceled.
+	Hedge time.Duration
+}
+
+// Unary is a grpc.UnaryClientInterceptor.
+func (i *Interceptor) Unary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	call := func(ctx context.Context) error {
+		return i.hedged(ctx, reply, func(ctx context.Context, reply interface{}) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+	return i.retry(ctx, call)
+}
+
+// Stream is a grpc.StreamClientInterceptor.
+func (i *Interceptor) Stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	var stream grpc.ClientStream
+	err := i.retry(ctx, func(ctx context.Context) error {
+		if i.Timeout <= 0 {
+			var err error
+			stream, err = streamer(ctx, desc, cc, method, opts...)
+			return err
+		}
+		d := i.deadline(ctx)
+		s, err := streamer(d.ctx, desc, cc, method, opts...)
+		if err != nil {
+			d.stop()
+			return d.wrap(err)
+		}
+		stream = &deadlineStream{ClientStream: s, deadline: d}
+		return nil
+	})
+	return stream, err
+}
+
+func (i *Interceptor) retryable(err error) bool {
+	code := status.Code(err)
+	if len(i.RetryCodes) == 0 {
+		return code == codes.Unavailable
+	}
+	for _, c := range i.RetryCodes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Interceptor) retry(ctx context.Context, call func(ctx context.Context) error) error {
+	if i.Retry == nil {
+		return call(ctx)
+	}
+	return clock.RetryWith(ctx, i.Clock, i.Retry, func(ctx context.Context) error {
+		err := call(ctx)
+		if err != nil && !i.retryable(err) {
+			return clock.Permanent(err)
+		}
+		return err
+	})
+}
+
+// attempt makes one attempt of a unary call, within the timeout.
+func (i *Interceptor) attempt(ctx context.Context, call func(ctx context.Context) error) error {
+	if i.Timeout <= 0 {
+		return call(ctx)
+	}
+	d := i.deadline(ctx)
+	defer d.stop()
+	return d.wrap(call(d.ctx))
+}
+
+// hedged makes an attempt of a unary call, and another if the first is slow.
+// Each attempt decodes into its own reply, and the one used is copied into
+// reply.
+func (i *Interceptor) hedged(ctx context.Context, reply interface{}, call func(ctx context.Context, reply interface{}) error) error {
+	msg, ok := reply.(proto.Message)
+	if i.Hedge <= 0 || !ok {
+		return i.attempt(ctx, func(ctx context.Context) error { return call(ctx, reply) })
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+	results := make(chan result, 2)
+	start := func() {
+		r := msg.ProtoReflect().New().Interface()
+		go func() {
+			err := i.attempt(ctx, func(ctx context.Context) error { return call(ctx, r) })
+			results <- result{r, err}
+		}()
+	}
+
+	start()
+	running := 1
+	hedge := i.Clock.NewTimer(i.Hedge)
+	defer hedge.Stop()
+	hedgeC := hedge.C
+	for {
+		select {
+		case <-hedgeC:
+			hedgeC = nil
+			start()
+			running++
+		case res := <-results:
+			running--
+			if res.err != nil && i.retryable(res.err) && running > 0 {
+				continue
+			}
+			if res.err == nil {
+				proto.Reset(msg)
+				proto.Merge(msg, res.reply)
+			}
+			return res.err
+		}
+	}
+}
+
+// deadline cancels an attempt's context when its timer fires.
+type deadline struct {
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timer   clock.MockableTimer
+	fired   int32
+}
+
+func (i *Interceptor) deadline(ctx context.Context) *deadline {
+	d := &deadline{timeout: i.Timeout}
+	d.ctx, d.cancel = context.WithCancel(ctx)
+	d.timer = i.Clock.AfterFunc(i.Timeout, func() {
+		atomic.StoreInt32(&d.fired, 1)
+		d.cancel()
+	})
+	return d
+}
+
+func (d *deadline) stop() {
+	d.timer.Stop()
+	d.cancel()
+}
+
+// wrap reports err as codes.DeadlineExceeded if it came from the timer
+// firing. io.EOF, which streams use to signal their end, is left alone.
+func (d *deadline) wrap(err error) error {
+	if err != nil && err != io.EOF && atomic.LoadInt32(&d.fired) != 0 {
+		return status.Errorf(codes.DeadlineExceeded, "clockgrpc: call timed out after %v", d.timeout)
+	}
+	return err
+}
+
+// deadlineStream keeps a stream's timeout running until the stream ends.
+type deadlineStream struct {
+	grpc.ClientStream
+	deadline *deadline
+}
+
+func (s *deadlineStream) SendMsg(m interface{}) error {
+	return s.deadline.wrap(s.ClientStream.SendMsg(m))
+}
+
+func (s *deadlineStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.deadline.stop()
+	}
+	return s.deadline.wrap(err)
+}
@@ -0,0 +1,124 @@
+package clockgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/kraney/clock/clockgrpc/clockpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeServer answers Now with the result of now, counting calls, and holds
+// Follow streams open until they are canceled.
+type fakeServer struct {
+	clockpb.UnimplementedVirtualTimeServer
+	calls chan int
+	now   func(ctx context.Context, call int) (*clockpb.NowResponse, error)
+}
+
+func (s *fakeServer) Now(ctx context.Context, _ *clockpb.NowRequest) (*clockpb.NowResponse, error) {
+	call := <-s.calls
+	s.calls <- call + 1
+	return s.now(ctx, call)
+}
+
+func (s *fakeServer) Follow(stream clockpb.VirtualTime_FollowServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func dialIntercepted(t *testing.T, i *Interceptor, now func(ctx context.Context, call int) (*clockpb.NowResponse, error)) clockpb.VirtualTimeClient {
+	s := &fakeServer{calls: make(chan int, 1), now: now}
+	s.calls <- 0
+	return dial(t, s, grpc.WithUnaryInterceptor(i.Unary), grpc.WithStreamInterceptor(i.Stream))
+}
+
+func hang(ctx context.Context, _ int) (*clockpb.NowResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// Ensure that a call that hangs fails once the mock reaches the timeout.
+func TestInterceptor_Timeout(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	client := dialIntercepted(t, &Interceptor{Clock: mock, Timeout: 5 * time.Second}, hang)
+
+	errc := make(chan error)
+	go func() {
+		_, err := client.Now(context.Background(), &clockpb.NowRequest{})
+		errc <- err
+	}()
+	mock.ExpectStarts(1)
+	mock.Add(5*time.Second, clock.WaitBefore)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(<-errc))
+}
+
+// Ensure that unavailable servers are retried on the policy's schedule.
+func TestInterceptor_Retry(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	i := &Interceptor{Clock: mock, Retry: clock.ConstantBackoff{Interval: time.Second}}
+	client := dialIntercepted(t, i, func(ctx context.Context, call int) (*clockpb.NowResponse, error) {
+		if call < 2 {
+			return nil, status.Error(codes.Unavailable, "not yet")
+		}
+		return &clockpb.NowResponse{Now: timestamppb.New(time.Unix(int64(call), 0))}, nil
+	})
+
+	done := make(chan *clockpb.NowResponse)
+	go func() {
+		resp, err := client.Now(context.Background(), &clockpb.NowRequest{})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	for n := 0; n < 2; n++ {
+		mock.ExpectStarts(1)
+		mock.Add(time.Second, clock.WaitBefore)
+	}
+	assert.Equal(t, int64(2), (<-done).Now.Seconds)
+}
+
+// Ensure that a slow call is hedged, and the hedge's reply is used.
+func TestInterceptor_Hedge(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	client := dialIntercepted(t, &Interceptor{Clock: mock, Hedge: time.Second}, func(ctx context.Context, call int) (*clockpb.NowResponse, error) {
+		if call == 0 {
+			return hang(ctx, call)
+		}
+		return &clockpb.NowResponse{Now: timestamppb.New(time.Unix(42, 0))}, nil
+	})
+
+	done := make(chan *clockpb.NowResponse)
+	go func() {
+		resp, err := client.Now(context.Background(), &clockpb.NowRequest{})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	mock.ExpectStarts(1)
+	mock.Add(time.Second, clock.WaitBefore)
+	assert.Equal(t, int64(42), (<-done).Now.Seconds)
+}
+
+// Ensure that a stream is ended when the mock reaches the timeout.
+func TestInterceptor_StreamTimeout(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	client := dialIntercepted(t, &Interceptor{Clock: mock, Timeout: time.Minute}, hang)
+
+	stream, err := client.Follow(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error)
+	go func() {
+		_, err := stream.Recv()
+		errc <- err
+	}()
+	mock.Add(time.Minute)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(<-errc))
+	assert.Empty(t, mock.PendingTimers())
+}
@@ -0,0 +1,233 @@
+// Package clockhttp exposes a mock clock over HTTP, so that end-to-end tests
+// can drive the mock clock of a service from outside its process.
+//
+// The handler serves four operations, relative to wherever it is mounted:
+//
+//	GET  now      returns the mock's current time
+//	POST advance  moves the mock forward by {"duration": "1m30s"}
+//	POST set      moves the mock to {"time": "2006-01-02T15:04:05Z"}
+//	GET  pending  lists the timers and tickers scheduled on the mock
+//
+// Requests and responses are JSON, described by Schema, which is also served
+// at "schema". Errors are reported as {"error": "..."} with a 4xx status.
+//
+// The package also provides Transport, for the other side of the wire: an
+// http.RoundTripper whose timeouts and retries run on a clock.
+package clockhttp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// ErrUnauthorized may be returned by an Authorize function to reject a
+// request with 401 rather than 403.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Handler serves the control operations for a mock clock.
+type Handler struct {
+	Mock clock.Controller
+
+	// Authorize, if set, is called before every operation. Returning
+	// ErrUnauthorized rejects the request with 401 Unauthorized, and any
+	// other error rejects it with 403 Forbidden.
+	Authorize func(r *http.Request) error
+}
+
+// NewHandler returns a Handler that controls m and accepts every request.
+func NewHandler(m clock.Controller) *Handler {
+	return &Handler{Mock: m}
+}
+
+// BearerToken returns an Authorize function that accepts only requests
+// carrying "Authorization: Bearer <token>". The header is compared in
+// constant time, so response times don't give the token away.
+func BearerToken(token string) func(r *http.Request) error {
+	want := []byte("Bearer " + token)
+	return func(r *http.Request) error {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+}
+
+// NowResponse is returned by every operation except pending.
+type NowResponse struct {
+	Now time.Time `json:"now"`
+}
+
+// AdvanceRequest is the body of an advance request.
+type AdvanceRequest struct {
+	Duration string `json:"duration"` // parsed by time.ParseDuration
+}
+
+// SetRequest is the body of a set request.
+type SetRequest struct {
+	Time time.Time `json:"time"`
+}
+
+// PendingTimer describes one entry of a pending response.
+type PendingTimer struct {
+	ID       uint64    `json:"id"`
+	Kind     string    `json:"kind"`
+	Deadline time.Time `json:"deadline"`
+	Interval string    `json:"interval,omitempty"`
+	Stack    string    `json:"stack,omitempty"`
+}
+
+// ErrorResponse is returned with any 4xx status.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Schema is a JSON schema describing the requests and responses.
+const Schema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$defs": {
+    "AdvanceRequest": {
+      "type": "object",
+      "properties": {"duration": {"type": "string", "description": "Go duration, e.g. 1m30s"}},
+      "required": ["duration"]
+    },
+    "SetRequest": {
+      "type": "object",
+      "properties": {"time": {"type": "string", "format": "date-time"}},
+      "required": ["time"]
+    },
+    "NowResponse": {
+      "type": "object",
+      "properties": {"now": {"type": "string", "format": "date-time"}},
+      "required": ["now"]
+    },
+    "PendingResponse": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer"},
+          "kind": {"enum": ["Timer", "Ticker", "AfterFunc"]},
+          "deadline": {"type": "string", "format": "date-time"},
+          "interval": {"type": "string"},
+          "stack": {"type": "string"}
+        },
+        "required": ["id", "kind", "deadline"]
+      }
+    },
+    "ErrorResponse": {
+      "type": "object",
+      "properties": {"error": {"type": "string"}},
+      "required": ["error"]
+    }
+  }
+}
+`
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Authorize != nil {
+		if err := h.Authorize(r); err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, ErrUnauthorized) {
+				status = http.StatusUnauthorized
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+	}
+
+	op := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	switch op {
+	case "now":
+		if checkMethod(w, r, http.MethodGet) {
+			writeJSON(w, NowResponse{Now: h.Mock.Now()})
+		}
+	case "advance":
+		if !checkMethod(w, r, http.MethodPost) {
+			return
+		}
+		var req AdvanceRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if d < 0 {
+			writeError(w, http.StatusBadRequest, "negative duration")
+			return
+		}
+		h.Mock.Add(d)
+		writeJSON(w, NowResponse{Now: h.Mock.Now()})
+	case "set":
+		if !checkMethod(w, r, http.MethodPost) {
+			return
+		}
+		var req SetRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if req.Time.IsZero() {
+			writeError(w, http.StatusBadRequest, "missing time")
+			return
+		}
+		h.Mock.Set(req.Time)
+		writeJSON(w, NowResponse{Now: h.Mock.Now()})
+	case "pending":
+		if !checkMethod(w, r, http.MethodGet) {
+			return
+		}
+		pending := h.Mock.PendingTimers()
+		ret := make([]PendingTimer, 0, len(pending))
+		for _, t := range pending {
+			p := PendingTimer{ID: t.ID, Kind: string(t.Kind), Deadline: t.Deadline, Stack: t.Stack}
+			if t.Interval != 0 {
+				p.Interval = t.Interval.String()
+			}
+			ret = append(ret, p)
+		}
+		writeJSON(w, ret)
+	case "schema":
+		if checkMethod(w, r, http.MethodGet) {
+			w.Header().Set("Content-Type", "application/schema+json")
+			_, _ = w.Write([]byte(Schema))
+		}
+	default:
+		writeError(w, http.StatusNotFound, "unknown operation "+op)
+	}
+}
+
+func checkMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	return true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: msg})
+}
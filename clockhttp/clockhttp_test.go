@@ -0,0 +1,82 @@
+package clockhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+// Ensure that the mock can be read, advanced and set over HTTP.
+func TestHandler(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	mock.NewTicker(time.Minute)
+	srv := httptest.NewServer(NewHandler(mock))
+	defer srv.Close()
+
+	var now NowResponse
+	assert.Equal(t, http.StatusOK, do(t, srv, "GET", "/now", "", &now))
+	assert.True(t, time.Unix(0, 0).Equal(now.Now))
+
+	assert.Equal(t, http.StatusOK, do(t, srv, "POST", "/advance", `{"duration": "90s"}`, &now))
+	assert.True(t, time.Unix(90, 0).Equal(now.Now))
+	assert.Equal(t, time.Unix(90, 0), mock.Now())
+
+	assert.Equal(t, http.StatusOK, do(t, srv, "POST", "/set", `{"time": "1970-01-01T00:10:00Z"}`, &now))
+	assert.True(t, time.Unix(600, 0).Equal(mock.Now()))
+
+	var pending []PendingTimer
+	assert.Equal(t, http.StatusOK, do(t, srv, "GET", "/pending", "", &pending))
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "Ticker", pending[0].Kind)
+		assert.Equal(t, "1m0s", pending[0].Interval)
+		assert.True(t, time.Unix(660, 0).Equal(pending[0].Deadline))
+	}
+
+	var e ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, do(t, srv, "POST", "/advance", `{"duration": "soon"}`, &e))
+	assert.NotEmpty(t, e.Error)
+	assert.Equal(t, http.StatusBadRequest, do(t, srv, "POST", "/set", `{}`, &e))
+	assert.Equal(t, "missing time", e.Error)
+	assert.True(t, time.Unix(600, 0).Equal(mock.Now()))
+	assert.Equal(t, http.StatusMethodNotAllowed, do(t, srv, "GET", "/advance", "", nil))
+	assert.Equal(t, http.StatusNotFound, do(t, srv, "GET", "/rewind", "", nil))
+
+	var schema map[string]interface{}
+	assert.Equal(t, http.StatusOK, do(t, srv, "GET", "/schema", "", &schema))
+}
+
+// Ensure that the Authorize hook rejects requests.
+func TestHandler_Authorize(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	h := NewHandler(mock)
+	h.Authorize = BearerToken("other")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, srv, "POST", "/advance", `{"duration": "1s"}`, nil))
+	assert.Equal(t, time.Unix(0, 0), mock.Now())
+
+	h.Authorize = BearerToken("secret")
+	assert.Equal(t, http.StatusOK, do(t, srv, "POST", "/advance", `{"duration": "1s"}`, nil))
+	assert.Equal(t, time.Unix(1, 0), mock.Now())
+}
@@ -0,0 +1,160 @@
+package clockhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// Transport is an http.RoundTripper that applies a timeout to each request
+// and retries failed requests, measuring both on a clock. With a mock clock,
+// a client's timeout and retry paths can be tested by advancing virtual
+// time instead of waiting.
+type Transport struct {
+	// Clock measures timeouts and waits between retries.
+	Clock clock.MockableClock
+	// Base makes the requests. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Timeout, if positive, limits each attempt, including reading the
+	// response body, like http.Client's Timeout. An attempt that times out
+	// fails with an error wrapping context.DeadlineExceeded.
+	Timeout time.Duration
+
+	// Retry, if set, decides how long to wait between attempts and when to
+	// give up. Requests are only retried if they have no body or can
+	// recreate it with GetBody.
+	Retry clock.BackoffPolicy
+	// ShouldRetry reports whether an attempt should be retried. If nil,
+	// errors, 429 Too Many Requests and 5xx responses are retried.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// errRetryable marks an attempt that returned a response worth retrying.
+var errRetryable = errors.New("clockhttp: retryable response")
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Retry == nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return t.attempt(req)
+	}
+
+	var resp *http.Response
+	attempts := 0
+	err := clock.RetryWith(req.Context(), t.Clock, t.Retry, func(ctx context.Context) error {
+		if resp != nil {
+			resp.Body.Close()
+			resp = nil
+		}
+		attempts++
+		r := req
+		if attempts > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return clock.Permanent(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		var err error
+		resp, err = t.attempt(r)
+		if !t.shouldRetry(resp, err) {
+			return clock.Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+		return errRetryable
+	})
+	if err == nil || errors.Is(err, errRetryable) {
+		// Either the last attempt succeeded, or the policy gave up and the
+		// last response is the best answer there is.
+		return resp, nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil, err
+}
+
+func (t *Transport) shouldRetry(resp *http.Response, err error) bool {
+	if t.ShouldRetry != nil {
+		return t.ShouldRetry(resp, err)
+	}
+	return err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.Timeout <= 0 {
+		return t.base().RoundTrip(req)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	d := &deadline{timeout: t.Timeout, cancel: cancel}
+	d.timer = t.Clock.AfterFunc(t.Timeout, func() {
+		atomic.StoreInt32(&d.fired, 1)
+		cancel()
+	})
+	resp, err := t.base().RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		d.stop()
+		return nil, d.wrap(err)
+	}
+	resp.Body = &deadlineBody{ReadCloser: resp.Body, deadline: d}
+	return resp, nil
+}
+
+// deadline cancels an attempt's context when its timer fires.
+type deadline struct {
+	timeout time.Duration
+	timer   clock.MockableTimer
+	cancel  context.CancelFunc
+	fired   int32
+}
+
+func (d *deadline) stop() {
+	d.timer.Stop()
+	d.cancel()
+}
+
+// wrap reports err as a timeout if it came from the timer firing.
+func (d *deadline) wrap(err error) error {
+	if err != nil && atomic.LoadInt32(&d.fired) != 0 {
+		return fmt.Errorf("clockhttp: request timed out after %v: %w", d.timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// deadlineBody keeps an attempt's timeout running until its response body is
+// closed.
+type deadlineBody struct {
+	io.ReadCloser
+	deadline *deadline
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		return n, err
+	}
+	return n, b.deadline.wrap(err)
+}
+
+func (b *deadlineBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.deadline.stop()
+	return err
+}
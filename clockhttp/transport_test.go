@@ -0,0 +1,93 @@
+package clockhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func respond(status int) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+// Ensure that a request that hangs fails once the mock reaches the timeout.
+func TestTransport_Timeout(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	client := &http.Client{Transport: &Transport{
+		Clock:   mock,
+		Timeout: 5 * time.Second,
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+	}}
+
+	errc := make(chan error)
+	go func() {
+		_, err := client.Get("http://example.com/")
+		errc <- err
+	}()
+	mock.ExpectStarts(1)
+	mock.Add(5*time.Second, clock.WaitBefore)
+	err := <-errc
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.Empty(t, mock.PendingTimers())
+}
+
+// Ensure that failed requests are retried on the policy's schedule, resending
+// the body, and the last response is returned when the policy gives up.
+func TestTransport_Retry(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	var bodies []string
+	client := &http.Client{Transport: &Transport{
+		Clock: mock,
+		Retry: clock.ConstantBackoff{Interval: time.Second, MaxAttempts: 3},
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			return respond(http.StatusServiceUnavailable), nil
+		}),
+	}}
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := client.Post("http://example.com/", "text/plain", strings.NewReader("hi"))
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	for i := 0; i < 2; i++ {
+		mock.ExpectStarts(1)
+		mock.Add(time.Second, clock.WaitBefore)
+	}
+	resp := <-done
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, []string{"hi", "hi", "hi"}, bodies)
+}
+
+// Ensure that responses ShouldRetry rejects are returned at once.
+func TestTransport_NoRetry(t *testing.T) {
+	calls := 0
+	client := &http.Client{Transport: &Transport{
+		Clock: clock.NewUnsynchronizedMock(),
+		Retry: clock.ConstantBackoff{Interval: time.Second},
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return respond(http.StatusNotFound), nil
+		}),
+	}}
+	resp, err := client.Get("http://example.com/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,215 @@
+// Package clockotel traces the sleeps, timers and tickers of any
+// MockableClock with OpenTelemetry, so that services using the clock for
+// testability also get timing observability.
+//
+// Each sleep and timer is a span, and each ticker is a span with an event
+// for every tick. They carry the requested duration, the elapsed time on the
+// clock ("virtual"), and the elapsed wall-clock time ("real"), which differ
+// when the clock is a mock.
+package clockotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kraney/clock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys set on spans and events.
+const (
+	DurationKey = attribute.Key("clock.duration")        // requested duration, in seconds
+	VirtualKey  = attribute.Key("clock.virtual_elapsed") // elapsed time on the clock, in seconds
+	RealKey     = attribute.Key("clock.real_elapsed")    // elapsed wall-clock time, in seconds
+	StoppedKey  = attribute.Key("clock.stopped")         // the timer was stopped before firing
+)
+
+// Clock is a MockableClock that passes calls on to another clock and traces
+// them. Sleeps without a context, timers and tickers start new root spans;
+// SleepContext starts a child of the span in its context.
+//
+// Channels of timers and tickers created through Clock are fed by a
+// goroutine that records each value before passing it on, so a value may
+// arrive slightly after a mock has been advanced rather than immediately.
+type Clock struct {
+	clock  clock.MockableClock
+	tracer trace.Tracer
+}
+
+// New returns a Clock that traces c's activity with tracer.
+func New(c clock.MockableClock, tracer trace.Tracer) *Clock {
+	return &Clock{clock: c, tracer: tracer}
+}
+
+// since records when a span started on both the clock and the wall clock.
+type since struct {
+	virtual time.Time
+	real    time.Time
+}
+
+func (c *Clock) start() since { return since{virtual: c.clock.Now(), real: time.Now()} }
+
+func (c *Clock) elapsed(s since) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		VirtualKey.Float64(c.clock.Since(s.virtual).Seconds()),
+		RealKey.Float64(time.Since(s.real).Seconds()),
+	}
+}
+
+func (c *Clock) span(ctx context.Context, name string, d time.Duration) (trace.Span, since) {
+	_, span := c.tracer.Start(ctx, name, trace.WithAttributes(DurationKey.Float64(d.Seconds())))
+	return span, c.start()
+}
+
+func (c *Clock) Now() time.Time { return c.clock.Now() }
+
+func (c *Clock) Since(t time.Time) time.Duration { return c.clock.Since(t) }
+
+func (c *Clock) Sleep(d time.Duration) {
+	span, s := c.span(context.Background(), "clock.Sleep", d)
+	c.clock.Sleep(d)
+	span.SetAttributes(c.elapsed(s)...)
+	span.End()
+}
+
+func (c *Clock) SleepUntil(t time.Time) { c.Sleep(t.Sub(c.clock.Now())) }
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	span, s := c.span(ctx, "clock.Sleep", d)
+	err := c.clock.SleepContext(ctx, d)
+	span.SetAttributes(c.elapsed(s)...)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+	return err
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time { return c.NewTimer(d).C }
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.NewTimer(t.Sub(c.clock.Now())).C
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.MockableTimer {
+	span, s := c.span(context.Background(), "clock.AfterFunc", d)
+	tm := &timer{c: c, span: span, since: s}
+	tm.t = c.clock.AfterFunc(d, func() {
+		tm.fired()
+		f()
+	})
+	return clock.WrapTimer(nil, tm)
+}
+
+func (c *Clock) AtFunc(t time.Time, f func()) clock.MockableTimer {
+	return c.AfterFunc(t.Sub(c.clock.Now()), f)
+}
+
+func (c *Clock) NewTimer(d time.Duration) *clock.Timer {
+	span, s := c.span(context.Background(), "clock.Timer", d)
+	t := c.clock.NewTimer(d)
+	tm := &timer{c: c, t: t, span: span, since: s}
+	return clock.ForwardTimer(t, tm, func(time.Time) { tm.fired() })
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time { return c.NewTicker(d).C }
+
+func (c *Clock) NewTicker(d time.Duration) *clock.Ticker {
+	return c.newTicker(d, c.clock.NewTicker(d))
+}
+
+func (c *Clock) NewAlignedTicker(d time.Duration) *clock.Ticker {
+	return c.newTicker(d, c.clock.NewAlignedTicker(d))
+}
+
+func (c *Clock) newTicker(d time.Duration, t *clock.Ticker) *clock.Ticker {
+	span, s := c.span(context.Background(), "clock.Ticker", d)
+	tk := &ticker{c: c, t: t, span: span, done: make(chan struct{})}
+	ch := make(chan time.Time, 1)
+	go func() {
+		last := s
+		for {
+			select {
+			case v := <-t.C:
+				span.AddEvent("tick", trace.WithAttributes(c.elapsed(last)...))
+				last = c.start()
+				select {
+				case ch <- v:
+				default:
+				}
+			case <-tk.done:
+				return
+			}
+		}
+	}()
+	return clock.WrapTicker(ch, tk)
+}
+
+// timer ends its span when it first fires or is stopped. Later activity
+// after a Reset is recorded as events on the same span, if it is still
+// being recorded.
+type timer struct {
+	c     *Clock
+	t     clock.MockableTimer
+	span  trace.Span
+	since since
+
+	mu    sync.Mutex
+	ended bool
+}
+
+func (t *timer) fired() {
+	t.end(t.c.elapsed(t.since)...)
+}
+
+func (t *timer) end(attrs ...attribute.KeyValue) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.ended {
+		t.ended = true
+		t.span.SetAttributes(attrs...)
+		t.span.End()
+	}
+}
+
+func (t *timer) Stop() bool {
+	stopped := t.t.Stop()
+	if stopped {
+		t.end(append(t.c.elapsed(t.since), StoppedKey.Bool(true))...)
+	}
+	return stopped
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.span.AddEvent("reset", trace.WithAttributes(DurationKey.Float64(d.Seconds())))
+	return t.t.Reset(d)
+}
+
+func (t *timer) Reschedule(at time.Time) bool {
+	d := at.Sub(t.c.clock.Now())
+	t.span.AddEvent("reset", trace.WithAttributes(DurationKey.Float64(d.Seconds())))
+	return t.t.Reschedule(at)
+}
+
+type ticker struct {
+	c    *Clock
+	t    *clock.Ticker
+	span trace.Span
+	once sync.Once
+	done chan struct{}
+}
+
+func (t *ticker) Stop() {
+	t.t.Stop()
+	t.once.Do(func() {
+		t.span.End()
+		close(t.done)
+	})
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.span.AddEvent("reset", trace.WithAttributes(DurationKey.Float64(d.Seconds())))
+	t.t.Reset(d)
+}
@@ -0,0 +1,97 @@
+package clockotel
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newClock() (*Clock, *clock.UnsynchronizedMock, *tracetest.SpanRecorder) {
+	rec := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	mock := clock.NewUnsynchronizedMock()
+	return New(mock, provider.Tracer("test")), mock, rec
+}
+
+func attr(span sdktrace.ReadOnlySpan, key string) float64 {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.AsFloat64()
+		}
+	}
+	return -1
+}
+
+// Ensure that sleeps are spans tagged with virtual and real durations.
+func TestClock_SleepContext(t *testing.T) {
+	c, mock, rec := newClock()
+	mock.ExpectStarts(1)
+	go mock.Add(time.Minute, clock.WaitBefore)
+	assert.NoError(t, c.SleepContext(context.Background(), time.Minute))
+
+	spans := rec.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "clock.Sleep", spans[0].Name())
+		assert.Equal(t, 60.0, attr(spans[0], "clock.duration"))
+		assert.Equal(t, 60.0, attr(spans[0], "clock.virtual_elapsed"))
+		assert.Less(t, attr(spans[0], "clock.real_elapsed"), 60.0)
+	}
+}
+
+// Ensure that timers end their spans when they fire or are stopped, and
+// tickers record an event per tick.
+func TestClock_Timers(t *testing.T) {
+	c, mock, rec := newClock()
+	timer := c.NewTimer(time.Second)
+	c.AfterFunc(time.Hour, func() {}).Stop()
+	ticker := c.NewTicker(time.Second)
+
+	mock.Add(time.Second)
+	<-timer.C
+	<-ticker.C
+	mock.Add(time.Second)
+	<-ticker.C
+	ticker.Stop()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		spans[s.Name()] = s
+	}
+	assert.Equal(t, 1.0, attr(spans["clock.Timer"], "clock.virtual_elapsed"))
+	assert.Contains(t, spans["clock.AfterFunc"].Attributes(), StoppedKey.Bool(true))
+	assert.Len(t, spans["clock.Ticker"].Events(), 2)
+}
+
+// Ensure that timers that have fired leave no goroutines behind, and that
+// a fire that wasn't received is discarded by Reset.
+func TestClock_TimerNoLeak(t *testing.T) {
+	c, mock, _ := newClock()
+	before := runtime.NumGoroutine()
+	for i := 0; i < 1000; i++ {
+		ch := c.After(time.Second)
+		mock.Add(time.Second)
+		<-ch
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+10)
+
+	timer := c.NewTimer(time.Second)
+	mock.Add(time.Second)
+	timer.Reset(time.Second)
+	select {
+	case v := <-timer.C:
+		t.Fatalf("received stale fire %v after Reset", v)
+	default:
+	}
+	mock.Add(time.Second)
+	<-timer.C
+}
@@ -0,0 +1,200 @@
+// Package clockprom instruments any MockableClock with Prometheus metrics:
+// the clock's current time, counts of timers and tickers created, fired and
+// stopped, and a histogram of sleep durations. It works around both the
+// realtime clock, for production observability, and the mocks, for test
+// debugging.
+package clockprom
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of timer, used as the "kind" label.
+const (
+	KindTimer     = "timer"
+	KindTicker    = "ticker"
+	KindAfterFunc = "afterfunc"
+)
+
+// Clock is a MockableClock that passes calls on to another clock and
+// records metrics about them. It is also a prometheus.Collector, and must be
+// registered for its metrics to be exported.
+//
+// Channels of timers and tickers created through Clock are fed by a
+// goroutine that counts each value before passing it on, so a value may
+// arrive slightly after a mock has been advanced rather than immediately.
+type Clock struct {
+	clock clock.MockableClock
+
+	now     prometheus.GaugeFunc
+	created *prometheus.CounterVec
+	fired   *prometheus.CounterVec
+	stopped *prometheus.CounterVec
+	sleeps  prometheus.Histogram
+}
+
+var _ prometheus.Collector = (*Clock)(nil)
+
+// New returns a Clock that instruments c. Metric names are prefixed with
+// namespace, if not empty, then "clock_".
+func New(c clock.MockableClock, namespace string) *Clock {
+	return &Clock{
+		clock: c,
+		now: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "clock", Name: "now_seconds",
+			Help: "Current time of the clock, in seconds since the Unix epoch.",
+		}, func() float64 { return float64(c.Now().UnixNano()) / 1e9 }),
+		created: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "clock", Name: "timers_created_total",
+			Help: "Number of timers and tickers created.",
+		}, []string{"kind"}),
+		fired: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "clock", Name: "timers_fired_total",
+			Help: "Number of times timers have fired and tickers have ticked.",
+		}, []string{"kind"}),
+		stopped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "clock", Name: "timers_stopped_total",
+			Help: "Number of timers and tickers stopped.",
+		}, []string{"kind"}),
+		sleeps: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "clock", Name: "sleep_seconds",
+			Help:    "Requested durations of sleeps, in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 10, 7),
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Clock) Describe(ch chan<- *prometheus.Desc) {
+	c.now.Describe(ch)
+	c.created.Describe(ch)
+	c.fired.Describe(ch)
+	c.stopped.Describe(ch)
+	c.sleeps.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Clock) Collect(ch chan<- prometheus.Metric) {
+	c.now.Collect(ch)
+	c.created.Collect(ch)
+	c.fired.Collect(ch)
+	c.stopped.Collect(ch)
+	c.sleeps.Collect(ch)
+}
+
+func (c *Clock) Now() time.Time { return c.clock.Now() }
+
+func (c *Clock) Since(t time.Time) time.Duration { return c.clock.Since(t) }
+
+func (c *Clock) Sleep(d time.Duration) {
+	c.sleeps.Observe(d.Seconds())
+	c.clock.Sleep(d)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	c.sleeps.Observe(t.Sub(c.clock.Now()).Seconds())
+	c.clock.SleepUntil(t)
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	c.sleeps.Observe(d.Seconds())
+	return c.clock.SleepContext(ctx, d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time { return c.NewTimer(d).C }
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.NewTimer(t.Sub(c.clock.Now())).C
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.MockableTimer {
+	c.created.WithLabelValues(KindAfterFunc).Inc()
+	t := c.clock.AfterFunc(d, func() {
+		c.fired.WithLabelValues(KindAfterFunc).Inc()
+		f()
+	})
+	return clock.WrapTimer(nil, &timer{c: c, kind: KindAfterFunc, t: t})
+}
+
+func (c *Clock) AtFunc(t time.Time, f func()) clock.MockableTimer {
+	return c.AfterFunc(t.Sub(c.clock.Now()), f)
+}
+
+func (c *Clock) NewTimer(d time.Duration) *clock.Timer {
+	c.created.WithLabelValues(KindTimer).Inc()
+	t := c.clock.NewTimer(d)
+	return clock.ForwardTimer(t, &timer{c: c, kind: KindTimer, t: t}, func(time.Time) {
+		c.fired.WithLabelValues(KindTimer).Inc()
+	})
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time { return c.NewTicker(d).C }
+
+func (c *Clock) NewTicker(d time.Duration) *clock.Ticker {
+	return c.newTicker(c.clock.NewTicker(d))
+}
+
+func (c *Clock) NewAlignedTicker(d time.Duration) *clock.Ticker {
+	return c.newTicker(c.clock.NewAlignedTicker(d))
+}
+
+func (c *Clock) newTicker(t *clock.Ticker) *clock.Ticker {
+	c.created.WithLabelValues(KindTicker).Inc()
+	tk := &ticker{c: c, t: t, done: make(chan struct{})}
+	ch := make(chan time.Time, 1)
+	go func() {
+		for {
+			select {
+			case v := <-t.C:
+				c.fired.WithLabelValues(KindTicker).Inc()
+				select {
+				case ch <- v:
+				default:
+				}
+			case <-tk.done:
+				return
+			}
+		}
+	}()
+	return clock.WrapTicker(ch, tk)
+}
+
+type timer struct {
+	c    *Clock
+	kind string
+	t    clock.MockableTimer
+}
+
+func (t *timer) Stop() bool {
+	stopped := t.t.Stop()
+	if stopped {
+		t.c.stopped.WithLabelValues(t.kind).Inc()
+	}
+	return stopped
+}
+
+func (t *timer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+func (t *timer) Reschedule(at time.Time) bool { return t.t.Reschedule(at) }
+
+type ticker struct {
+	c    *Clock
+	t    *clock.Ticker
+	once sync.Once
+	done chan struct{}
+}
+
+func (t *ticker) Stop() {
+	t.t.Stop()
+	t.once.Do(func() {
+		t.c.stopped.WithLabelValues(KindTicker).Inc()
+		close(t.done)
+	})
+}
+
+func (t *ticker) Reset(d time.Duration) { t.t.Reset(d) }
@@ -0,0 +1,84 @@
+package clockprom
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that timer activity on a mock is counted.
+func TestClock_Timers(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	c := New(mock, "test")
+
+	timer := c.NewTimer(time.Second)
+	ticker := c.NewTicker(time.Second)
+	c.AfterFunc(time.Hour, func() {}).Stop()
+
+	mock.Add(time.Second)
+	<-timer.C
+	<-ticker.C
+	ticker.Stop()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.created.WithLabelValues(KindTimer)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.created.WithLabelValues(KindTicker)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.fired.WithLabelValues(KindTimer)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.fired.WithLabelValues(KindTicker)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.fired.WithLabelValues(KindAfterFunc)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.stopped.WithLabelValues(KindAfterFunc)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.stopped.WithLabelValues(KindTicker)))
+	assert.False(t, timer.Stop())
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.stopped.WithLabelValues(KindTimer)))
+}
+
+// Ensure that the collector exports the clock's time and sleeps.
+func TestClock_Collect(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	mock.Add(90 * time.Second)
+	c := New(mock, "")
+	mock.ExpectStarts(1)
+	go mock.Add(time.Second, clock.WaitBefore)
+	c.Sleep(time.Second)
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP clock_now_seconds Current time of the clock, in seconds since the Unix epoch.
+# TYPE clock_now_seconds gauge
+clock_now_seconds 91
+`), "clock_now_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(c, "clock_sleep_seconds"))
+}
+
+// Ensure that timers that have fired leave no goroutines behind, and that
+// a fire that wasn't received is discarded by Reset.
+func TestClock_TimerNoLeak(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	c := New(mock, "test")
+	before := runtime.NumGoroutine()
+	for i := 0; i < 1000; i++ {
+		ch := c.After(time.Second)
+		mock.Add(time.Second)
+		<-ch
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+10)
+
+	timer := c.NewTimer(time.Second)
+	mock.Add(time.Second)
+	timer.Reset(time.Second)
+	select {
+	case v := <-timer.C:
+		t.Fatalf("received stale fire %v after Reset", v)
+	default:
+	}
+	mock.Add(time.Second)
+	<-timer.C
+}
@@ -0,0 +1,272 @@
+// Package clocksock shares a mock clock's time with helper processes that a
+// test spawns, over a unix socket.
+//
+// The test serves the socket with Listen, around its mock. Each helper calls
+// Dial, or DialEnv to find the socket from its environment, and gets a clock
+// that is set to the mock's time whenever the mock advances. An advance of
+// the mock returns only after every connected helper has applied it, so the
+// test and its helpers move in lockstep.
+//
+// The wire format is a stream of JSON objects, one per line: the server
+// sends {"seq": 1, "now": "2006-01-02T15:04:05Z"} when a helper connects and
+// after each advance, and the helper replies {"ack": 1} once it has set its
+// clock.
+package clocksock
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// SocketEnv is the environment variable that Server.Env sets, and DialEnv
+// reads, to pass the socket's path to a helper process.
+const SocketEnv = "CLOCK_SOCKET"
+
+// DefaultTimeout is how long of real time a Server waits for a helper to
+// acknowledge an advance, unless changed with SetTimeout.
+const DefaultTimeout = 5 * time.Second
+
+// update is sent by the server to announce the time.
+type update struct {
+	Seq uint64    `json:"seq"`
+	Now time.Time `json:"now"`
+}
+
+// ack is sent by a helper once it has applied an update.
+type ack struct {
+	Ack uint64 `json:"ack"`
+}
+
+// Server publishes a mock's time on a unix socket.
+type Server struct {
+	mock clock.AdvanceObserver
+	ln   net.Listener
+
+	mu      sync.Mutex // serializes updates
+	seq     uint64     // sequence number of the last update
+	timeout time.Duration
+	helpers map[*helper]struct{}
+	closed  bool
+}
+
+type helper struct {
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+}
+
+// Listen serves m's time on a unix socket at path, which must not exist. The
+// server registers an OnAdvance hook with m, so each of m's advances waits
+// for the helpers connected at the time.
+func Listen(path string, m clock.AdvanceObserver) (*Server, error) {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{mock: m, ln: ln, timeout: DefaultTimeout, helpers: map[*helper]struct{}{}}
+	m.OnAdvance(func(from, to time.Time) { s.publish(to) })
+	go s.serve()
+	return s, nil
+}
+
+// Path returns the path of the server's socket.
+func (s *Server) Path() string {
+	return s.ln.Addr().String()
+}
+
+// Env returns an entry for exec.Cmd.Env that tells DialEnv in the helper
+// where to find the socket.
+func (s *Server) Env() string {
+	return SocketEnv + "=" + s.Path()
+}
+
+// SetTimeout sets how long of real time the server waits for each helper to
+// acknowledge an advance. A helper that takes longer is disconnected, so a
+// hung helper can't hang the test.
+func (s *Server) SetTimeout(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = d
+}
+
+// Close stops the server, disconnecting every helper and removing the
+// socket. Later advances of the mock are not published.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for h := range s.helpers {
+		s.dropLocked(h)
+	}
+	return s.ln.Close()
+}
+
+// serve accepts helpers until the listener is closed.
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.add(conn)
+	}
+}
+
+// add sends the current time to a new helper and registers it for updates.
+// It doesn't wait for the helper's ack; the next update does.
+func (s *Server) add(conn net.Conn) {
+	h := &helper{conn: conn, enc: json.NewEncoder(conn), dec: json.NewDecoder(bufio.NewReader(conn))}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		conn.Close()
+		return
+	}
+	s.helpers[h] = struct{}{}
+	if err := s.sendLocked(h, update{Seq: s.seq, Now: s.mock.Now()}); err != nil {
+		s.dropLocked(h)
+	}
+}
+
+// publish sends now to every helper and waits for them to acknowledge it,
+// dropping any that fail to.
+func (s *Server) publish(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.seq++
+	u := update{Seq: s.seq, Now: now}
+	for h := range s.helpers {
+		if err := s.sendLocked(h, u); err != nil {
+			s.dropLocked(h)
+		}
+	}
+	for h := range s.helpers {
+		if err := h.wait(s.seq); err != nil {
+			s.dropLocked(h)
+		}
+	}
+}
+
+// sendLocked writes u to h, and starts the time h has to acknowledge it. It
+// must be called with mu held.
+func (s *Server) sendLocked(h *helper, u update) error {
+	// The deadline is in real time, since it guards against a helper that
+	// has stopped responding, not anything the mock controls.
+	if err := h.conn.SetDeadline(time.Now().Add(s.timeout)); err != nil { // clockvet:ignore
+		return err
+	}
+	return h.enc.Encode(u)
+}
+
+// dropLocked disconnects h. It must be called with mu held.
+func (s *Server) dropLocked(h *helper) {
+	delete(s.helpers, h)
+	h.conn.Close()
+}
+
+// wait reads acks from the helper until it acknowledges seq.
+func (h *helper) wait(seq uint64) error {
+	for {
+		var a ack
+		if err := h.dec.Decode(&a); err != nil {
+			return err
+		}
+		if a.Ack >= seq {
+			return nil
+		}
+	}
+}
+
+// Client follows the time of a Server.
+type Client struct {
+	conn net.Conn
+	mock *clock.UnsynchronizedMock
+	done chan struct{}
+	err  error
+}
+
+// Dial connects to the server at path, and returns a Client whose Clock is
+// set to the server's time. Its timers fire as the server's mock advances.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	var first update
+	if err := dec.Decode(&first); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c := &Client{conn: conn, mock: clock.NewUnsynchronizedMock(), done: make(chan struct{})}
+	c.mock.Set(first.Now)
+	go c.follow(dec, first.Seq)
+	return c, nil
+}
+
+// DialEnv is Dial with the path from the SocketEnv environment variable, as
+// set by Server.Env.
+func DialEnv() (*Client, error) {
+	path := os.Getenv(SocketEnv)
+	if path == "" {
+		return nil, errors.New("clocksock: " + SocketEnv + " is not set")
+	}
+	return Dial(path)
+}
+
+// Clock returns the clock that follows the server's time. Only the server
+// moves it.
+func (c *Client) Clock() clock.MockableClock {
+	return c.mock
+}
+
+// Done returns a channel that is closed when the client stops following the
+// server, because either side closed the connection.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err waits for the client to stop following the server, and returns the
+// error that stopped it.
+func (c *Client) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Close disconnects from the server. The clock stays at the last time it
+// was set to.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// follow applies updates from the server, acknowledging each one, until the
+// connection fails.
+func (c *Client) follow(dec *json.Decoder, seq uint64) {
+	defer close(c.done)
+	enc := json.NewEncoder(c.conn)
+	for {
+		if err := enc.Encode(ack{Ack: seq}); err != nil {
+			c.err = err
+			return
+		}
+		var u update
+		if err := dec.Decode(&u); err != nil {
+			c.err = err
+			return
+		}
+		if !u.Now.Equal(c.mock.Now()) {
+			c.mock.Set(u.Now)
+		}
+		seq = u.Seq
+	}
+}
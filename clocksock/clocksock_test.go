@@ -0,0 +1,136 @@
+package clocksock
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func listen(t *testing.T, m clock.AdvanceObserver) *Server {
+	t.Helper()
+	s, err := Listen(filepath.Join(t.TempDir(), "clock.sock"), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// Ensure that clients start from the server's time and apply each advance
+// before it returns.
+func TestClient(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	mock.Add(time.Minute)
+	s := listen(t, mock)
+
+	clients := []*Client{}
+	for i := 0; i < 2; i++ {
+		c, err := Dial(s.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		assert.True(t, time.Unix(60, 0).Equal(c.Clock().Now()))
+		clients = append(clients, c)
+	}
+
+	fired := clients[0].Clock().After(time.Minute)
+	mock.Add(time.Minute)
+	for _, c := range clients {
+		assert.True(t, time.Unix(120, 0).Equal(c.Clock().Now()))
+	}
+	select {
+	case <-fired:
+	default:
+		t.Fatal("client timer did not fire")
+	}
+
+	mock.Set(time.Unix(1000, 0))
+	for _, c := range clients {
+		assert.True(t, time.Unix(1000, 0).Equal(c.Clock().Now()))
+	}
+
+	// A client that leaves doesn't hold up the server.
+	clients[1].Close()
+	<-clients[1].Done()
+	mock.Add(time.Minute)
+	assert.True(t, time.Unix(1060, 0).Equal(clients[0].Clock().Now()))
+
+	s.Close()
+	assert.Error(t, clients[0].Err())
+}
+
+// Ensure that a client that stops acknowledging is dropped after its timeout.
+func TestServer_Timeout(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	s := listen(t, mock)
+	s.SetTimeout(10 * time.Millisecond)
+
+	c, err := Dial(s.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	// Block the client inside a timer callback, so it can't acknowledge.
+	release := make(chan struct{})
+	c.Clock().AfterFunc(time.Second, func() { <-release })
+
+	mock.Add(time.Second)
+	mock.Add(time.Second)
+	assert.Equal(t, time.Unix(2, 0), mock.Now())
+	close(release)
+	<-c.Done()
+	assert.Error(t, c.Err())
+}
+
+// Ensure that a helper process follows the test's clock.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv(SocketEnv) == "" {
+		t.Skip("run as a helper process by TestDialEnv")
+	}
+	c, err := DialEnv()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fired := c.Clock().After(time.Minute)
+	fmt.Println("ready")
+	fmt.Println(<-fired)
+	os.Exit(0)
+}
+
+func TestDialEnv(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	s := listen(t, mock)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), s.Env())
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	lines := bufio.NewScanner(out)
+	if !lines.Scan() || lines.Text() != "ready" {
+		t.Fatalf("helper: %q", lines.Text())
+	}
+
+	mock.Add(time.Minute)
+	if assert.True(t, lines.Scan()) {
+		assert.Equal(t, time.Unix(60, 0).String(), lines.Text())
+	}
+	assert.NoError(t, cmd.Wait())
+
+	t.Setenv(SocketEnv, "")
+	_, err = DialEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+// Package clocktest runs table-driven timer tests against a mock clock. A
+// Scenario lists steps, each an advance of the clock with the timer starts
+// and checkpoints it should lead to and assertions to make afterwards, in
+// place of hand-written sequences of Add, Wait and assert calls.
+package clocktest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// DefaultTimeout is how long of real time a Scenario waits for timer starts
+// and checkpoints, unless it sets Timeout.
+const DefaultTimeout = 5 * time.Second
+
+// Step is one step of a Scenario: an advance of the clock, by Advance or to
+// Set, and what should follow from it.
+type Step struct {
+	Name    string        // describes the step in test names and failures
+	Advance time.Duration // how far to advance the clock
+	Set     time.Time     // time to set the clock to instead, if not zero
+
+	// Starts is the number of timers the code under test is expected to
+	// start as a result of the step. The next step, or the end of the
+	// scenario, waits for them.
+	Starts int
+
+	// Checkpoints maps names of the Scenario's checkpoints to the number of
+	// times each is expected to be done as a result of the step. The step
+	// waits for them before its Check.
+	Checkpoints map[clock.CheckpointName]int
+
+	// Check, if set, makes assertions once the step is done, given a
+	// report of what fired during the advance.
+	Check func(t testing.TB, report *clock.AdvanceReport)
+}
+
+// Scenario is a table-driven timer test.
+type Scenario struct {
+	// Starts is the number of timers expected to start before the first
+	// step, such as by code the test has just started.
+	Starts int
+
+	// Checkpoints are the checkpoints the code under test marks as done,
+	// by name, for the steps to wait for.
+	Checkpoints map[clock.CheckpointName]clock.Checkpoint
+
+	// Timeout is how long of real time to wait for timer starts and
+	// checkpoints before failing, or DefaultTimeout if zero.
+	Timeout time.Duration
+
+	Steps []Step
+}
+
+// Run runs the scenario against mock, each step as a subtest of t named
+// after it, so failures say which step they happened in. It stops at the
+// first step that fails, and reports whether every step passed.
+func (s *Scenario) Run(t *testing.T, mock clock.Controller) bool {
+	t.Helper()
+	for i, step := range s.Steps {
+		for name := range step.Checkpoints {
+			if s.Checkpoints[name] == nil {
+				t.Fatalf("%s: unknown checkpoint %q", step.describe(i), name)
+			}
+		}
+	}
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	mock.ExpectStarts(s.Starts)
+	for i, step := range s.Steps {
+		ok := t.Run(step.describe(i), func(t *testing.T) {
+			if !mock.WaitTimeout(t, timeout) {
+				t.FailNow()
+			}
+			for name, n := range step.Checkpoints {
+				s.Checkpoints[name].Add(n)
+			}
+			mock.ExpectStarts(step.Starts)
+
+			var report *clock.AdvanceReport
+			if step.Set.IsZero() {
+				report = mock.AddReport(step.Advance)
+			} else {
+				report = mock.SetReport(step.Set)
+			}
+			for name := range step.Checkpoints {
+				if !clock.WaitCheckpoint(t, s.Checkpoints[name], timeout) {
+					t.FailNow()
+				}
+			}
+			if step.Check != nil {
+				step.Check(t, report)
+			}
+		})
+		if !ok {
+			return false
+		}
+	}
+	return mock.WaitTimeout(t, timeout)
+}
+
+func (step Step) describe(i int) string {
+	if step.Name != "" {
+		return fmt.Sprintf("step %d %s", i+1, step.Name)
+	}
+	return fmt.Sprintf("step %d", i+1)
+}
@@ -0,0 +1,72 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// poller polls every interval until stopped, marking polled each time.
+func poller(c clock.MockableClock, interval time.Duration, polled clock.Checkpoint, stop <-chan struct{}) {
+	for {
+		t := c.NewTimer(interval)
+		select {
+		case <-t.C:
+			polled.Done()
+		case <-stop:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// Ensure that a scenario waits for each step's starts and checkpoints, and
+// passes the advance report to its Check.
+func TestScenario_Run(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	start := mock.Now()
+	polled := clock.NewOptionalCheckPoint("polled")
+	stop := make(chan struct{})
+	defer close(stop)
+
+	s := Scenario{
+		Starts:      1,
+		Checkpoints: map[clock.CheckpointName]clock.Checkpoint{"polled": polled},
+		Steps: []Step{
+			{
+				Name:    "too early",
+				Advance: 9 * time.Second,
+				Check: func(t testing.TB, r *clock.AdvanceReport) {
+					assert.Empty(t, r.Fired)
+				},
+			},
+			{
+				Name:        "first poll",
+				Advance:     time.Second,
+				Starts:      1,
+				Checkpoints: map[clock.CheckpointName]int{"polled": 1},
+				Check: func(t testing.TB, r *clock.AdvanceReport) {
+					assert.Equal(t, 1, r.Count(clock.KindTimer))
+				},
+			},
+			{
+				Name:        "second poll",
+				Set:         start.Add(20 * time.Second),
+				Starts:      1,
+				Checkpoints: map[clock.CheckpointName]int{"polled": 1},
+			},
+		},
+	}
+	go poller(mock, 10*time.Second, polled, stop)
+
+	assert.True(t, s.Run(t, mock))
+	assert.Equal(t, start.Add(20*time.Second), mock.Now())
+}
+
+// Ensure that steps are named by number and name.
+func TestStep_describe(t *testing.T) {
+	assert.Equal(t, "step 1", Step{}.describe(0))
+	assert.Equal(t, "step 3 retry", Step{Name: "retry"}.describe(2))
+}
@@ -0,0 +1,102 @@
+// Package clockvet provides an analyzer that flags direct use of the time
+// package's clock functions, such as time.Now and time.After, in packages
+// that import github.com/kraney/clock. Such calls bypass the injected clock,
+// so a mock can't control them; finding them guides a complete migration.
+//
+// Run it with go vet:
+//
+//	go install github.com/kraney/clock/clockvet/cmd/clockvet@latest
+//	go vet -vettool=$(which clockvet) ./...
+//
+// A use that should read the real clock can be kept by adding a comment
+// containing "clockvet:ignore" on the same line or the line before.
+package clockvet
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// clockPath is the import path of the clock package.
+const clockPath = "github.com/kraney/clock"
+
+// Analyzer flags uses of the time package's clock functions in packages
+// that import the clock package.
+var Analyzer = &analysis.Analyzer{
+	Name: "clockvet",
+	Doc:  "report direct uses of the time package's clock in packages that use github.com/kraney/clock",
+	Run:  run,
+}
+
+// replacements maps each time package function that reads or waits on the
+// real clock to what replaces it.
+var replacements = map[string]string{
+	"Now":       "Now on a clock.MockableClock",
+	"Since":     "Since on a clock.MockableClock",
+	"Until":     "Now on a clock.MockableClock and Sub",
+	"Sleep":     "Sleep on a clock.MockableClock",
+	"After":     "After on a clock.MockableClock",
+	"AfterFunc": "AfterFunc on a clock.MockableClock",
+	"Tick":      "Tick on a clock.MockableClock",
+	"NewTimer":  "NewTimer on a clock.MockableClock",
+	"NewTicker": "NewTicker on a clock.MockableClock",
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if !importsClock(pass.Pkg) {
+		return nil, nil
+	}
+	for _, file := range pass.Files {
+		ignored := ignoredLines(pass, file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+			if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" {
+				return true
+			}
+			if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+				return true
+			}
+			use, ok := replacements[fn.Name()]
+			if !ok {
+				return true
+			}
+			line := pass.Fset.Position(sel.Pos()).Line
+			if ignored[line] || ignored[line-1] {
+				return true
+			}
+			pass.Reportf(sel.Pos(), "time.%s bypasses the injected clock; use %s", fn.Name(), use)
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// importsClock reports whether pkg imports the clock package.
+func importsClock(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == clockPath {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredLines returns the lines of file with a clockvet:ignore comment.
+func ignoredLines(pass *analysis.Pass, file *ast.File) map[int]bool {
+	lines := map[int]bool{}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if strings.Contains(c.Text, "clockvet:ignore") {
+				lines[pass.Fset.Position(c.Slash).Line] = true
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,12 @@
+package clockvet_test
+
+import (
+	"testing"
+
+	"github.com/kraney/clock/clockvet"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), clockvet.Analyzer, "a", "b")
+}
@@ -0,0 +1,11 @@
+// Command clockvet reports direct uses of the time package's clock in
+// packages that use github.com/kraney/clock. Run it with
+// go vet -vettool=$(which clockvet).
+package main
+
+import (
+	"github.com/kraney/clock/clockvet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(clockvet.Analyzer) }
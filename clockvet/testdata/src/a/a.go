@@ -0,0 +1,25 @@
+package a
+
+import (
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+func f(c clock.MockableClock) {
+	_ = c.Now()
+	_ = time.Now()                  // want `time.Now bypasses the injected clock; use Now on a clock.MockableClock`
+	time.Sleep(time.Second)         // want `time.Sleep bypasses the injected clock`
+	<-time.After(time.Second)       // want `time.After bypasses the injected clock`
+	t := time.NewTimer(time.Second) // want `time.NewTimer bypasses the injected clock`
+	t.Reset(time.Second)
+	now := time.Now // want `time.Now bypasses the injected clock`
+	_ = now
+	_ = time.Unix(0, 0).Add(time.Hour)
+	_, _ = time.ParseDuration("1s")
+
+	_ = time.Now() // clockvet:ignore
+
+	// clockvet:ignore
+	_ = time.Since(time.Time{})
+}
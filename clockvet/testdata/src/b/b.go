@@ -0,0 +1,6 @@
+// Package b doesn't use the clock package, so it is not checked.
+package b
+
+import "time"
+
+func f() time.Time { return time.Now() }
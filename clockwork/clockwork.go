@@ -0,0 +1,132 @@
+// Package clockwork adapts between MockableClock and the Clock and FakeClock
+// interfaces of github.com/jonboulle/clockwork, so that libraries expecting
+// clockwork can be driven by the same clock as the rest of a test.
+package clockwork
+
+import (
+	"context"
+	"time"
+
+	cw "github.com/jonboulle/clockwork"
+	"github.com/kraney/clock"
+)
+
+// ToClock returns a clockwork.Clock that reads and schedules on c.
+func ToClock(c clock.MockableClock) cw.Clock {
+	if a, ok := c.(*fromClockwork); ok {
+		return a.c
+	}
+	return &toClockwork{c: c}
+}
+
+// ToFakeClock returns a clockwork.FakeClock that reads, schedules on and
+// advances m. BlockUntil counts the timers and tickers pending on m,
+// including those started through other adapters or directly.
+func ToFakeClock(m clock.MockClock) cw.FakeClock {
+	return &toFakeClock{toClockwork: toClockwork{c: m}, m: m}
+}
+
+// FromClock returns a MockableClock that reads and schedules on c, which may
+// be a clockwork.FakeClock.
+func FromClock(c cw.Clock) clock.MockableClock {
+	if a, ok := c.(*toClockwork); ok {
+		return a.c
+	}
+	if a, ok := c.(*toFakeClock); ok {
+		return a.c
+	}
+	return &fromClockwork{c: c}
+}
+
+// toClockwork implements clockwork.Clock on top of a MockableClock.
+type toClockwork struct {
+	c clock.MockableClock
+}
+
+func (a *toClockwork) After(d time.Duration) <-chan time.Time { return a.c.After(d) }
+
+func (a *toClockwork) Sleep(d time.Duration) { a.c.Sleep(d) }
+
+func (a *toClockwork) Now() time.Time { return a.c.Now() }
+
+func (a *toClockwork) Since(t time.Time) time.Duration { return a.c.Since(t) }
+
+func (a *toClockwork) NewTicker(d time.Duration) cw.Ticker { return ticker{a.c.NewTicker(d)} }
+
+func (a *toClockwork) NewTimer(d time.Duration) cw.Timer {
+	return a.c.NewTimer(d)
+}
+
+func (a *toClockwork) AfterFunc(d time.Duration, f func()) cw.Timer {
+	return a.c.AfterFunc(d, f)
+}
+
+// toFakeClock implements clockwork.FakeClock on top of a mock clock.
+type toFakeClock struct {
+	toClockwork
+	m clock.MockClock
+}
+
+func (a *toFakeClock) Advance(d time.Duration) { a.m.Add(d) }
+
+func (a *toFakeClock) BlockUntil(waiters int) {
+	for len(a.m.PendingTimers()) != waiters {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type ticker struct{ *clock.Ticker }
+
+func (t ticker) Chan() <-chan time.Time { return t.C }
+
+// fromClockwork implements MockableClock on top of a clockwork.Clock.
+type fromClockwork struct {
+	c cw.Clock
+}
+
+func (a *fromClockwork) After(d time.Duration) <-chan time.Time { return a.c.After(d) }
+
+func (a *fromClockwork) AfterAt(t time.Time) <-chan time.Time { return a.c.After(t.Sub(a.c.Now())) }
+
+func (a *fromClockwork) AfterFunc(d time.Duration, f func()) clock.MockableTimer {
+	return clock.WrapTimer(nil, a.c.AfterFunc(d, f))
+}
+
+func (a *fromClockwork) AtFunc(t time.Time, f func()) clock.MockableTimer {
+	return a.AfterFunc(t.Sub(a.c.Now()), f)
+}
+
+func (a *fromClockwork) Now() time.Time { return a.c.Now() }
+
+func (a *fromClockwork) Since(t time.Time) time.Duration { return a.c.Since(t) }
+
+func (a *fromClockwork) Sleep(d time.Duration) { a.c.Sleep(d) }
+
+func (a *fromClockwork) SleepUntil(t time.Time) { a.c.Sleep(t.Sub(a.c.Now())) }
+
+func (a *fromClockwork) SleepContext(ctx context.Context, d time.Duration) error {
+	t := a.c.NewTimer(d)
+	select {
+	case <-t.Chan():
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
+
+func (a *fromClockwork) Tick(d time.Duration) <-chan time.Time { return a.c.NewTicker(d).Chan() }
+
+func (a *fromClockwork) NewTicker(d time.Duration) *clock.Ticker {
+	t := a.c.NewTicker(d)
+	return clock.WrapTicker(t.Chan(), t)
+}
+
+func (a *fromClockwork) NewAlignedTicker(d time.Duration) *clock.Ticker {
+	return clock.AlignTicker(a, d)
+}
+
+func (a *fromClockwork) NewTimer(d time.Duration) *clock.Timer {
+	t := a.c.NewTimer(d)
+	return clock.WrapTimer(t.Chan(), t)
+}
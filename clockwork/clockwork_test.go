@@ -0,0 +1,81 @@
+package clockwork
+
+import (
+	"testing"
+	"time"
+
+	cw "github.com/jonboulle/clockwork"
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that code written against clockwork can be driven by the mock.
+func TestToFakeClock(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	fake := ToFakeClock(mock)
+
+	done := make(chan struct{})
+	go func() {
+		fake.Sleep(10 * time.Second)
+		close(done)
+	}()
+	fake.BlockUntil(1)
+
+	timer := fake.NewTimer(5 * time.Second)
+	fake.Advance(5 * time.Second)
+	select {
+	case <-timer.Chan():
+	default:
+		t.Fatal("timer did not fire")
+	}
+
+	fake.Advance(5 * time.Second)
+	<-done
+	assert.Equal(t, time.Unix(10, 0), mock.Now())
+}
+
+// Ensure that tickers created through the adapter are driven by the mock.
+func TestToClock_Ticker(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	ticker := ToClock(mock).NewTicker(time.Second)
+	defer ticker.Stop()
+
+	mock.Add(time.Second)
+	select {
+	case <-ticker.Chan():
+	default:
+		t.Fatal("ticker did not tick")
+	}
+}
+
+// Ensure that timers created through the adapter are driven by the fake clock.
+func TestFromClock(t *testing.T) {
+	fake := cw.NewFakeClockAt(time.Unix(0, 0))
+	c := FromClock(fake)
+
+	timer := c.NewTimer(10 * time.Second)
+	fake.Advance(5 * time.Second)
+	assert.True(t, timer.Reset(10*time.Second))
+	fake.Advance(9 * time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("timer fired early")
+	default:
+	}
+	fake.Advance(time.Second)
+	select {
+	case <-timer.C:
+	default:
+		t.Fatal("timer did not fire")
+	}
+	assert.Equal(t, time.Unix(15, 0), c.Now())
+}
+
+// Ensure that adapting a clock twice returns the original.
+func TestRoundTrip(t *testing.T) {
+	mock := clock.NewUnsynchronizedMock()
+	assert.Equal(t, clock.MockableClock(mock), FromClock(ToFakeClock(mock)))
+
+	fake := cw.NewFakeClock()
+	assert.Equal(t, cw.Clock(fake), ToClock(FromClock(fake)))
+}
@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Now only moves when the refresh ticker ticks.
+func TestCoarsen(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	c := Coarsen(mock, time.Second)
+	defer c.Stop()
+	start := mock.Now()
+
+	mock.Add(999 * time.Millisecond)
+	assert.Equal(t, start, c.Now())
+	mock.Add(time.Millisecond)
+	for c.Since(start) != time.Second {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Stop()
+	mock.Add(time.Second)
+	assert.Equal(t, start.Add(time.Second), c.Now())
+	assert.Empty(t, mock.PendingTimers())
+}
+
+// Ensure that the realtime coarse clock keeps up with the time.
+func TestNewCoarseClock(t *testing.T) {
+	c := NewCoarseClock(time.Millisecond)
+	defer c.Stop()
+	start := c.Now()
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, c.Now().After(start))
+	assert.WithinDuration(t, time.Now(), c.Now(), 100*time.Millisecond)
+}
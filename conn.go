@@ -0,0 +1,85 @@
+package clock
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// expired is a deadline in the past, for making a net.Pipe time out now.
+var expired = time.Unix(1, 0)
+
+// Pipe returns the two ends of an in-memory, synchronous connection, like
+// net.Pipe, whose read and write deadlines are measured on c rather than the
+// system clock. With a mock, protocol timeouts can then be tested by
+// advancing virtual time: a Read or Write blocked past its deadline returns
+// an error wrapping os.ErrDeadlineExceeded once the mock reaches it.
+func Pipe(c MockableClock) (net.Conn, net.Conn) {
+	a, b := net.Pipe()
+	return newPipeConn(c, a), newPipeConn(c, b)
+}
+
+type pipeConn struct {
+	net.Conn
+	read  deadline
+	write deadline
+}
+
+func newPipeConn(c MockableClock, conn net.Conn) *pipeConn {
+	return &pipeConn{
+		Conn:  conn,
+		read:  deadline{clock: c, set: conn.SetReadDeadline},
+		write: deadline{clock: c, set: conn.SetWriteDeadline},
+	}
+}
+
+func (p *pipeConn) SetDeadline(t time.Time) error {
+	if err := p.read.reset(t); err != nil {
+		return err
+	}
+	return p.write.reset(t)
+}
+
+func (p *pipeConn) SetReadDeadline(t time.Time) error { return p.read.reset(t) }
+
+func (p *pipeConn) SetWriteDeadline(t time.Time) error { return p.write.reset(t) }
+
+// deadline holds off the underlying connection's deadline, using a timer on
+// clock to set it to a time already passed when the deadline is reached.
+type deadline struct {
+	clock MockableClock
+	set   func(time.Time) error
+
+	mu    sync.Mutex
+	timer MockableTimer
+	gen   int // incremented on each reset, so a stale timer does nothing
+}
+
+func (d *deadline) reset(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.gen++
+	if t.IsZero() {
+		return d.set(time.Time{})
+	}
+	wait := t.Sub(d.clock.Now())
+	if wait <= 0 {
+		return d.set(expired)
+	}
+	if err := d.set(time.Time{}); err != nil {
+		return err
+	}
+	gen := d.gen
+	d.timer = d.clock.AfterFunc(wait, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.gen == gen {
+			_ = d.set(expired)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a blocked read times out when the mock reaches its deadline.
+func TestPipe_ReadDeadline(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	a, b := Pipe(mock)
+	defer a.Close()
+	defer b.Close()
+
+	assert.NoError(t, a.SetReadDeadline(mock.Now().Add(time.Second)))
+	errc := make(chan error)
+	go func() {
+		_, err := a.Read(make([]byte, 1))
+		errc <- err
+	}()
+
+	mock.Add(999 * time.Millisecond)
+	select {
+	case err := <-errc:
+		t.Fatalf("read returned early: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	mock.Add(time.Millisecond)
+	err := <-errc
+	assert.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+
+	// Clearing the deadline lets reads through again.
+	assert.NoError(t, a.SetReadDeadline(time.Time{}))
+	go b.Write([]byte("x"))
+	n, err := a.Read(make([]byte, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+// Ensure that moving a deadline replaces the old one, and a deadline in the
+// past fails at once.
+func TestPipe_WriteDeadline(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	a, b := Pipe(mock)
+	defer a.Close()
+	defer b.Close()
+
+	assert.NoError(t, a.SetDeadline(mock.Now().Add(time.Second)))
+	assert.NoError(t, a.SetWriteDeadline(mock.Now().Add(time.Hour)))
+	mock.Add(time.Minute)
+	go func() {
+		buf := make([]byte, 1)
+		b.Read(buf)
+	}()
+	_, err := a.Write([]byte("x"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, a.SetWriteDeadline(mock.Now()))
+	_, err = a.Write([]byte("x"))
+	assert.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+}
@@ -0,0 +1,116 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned by WithTimeoutFn when its function runs out of
+// time. It wraps context.DeadlineExceeded.
+var ErrTimeout = fmt.Errorf("timed out: %w", context.DeadlineExceeded)
+
+// ContextWithTimeout is like context.WithTimeout, but measures the timeout
+// on c. See ContextWithDeadline.
+func ContextWithTimeout(ctx context.Context, c MockableClock, d time.Duration) (context.Context, context.CancelFunc) {
+	return ContextWithDeadline(ctx, c, c.Now().Add(d))
+}
+
+// ContextWithDeadline is like context.WithDeadline, but the deadline is a
+// time on c. The returned context is done, with Err reporting
+// context.DeadlineExceeded, once c reaches the deadline, and its Deadline
+// method reports it. With the realtime clock this is context.WithDeadline.
+//
+// The deadline of a mock is virtual, so code that compares Deadline with
+// time.Now, as net.Dialer and gRPC do, will misjudge how long is left. Pass
+// such code a context without a deadline and cancel it instead.
+func ContextWithDeadline(ctx context.Context, c MockableClock, deadline time.Time) (context.Context, context.CancelFunc) {
+	if IsRealtime(c) {
+		return context.WithDeadline(ctx, deadline)
+	}
+	// If the parent's deadline on the same clock is sooner, it applies, as
+	// with context.WithDeadline. A deadline on another clock, such as the
+	// real one, can't be compared with this one.
+	if p, ok := ctx.Value(deadlineCtxKey{}).(*deadlineCtx); ok && p.clock == c && p.deadline.Before(deadline) {
+		return context.WithCancel(ctx)
+	}
+
+	t := &deadlineCtx{parent: ctx, clock: c, deadline: deadline, done: make(chan struct{})}
+	d := deadline.Sub(c.Now())
+	if d <= 0 {
+		t.cancel(context.DeadlineExceeded)
+		return t, func() {}
+	}
+	timer := c.AfterFunc(d, func() { t.cancel(context.DeadlineExceeded) })
+	go func() {
+		select {
+		case <-ctx.Done():
+			t.cancel(ctx.Err())
+		case <-t.done:
+		}
+		// Take the timer off the clock once the context is done for any
+		// other reason.
+		timer.Stop()
+	}()
+	return t, func() {
+		t.cancel(context.Canceled)
+		timer.Stop()
+	}
+}
+
+// WithTimeoutFn calls fn with a context that expires after d on c, and
+// returns fn's error, or ErrTimeout if fn failed after the context expired.
+// fn runs on the calling goroutine, so it must return promptly once its
+// context is done.
+func WithTimeoutFn(c MockableClock, d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := ContextWithTimeout(context.Background(), c, d)
+	defer cancel()
+	err := fn(ctx)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	return err
+}
+
+// deadlineCtx is a context with a deadline on a clock. It has its own done
+// channel, rather than wrapping one from context.WithCancel, so that
+// contexts derived from it see its Err, not the wrapped context's.
+type deadlineCtx struct {
+	parent   context.Context
+	clock    MockableClock
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *deadlineCtx) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) { return c.deadline, true }
+
+func (c *deadlineCtx) Done() <-chan struct{} { return c.done }
+
+func (c *deadlineCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// deadlineCtxKey is the Value key for the nearest deadlineCtx.
+type deadlineCtxKey struct{}
+
+func (c *deadlineCtx) Value(key interface{}) interface{} {
+	if key == (deadlineCtxKey{}) {
+		return c
+	}
+	return c.parent.Value(key)
+}
@@ -0,0 +1,115 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the context is done when the mock reaches its deadline.
+func TestContextWithTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ctx, cancel := ContextWithTimeout(context.Background(), mock, time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, mock.Now().Add(time.Minute), deadline)
+
+	mock.Add(59 * time.Second)
+	assert.NoError(t, ctx.Err())
+	mock.Add(time.Second)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+// Ensure that canceling, or canceling the parent, reports Canceled and takes
+// the timer off the mock.
+func TestContextWithTimeout_Cancel(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ctx, cancel := ContextWithTimeout(context.Background(), mock, time.Minute)
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Empty(t, mock.PendingTimers())
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = ContextWithTimeout(parent, mock, time.Minute)
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	for len(mock.PendingTimers()) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Ensure that a deadline already passed, or later than the parent's, is
+// handled as context.WithDeadline does.
+func TestContextWithDeadline(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ctx, cancel := ContextWithDeadline(context.Background(), mock, mock.Now())
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	parent, cancelParent := ContextWithTimeout(context.Background(), mock, time.Second)
+	defer cancelParent()
+	ctx, cancel = ContextWithTimeout(parent, mock, time.Hour)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, mock.Now().Add(time.Second), deadline)
+	mock.Add(time.Second)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	ctx, cancel = ContextWithTimeout(context.Background(), New(), time.Hour)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+}
+
+// Ensure that a parent's deadline on another clock doesn't stand in for a
+// virtual one, even if it is sooner by the numbers.
+func TestContextWithDeadline_RealParent(t *testing.T) {
+	mock := NewUnsynchronizedMock(Guard(t))
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelParent()
+	ctx, cancel := ContextWithTimeout(parent, mock, time.Minute)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, mock.Now().Add(time.Minute), deadline)
+	mock.Add(time.Minute)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	other := NewUnsynchronizedMock()
+	parent, cancelParent = ContextWithTimeout(context.Background(), other, time.Second)
+	defer cancelParent()
+	ctx, cancel = ContextWithTimeout(parent, mock, time.Minute)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.Equal(t, mock.Now().Add(time.Minute), deadline)
+}
+
+// Ensure that WithTimeoutFn reports a timeout when fn fails after its
+// context expires, and fn's own result otherwise.
+func TestWithTimeoutFn(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.ExpectStarts(1)
+	go mock.Add(time.Second, WaitBefore)
+	err := WithTimeoutFn(mock, time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, ErrTimeout, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	failed := errors.New("failed")
+	assert.Equal(t, failed, WithTimeoutFn(mock, time.Second, func(ctx context.Context) error { return failed }))
+	assert.NoError(t, WithTimeoutFn(mock, time.Second, func(ctx context.Context) error { return nil }))
+	assert.Empty(t, mock.PendingTimers())
+}
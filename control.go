@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// The interfaces below are implemented by both mock clocks, Mock and
+// UnsynchronizedMock. Packages that drive a mock accept the narrowest one
+// they need, so that they work with either.
+
+// Advancer is implemented by the mock clocks, which can be moved forward.
+type Advancer interface {
+	Add(d time.Duration, opts ...Option)
+	Now() time.Time
+}
+
+// Controllable is implemented by the mock clocks, which can be set as well as
+// advanced.
+type Controllable interface {
+	Advancer
+	Set(t time.Time, opts ...Option)
+}
+
+// PendingLister is implemented by the mock clocks, which can list the timers
+// and tickers scheduled on them.
+type PendingLister interface {
+	PendingTimers() []TimerInfo
+}
+
+// Reporter is implemented by the mock clocks, which can report what each
+// advance fired.
+type Reporter interface {
+	Now() time.Time
+	AddReport(d time.Duration, opts ...Option) *AdvanceReport
+	SetReport(t time.Time, opts ...Option) *AdvanceReport
+}
+
+// Waiter is implemented by the mock clocks, which can wait for the code
+// under test to start the timers a test expects.
+type Waiter interface {
+	ExpectStarts(delta int)
+	WaitTimeout(tb testing.TB, timeout time.Duration) bool
+}
+
+// AdvanceObserver is implemented by the mock clocks, which call hooks as they
+// advance.
+type AdvanceObserver interface {
+	Now() time.Time
+	OnAdvance(fn func(from, to time.Time))
+}
+
+// Controller is everything a test can do to drive a mock clock.
+type Controller interface {
+	Controllable
+	PendingLister
+	Reporter
+	Waiter
+	AdvanceObserver
+}
+
+// MockClock is implemented by the mock clocks: a MockableClock for the code
+// under test, and a Controller for the test.
+type MockClock interface {
+	MockableClock
+	Controller
+}
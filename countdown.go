@@ -0,0 +1,134 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Countdown counts down a duration on a clock, and can be paused and
+// resumed, as a game turn timer or a job's time budget can. Time while it
+// is paused doesn't count. It starts one timer, when it is created, which
+// it stops and resets as it is paused and resumed.
+type Countdown struct {
+	clock MockableClock
+	done  chan struct{}
+	timer MockableTimer
+
+	mu        sync.Mutex
+	deadline  time.Time     // when it completes, while running
+	remaining time.Duration // what is left, while paused
+	paused    bool
+	finished  bool
+}
+
+// NewCountdown returns a running Countdown that completes after d on c.
+func NewCountdown(c MockableClock, d time.Duration) *Countdown {
+	cd := &Countdown{clock: c, done: make(chan struct{})}
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	cd.deadline = c.Now().Add(d)
+	cd.timer = c.AfterFunc(d, cd.expire)
+	return cd
+}
+
+// Done returns a channel that is closed when the countdown completes.
+func (cd *Countdown) Done() <-chan struct{} {
+	return cd.done
+}
+
+// Remaining returns how much of the countdown is left, which doesn't change
+// while it is paused, and is 0 once it has completed.
+func (cd *Countdown) Remaining() time.Duration {
+	now := cd.clock.Now()
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	return cd.remainingLocked(now)
+}
+
+// Paused reports whether the countdown is paused.
+func (cd *Countdown) Paused() bool {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	return cd.paused
+}
+
+// Pause stops the countdown until Resume is called, and reports whether it
+// did; it does nothing if the countdown is already paused or has completed.
+// A countdown paused at the instant it was due completes instead.
+func (cd *Countdown) Pause() bool {
+	now := cd.clock.Now()
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.paused || cd.finished {
+		return false
+	}
+	if !now.Before(cd.deadline) {
+		cd.finishLocked()
+		return false
+	}
+	cd.timer.Stop()
+	cd.remaining = cd.deadline.Sub(now)
+	cd.paused = true
+	return true
+}
+
+// Resume restarts a paused countdown with the time it had left, and reports
+// whether it did.
+func (cd *Countdown) Resume() bool {
+	now := cd.clock.Now()
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if !cd.paused {
+		return false
+	}
+	cd.paused = false
+	cd.deadline = now.Add(cd.remaining)
+	cd.timer.Reset(cd.remaining)
+	return true
+}
+
+// Stop abandons the countdown without completing it, and reports whether it
+// was still counting down. Done is never closed afterwards.
+func (cd *Countdown) Stop() bool {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.finished {
+		return false
+	}
+	cd.finished = true
+	cd.timer.Stop()
+	return true
+}
+
+// remainingLocked returns what is left at now. It must be called with mu
+// held.
+func (cd *Countdown) remainingLocked(now time.Time) time.Duration {
+	switch {
+	case cd.finished:
+		return 0
+	case cd.paused:
+		return cd.remaining
+	case !now.Before(cd.deadline):
+		return 0
+	}
+	return cd.deadline.Sub(now)
+}
+
+// expire runs when the timer fires, and completes the countdown unless it
+// has been paused or stopped since.
+func (cd *Countdown) expire() {
+	now := cd.clock.Now()
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.paused || cd.finished || now.Before(cd.deadline) {
+		return
+	}
+	cd.finishLocked()
+}
+
+// finishLocked completes the countdown. It must be called with mu held.
+func (cd *Countdown) finishLocked() {
+	cd.finished = true
+	cd.timer.Stop()
+	close(cd.done)
+}
@@ -0,0 +1,68 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertDone(t *testing.T, cd *Countdown, done bool) {
+	t.Helper()
+	select {
+	case <-cd.Done():
+		assert.True(t, done, "countdown completed")
+	default:
+		assert.False(t, done, "countdown still running")
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	cd := NewCountdown(mock, 10*time.Second)
+
+	mock.Add(3 * time.Second)
+	assert.Equal(t, 7*time.Second, cd.Remaining())
+	assert.True(t, cd.Pause())
+	assert.False(t, cd.Pause())
+	assert.True(t, cd.Paused())
+
+	mock.Add(time.Minute)
+	assertDone(t, cd, false)
+	assert.Equal(t, 7*time.Second, cd.Remaining())
+
+	assert.True(t, cd.Resume())
+	assert.False(t, cd.Resume())
+	mock.Add(6 * time.Second)
+	assert.Equal(t, time.Second, cd.Remaining())
+	assertDone(t, cd, false)
+	mock.Add(time.Second)
+	assertDone(t, cd, true)
+	assert.Equal(t, time.Duration(0), cd.Remaining())
+	assert.False(t, cd.Pause())
+	assert.False(t, cd.Stop())
+}
+
+// Ensure that a countdown paused at the instant it is due completes, even
+// if its timer hasn't fired yet.
+func TestCountdown_PauseWhenDue(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	var cd *Countdown
+	var paused bool
+	mock.AfterFunc(5*time.Second, func() { paused = cd.Pause() })
+	cd = NewCountdown(mock, 5*time.Second)
+
+	mock.Add(5 * time.Second)
+	assert.False(t, paused)
+	assertDone(t, cd, true)
+}
+
+func TestCountdown_Stop(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	cd := NewCountdown(mock, time.Second)
+	assert.True(t, cd.Stop())
+	mock.Add(time.Minute)
+	assertDone(t, cd, false)
+	assert.Equal(t, time.Duration(0), cd.Remaining())
+	assert.False(t, cd.Resume())
+}
@@ -0,0 +1,369 @@
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule describes a recurring sequence of times.
+type Schedule interface {
+	// Next returns the first scheduled time strictly after t, or the zero
+	// time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// cronSchedule is a Schedule parsed from a cron expression. Each field is a
+// bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool           // day field was unrestricted
+	loc                           *time.Location // zone to evaluate in, if set
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{0, 59, nil}
+	hourField   = cronField{0, 23, nil}
+	domField    = cronField{1, 31, nil}
+	monthField  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Sunday may be written as either 0 or 7.
+	dowField = cronField{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard five-field cron expression
+// (minute hour day-of-month month day-of-week) into a Schedule. Fields may
+// use *, lists, ranges, steps and three-letter month and day names, and the
+// @yearly, @monthly, @weekly, @daily and @hourly macros are accepted. The
+// expression may be prefixed with CRON_TZ=<zone> or TZ=<zone> to evaluate it
+// in that time zone; otherwise it is evaluated in the zone of the time
+// passed to Next.
+func ParseCron(spec string) (Schedule, error) {
+	s := &cronSchedule{}
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		i := strings.IndexAny(expr, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("cron %q: missing expression after time zone", spec)
+		}
+		loc, err := time.LoadLocation(expr[strings.Index(expr, "=")+1 : i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+		s.loc = loc
+		expr = strings.TrimSpace(expr[i:])
+	}
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, found %d", spec, len(fields))
+	}
+	var err error
+	if s.minute, _, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("cron %q: minute: %w", spec, err)
+	}
+	if s.hour, _, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("cron %q: hour: %w", spec, err)
+	}
+	if s.dom, s.domStar, err = domField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("cron %q: day of month: %w", spec, err)
+	}
+	if s.month, _, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("cron %q: month: %w", spec, err)
+	}
+	if s.dow, s.dowStar, err = dowField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("cron %q: day of week: %w", spec, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 << 0
+	}
+	return s, nil
+}
+
+// parse returns the bitset of values matched by expr, and whether expr was
+// an unrestricted wildcard.
+func (f cronField) parse(expr string) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+			if part == expr && step == 1 {
+				return f.bits(lo, hi, 1), true, nil
+			}
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if lo, err = f.value(rng[:i]); err != nil {
+				return 0, false, err
+			}
+			if hi, err = f.value(rng[i+1:]); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, false, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		bits |= f.bits(lo, hi, step)
+	}
+	return bits, false, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (f cronField) bits(lo, hi, step int) uint64 {
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time after t matched by the schedule, in t's
+// location. Times that don't exist because of a daylight saving transition
+// are skipped.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	origLoc, loc := t.Location(), t.Location()
+	if s.loc != nil {
+		loc = s.loc
+	}
+	t = t.In(loc)
+
+	// Start from the following whole minute.
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+
+	// Once a field has been advanced, the lower fields restart from their
+	// smallest value.
+	truncated := false
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		if !truncated {
+			truncated = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !truncated {
+			truncated = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		// Midnight may not exist, or may be ambiguous, on a transition day.
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(-time.Duration(t.Hour()) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		if !truncated {
+			truncated = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		if !truncated {
+			truncated = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t.In(origLoc)
+}
+
+// Cron runs jobs according to their Schedules, timed by a MockableClock.
+type Cron struct {
+	clock   MockableClock
+	mu      sync.Mutex
+	entries []*CronEntry
+}
+
+// CronEntry is a job registered with a Cron.
+type CronEntry struct {
+	cron     *Cron
+	schedule Schedule
+	job      func()
+	next     time.Time
+	timer    MockableTimer // nil if the job never runs
+	removed  bool
+}
+
+// NewCron returns a Cron that uses c to decide when to run its jobs.
+func NewCron(c MockableClock) *Cron {
+	return &Cron{clock: c}
+}
+
+// AddFunc parses spec with ParseCron and schedules job to run at each
+// matching time.
+func (c *Cron) AddFunc(spec string, job func()) (*CronEntry, error) {
+	s, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddSchedule(s, job), nil
+}
+
+// AddSchedule schedules job to run at each time in s. Each entry creates a
+// single timer on the clock, which it resets after every run.
+func (c *Cron) AddSchedule(s Schedule, job func()) *CronEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &CronEntry{cron: c, schedule: s, job: job}
+	now := c.clock.Now()
+	e.next = s.Next(now)
+	if !e.next.IsZero() {
+		e.timer = c.clock.AfterFunc(e.next.Sub(now), e.run)
+	}
+	c.entries = append(c.entries, e)
+	return e
+}
+
+func (e *CronEntry) run() {
+	e.cron.mu.Lock()
+	if e.removed {
+		e.cron.mu.Unlock()
+		return
+	}
+	now := e.cron.clock.Now()
+	e.next = e.schedule.Next(now)
+	if !e.next.IsZero() {
+		e.timer.Reset(e.next.Sub(now))
+	}
+	e.cron.mu.Unlock()
+	e.job()
+}
+
+// Next returns the next time the entry's job will run, or the zero time if
+// it will not run again.
+func (e *CronEntry) Next() time.Time {
+	e.cron.mu.Lock()
+	defer e.cron.mu.Unlock()
+	return e.next
+}
+
+// Remove stops the entry's job from running again.
+func (c *Cron) Remove(e *CronEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, entry := range c.entries {
+		if entry == e {
+			c.entries = append(c.entries[:i], c.entries[i+1:]...)
+			break
+		}
+	}
+	e.stop()
+}
+
+// Stop stops the entry's job from running again, as Remove does.
+func (e *CronEntry) Stop() {
+	e.cron.Remove(e)
+}
+
+// stop must be called with the cron's mu held.
+func (e *CronEntry) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	e.removed = true
+	e.next = time.Time{}
+}
+
+// Stop stops all jobs from running again. Jobs that are already running are
+// not interrupted.
+func (c *Cron) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		e.stop()
+	}
+	c.entries = nil
+}
@@ -0,0 +1,129 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	from := time.Date(2021, 5, 11, 10, 30, 15, 0, time.UTC) // a Tuesday
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2021, 5, 11, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, 5, 11, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2021, 5, 11, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2021, 5, 12, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2021, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2021, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * mon-fri", time.Date(2021, 5, 11, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * 5", time.Date(2021, 5, 14, 0, 0, 0, 0, time.UTC)}, // day-of-month OR day-of-week
+		{"5-10/5 10 * * *", time.Date(2021, 5, 12, 10, 5, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2021, 5, 11, 11, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.spec)
+		if !assert.NoError(t, err, tt.spec) {
+			continue
+		}
+		assert.Equal(t, tt.want, s.Next(from), tt.spec)
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"TZ=Nowhere/Special * * * * *",
+	} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseCron_TimeZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data unavailable")
+	}
+
+	s, err := ParseCron("CRON_TZ=America/New_York 0 9 * * *")
+	assert.NoError(t, err)
+	from := time.Date(2021, 5, 11, 0, 0, 0, 0, time.UTC)
+	next := s.Next(from)
+	assert.Equal(t, time.Date(2021, 5, 11, 9, 0, 0, 0, ny), next.In(ny))
+	assert.Equal(t, time.UTC, next.Location())
+
+	// 2:30 does not exist on the day clocks spring forward, so that day is skipped.
+	s, err = ParseCron("30 2 * * *")
+	assert.NoError(t, err)
+	next = s.Next(time.Date(2021, 3, 13, 3, 0, 0, 0, ny))
+	assert.Equal(t, time.Date(2021, 3, 15, 2, 30, 0, 0, ny), next)
+
+	// Only the first 1:30 is matched on the day clocks fall back.
+	s, err = ParseCron("30 1 * * *")
+	assert.NoError(t, err)
+	next = s.Next(time.Date(2021, 11, 7, 0, 0, 0, 0, ny))
+	assert.Equal(t, time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC), next.UTC())
+}
+
+// Ensure that cron jobs run as the mock is advanced.
+func TestCron_Mock(t *testing.T) {
+	clock := NewMock(t, 0)
+	clock.Set(time.Date(2021, 5, 11, 10, 30, 0, 0, time.UTC), ExpectUpcomingStarts(1))
+
+	var runs []time.Time
+	cron := NewCron(clock)
+	entry, err := cron.AddFunc("0 */6 * * *", func() { runs = append(runs, clock.Now()) })
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 5, 11, 12, 0, 0, 0, time.UTC), entry.Next().UTC())
+
+	clock.Add(24 * time.Hour)
+	if assert.Len(t, runs, 4) {
+		assert.Equal(t, time.Date(2021, 5, 11, 12, 0, 0, 0, time.UTC), runs[0].UTC())
+		assert.Equal(t, time.Date(2021, 5, 12, 6, 0, 0, 0, time.UTC), runs[3].UTC())
+	}
+	assert.Equal(t, time.Date(2021, 5, 12, 12, 0, 0, 0, time.UTC), entry.Next().UTC())
+
+	// Whole months can be skipped instantly.
+	clock.Add(31 * 24 * time.Hour)
+	assert.Len(t, runs, 4+31*4)
+
+	cron.Remove(entry)
+	clock.Add(24 * time.Hour)
+	assert.Len(t, runs, 4+31*4)
+	assert.True(t, entry.Next().IsZero())
+}
+
+// Ensure that a stopped cron no longer runs jobs.
+func TestCron_Stop(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	var n int
+	cron := NewCron(clock)
+	_, err := cron.AddFunc("@hourly", func() { n++ })
+	assert.NoError(t, err)
+	_, err = cron.AddFunc("@daily", func() { n++ })
+	assert.NoError(t, err)
+
+	clock.Add(2 * time.Hour)
+	assert.Equal(t, 2, n)
+
+	cron.Stop()
+	clock.Add(48 * time.Hour)
+	assert.Equal(t, 2, n)
+}
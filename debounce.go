@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a function that delays calling fn until d has passed on
+// c without it being called again. Each call restarts the wait. fn is called
+// the way c calls AfterFunc callbacks.
+func Debounce(c MockableClock, d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var timer MockableTimer
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer == nil {
+			timer = c.AfterFunc(d, fn)
+			return
+		}
+		timer.Reset(d)
+	}
+}
+
+// Throttle returns a function that calls fn at most once per d on c. The
+// first call runs fn immediately, and calls within d of the last time fn was
+// run are dropped.
+func Throttle(c MockableClock, d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var last time.Time
+	ran := false
+	return func() {
+		mu.Lock()
+		now := c.Now()
+		if ran && now.Sub(last) < d {
+			mu.Unlock()
+			return
+		}
+		ran = true
+		last = now
+		mu.Unlock()
+		fn()
+	}
+}
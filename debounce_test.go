@@ -0,0 +1,59 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// Ensure that a debounced function runs only after calls stop.
+func TestDebounce(t *testing.T) {
+	var n int
+	clock := NewMock(t, 1)
+	debounced := Debounce(clock, 10*time.Second, func() { n++ })
+
+	for i := 0; i < 5; i++ {
+		debounced()
+		clock.Add(5 * time.Second)
+	}
+	if n != 0 {
+		t.Fatalf("expected 0, got %d", n)
+	}
+
+	clock.Add(5 * time.Second)
+	if n != 1 {
+		t.Fatalf("expected 1, got %d", n)
+	}
+
+	// It can be triggered again once it has run.
+	debounced()
+	clock.Add(10 * time.Second)
+	if n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+}
+
+// Ensure that a throttled function runs at most once per interval.
+func TestThrottle(t *testing.T) {
+	var n int
+	clock := NewUnsynchronizedMock()
+	throttled := Throttle(clock, 10*time.Second, func() { n++ })
+
+	throttled()
+	throttled()
+	if n != 1 {
+		t.Fatalf("expected 1, got %d", n)
+	}
+
+	clock.Add(9 * time.Second)
+	throttled()
+	if n != 1 {
+		t.Fatalf("expected 1, got %d", n)
+	}
+
+	clock.Add(1 * time.Second)
+	throttled()
+	throttled()
+	if n != 2 {
+		t.Fatalf("expected 2, got %d", n)
+	}
+}
@@ -0,0 +1,73 @@
+package clock
+
+import "time"
+
+// DriftOption makes the mock's Now drift from the time its timers run on.
+type DriftOption struct {
+	perSecond float64
+}
+
+// DriftPerSecond makes the mock's Now and Since gain perSecond seconds for
+// every second the clock advances, from the time the option is applied, so
+// that 0.001 gains a millisecond a second and -0.001 loses one. Timers,
+// tickers and sleeps, including AfterAt and SleepUntil deadlines, stay on
+// the undrifted time, as a machine's timers run on its monotonic clock while
+// its wall clock drifts. This allows testing drift compensation and NTP
+// discipline. The skew already accumulated is kept when the rate changes, so
+// DriftPerSecond(0) stops the drift without correcting it.
+func DriftPerSecond(perSecond float64) *DriftOption {
+	return &DriftOption{perSecond}
+}
+
+func (o *DriftOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *DriftOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.skew = mock.skewLocked()
+	mock.driftFrom = mock.now
+	mock.drift = o.perSecond
+	mock.publishNowLocked()
+}
+
+// skewLocked returns how far the mock's Now has drifted from its timers. It
+// must be called with mu held.
+func (m *UnsynchronizedMock) skewLocked() time.Duration {
+	if m.drift == 0 {
+		return m.skew
+	}
+	return m.skew + time.Duration(float64(m.now.Sub(m.driftFrom))*m.drift)
+}
+
+// Drift returns a clock whose Now drifts from c's, gaining perSecond seconds
+// for every second that passes on c, so that 0.001 gains a millisecond a
+// second and -0.001 loses one. Timers, tickers and sleeps run on c
+// unchanged; AfterAt and SleepUntil deadlines are read on the drifted time.
+// It works on the real-time clock and on mocks alike.
+func Drift(c MockableClock, perSecond float64) MockableClock {
+	return &driftClock{MockableClock: c, perSecond: perSecond, start: c.Now()}
+}
+
+// driftClock is a clock whose Now drifts at a constant rate from another's.
+type driftClock struct {
+	MockableClock
+	perSecond float64
+	start     time.Time
+}
+
+func (c *driftClock) Now() time.Time {
+	now := c.MockableClock.Now()
+	return now.Add(time.Duration(float64(now.Sub(c.start)) * c.perSecond))
+}
+
+func (c *driftClock) Since(t time.Time) time.Duration { return c.Now().Sub(t) }
+
+func (c *driftClock) AfterAt(t time.Time) <-chan time.Time { return c.After(t.Sub(c.Now())) }
+
+func (c *driftClock) AtFunc(t time.Time, f func()) MockableTimer {
+	return c.AfterFunc(t.Sub(c.Now()), f)
+}
+
+func (c *driftClock) SleepUntil(t time.Time) { c.Sleep(t.Sub(c.Now())) }
+
+func (c *driftClock) NewAlignedTicker(d time.Duration) *Ticker { return AlignTicker(c, d) }
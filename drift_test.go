@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a drifting mock's Now gains on its timers, and keeps the skew
+// when the drift stops.
+func TestMock_DriftPerSecond(t *testing.T) {
+	mock := NewUnsynchronizedMock(DriftPerSecond(0.001))
+	timer := mock.NewTimer(1000 * time.Second)
+
+	mock.Add(1000 * time.Second)
+	assert.Equal(t, time.Unix(1001, 0), mock.Now())
+	assert.Equal(t, time.Unix(1000, 0), <-timer.C)
+	assert.Equal(t, time.Second, mock.Since(time.Unix(1000, 0)))
+
+	mock.Add(time.Second, DriftPerSecond(0))
+	mock.Add(1000 * time.Second)
+	assert.Equal(t, time.Unix(2002, 0), mock.Now())
+
+	mock.Add(0, DriftPerSecond(-0.002))
+	mock.Add(500 * time.Second)
+	assert.Equal(t, time.Unix(2501, 0), mock.Now())
+}
+
+// Ensure that the Drift decorator skews Now but not timers.
+func TestDrift(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	c := Drift(mock, -0.01)
+	timer := c.NewTimer(100 * time.Second)
+
+	mock.Add(100 * time.Second)
+	assert.Equal(t, time.Unix(99, 0), c.Now())
+	assert.Equal(t, time.Unix(100, 0), <-timer.C)
+
+	real := Drift(New(), 0.5)
+	start := real.Now()
+	time.Sleep(20 * time.Millisecond)
+	assert.GreaterOrEqual(t, real.Since(start), 25*time.Millisecond)
+}
@@ -0,0 +1,139 @@
+package clock
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by NewFromEnv.
+const (
+	// EnvFakeStart is an RFC 3339 time that the clock starts at.
+	EnvFakeStart = "CLOCK_FAKE_START"
+	// EnvOffset is a duration, as accepted by time.ParseDuration, that is
+	// added to the real time.
+	EnvOffset = "CLOCK_OFFSET"
+	// EnvFreeze, if true, stops the clock at its start time.
+	EnvFreeze = "CLOCK_FREEZE"
+)
+
+// NewOffset returns a real-time clock that reads offset later than the time
+// package. Durations, and so timers, tickers and sleeps, run in real time.
+func NewOffset(offset time.Duration) MockableClock {
+	return &offsetClock{offset: offset}
+}
+
+// NewFrozen returns a clock whose Now always returns t. Timers, tickers and
+// sleeps still wait for their durations in real time.
+func NewFrozen(t time.Time) MockableClock {
+	return &frozenClock{now: t}
+}
+
+// NewFromEnv returns a clock configured by the environment, so that a
+// deployment can simulate other dates without code changes. If EnvFakeStart
+// is set, the clock starts at that time, and otherwise it is offset from the
+// real time by EnvOffset, if set. If EnvFreeze is true, the clock then stays
+// at its start time. With none of them set, it returns the real-time clock.
+func NewFromEnv() (MockableClock, error) {
+	start, hasStart := os.LookupEnv(EnvFakeStart)
+	offset, hasOffset := os.LookupEnv(EnvOffset)
+	freeze := false
+	if s, ok := os.LookupEnv(EnvFreeze); ok {
+		var err error
+		if freeze, err = strconv.ParseBool(s); err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvFreeze, err)
+		}
+	}
+
+	var d time.Duration
+	switch {
+	case hasStart && hasOffset:
+		return nil, fmt.Errorf("only one of %s and %s may be set", EnvFakeStart, EnvOffset)
+	case hasStart:
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvFakeStart, err)
+		}
+		d = time.Until(t)
+	case hasOffset:
+		var err error
+		if d, err = time.ParseDuration(offset); err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvOffset, err)
+		}
+	}
+
+	switch {
+	case freeze:
+		return NewFrozen(time.Now().Add(d)), nil
+	case hasStart || hasOffset:
+		return NewOffset(d), nil
+	default:
+		return New(), nil
+	}
+}
+
+// offsetClock is a real-time clock shifted by a fixed offset.
+type offsetClock struct {
+	clock
+	offset time.Duration
+}
+
+func (c *offsetClock) Now() time.Time { return time.Now().Add(c.offset) }
+
+func (c *offsetClock) Since(t time.Time) time.Duration { return c.Now().Sub(t) }
+
+func (c *offsetClock) AfterAt(t time.Time) <-chan time.Time { return time.After(t.Sub(c.Now())) }
+
+func (c *offsetClock) AfterFunc(d time.Duration, f func()) MockableTimer {
+	return WrapTimer(nil, shiftedTimer{time.AfterFunc(d, f), c.Now})
+}
+
+func (c *offsetClock) AtFunc(t time.Time, f func()) MockableTimer {
+	return c.AfterFunc(t.Sub(c.Now()), f)
+}
+
+func (c *offsetClock) NewTimer(d time.Duration) *Timer {
+	t := time.NewTimer(d)
+	return WrapTimer(t.C, shiftedTimer{t, c.Now})
+}
+
+func (c *offsetClock) SleepUntil(t time.Time) { time.Sleep(t.Sub(c.Now())) }
+
+func (c *offsetClock) NewAlignedTicker(d time.Duration) *Ticker { return AlignTicker(c, d) }
+
+// frozenClock is a real-time clock whose Now never changes.
+type frozenClock struct {
+	clock
+	now time.Time
+}
+
+func (c *frozenClock) Now() time.Time { return c.now }
+
+func (c *frozenClock) Since(t time.Time) time.Duration { return c.now.Sub(t) }
+
+func (c *frozenClock) AfterAt(t time.Time) <-chan time.Time { return time.After(t.Sub(c.now)) }
+
+func (c *frozenClock) AfterFunc(d time.Duration, f func()) MockableTimer {
+	return WrapTimer(nil, shiftedTimer{time.AfterFunc(d, f), c.Now})
+}
+
+func (c *frozenClock) AtFunc(t time.Time, f func()) MockableTimer {
+	return c.AfterFunc(t.Sub(c.now), f)
+}
+
+func (c *frozenClock) NewTimer(d time.Duration) *Timer {
+	t := time.NewTimer(d)
+	return WrapTimer(t.C, shiftedTimer{t, c.Now})
+}
+
+func (c *frozenClock) SleepUntil(t time.Time) { time.Sleep(t.Sub(c.now)) }
+
+// shiftedTimer is a realtime timer on a clock whose Now is not the real
+// time, which reads Reschedule deadlines on that clock.
+type shiftedTimer struct {
+	*time.Timer
+	now func() time.Time
+}
+
+func (t shiftedTimer) Reschedule(at time.Time) bool { return t.Reset(at.Sub(t.now())) }
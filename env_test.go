@@ -0,0 +1,64 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the environment selects an offset, frozen or real clock.
+func TestNewFromEnv(t *testing.T) {
+	c, err := NewFromEnv()
+	assert.NoError(t, err)
+	assert.True(t, IsRealtime(c))
+
+	t.Setenv(EnvOffset, "8760h")
+	c, err = NewFromEnv()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(8760*time.Hour), c.Now(), time.Second)
+
+	t.Setenv(EnvFreeze, "true")
+	c, err = NewFromEnv()
+	assert.NoError(t, err)
+	now := c.Now()
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, now, c.Now())
+	assert.Equal(t, time.Hour, c.Since(now.Add(-time.Hour)))
+}
+
+// Ensure that the clock starts at EnvFakeStart.
+func TestNewFromEnv_FakeStart(t *testing.T) {
+	t.Setenv(EnvFakeStart, "2038-01-19T03:14:07Z")
+	c, err := NewFromEnv()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC), c.Now(), time.Second)
+
+	// Timers still run in real time.
+	start := time.Now()
+	<-c.After(10 * time.Millisecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(10*time.Millisecond))
+
+	// Reschedule reads its deadline on the shifted time.
+	timer := c.NewTimer(time.Hour)
+	start = time.Now()
+	assert.True(t, timer.Reschedule(c.Now().Add(10*time.Millisecond)))
+	<-timer.C
+	assert.Less(t, int64(time.Since(start)), int64(time.Minute))
+
+	t.Setenv(EnvOffset, "1h")
+	_, err = NewFromEnv()
+	assert.Error(t, err, "both start and offset")
+}
+
+// Ensure that invalid settings are reported.
+func TestNewFromEnv_Invalid(t *testing.T) {
+	t.Setenv(EnvOffset, "a while")
+	_, err := NewFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv(EnvOffset, "1h")
+	t.Setenv(EnvFreeze, "maybe")
+	_, err = NewFromEnv()
+	assert.Error(t, err)
+}
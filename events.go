@@ -0,0 +1,149 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventType identifies what happened in an Event.
+type EventType string
+
+const (
+	TimerCreated     EventType = "TimerCreated"
+	TimerFired       EventType = "TimerFired"
+	TimerStopped     EventType = "TimerStopped"
+	TimerReset       EventType = "TimerReset"
+	TickerCreated    EventType = "TickerCreated"
+	TickerFired      EventType = "TickerFired"
+	TickerStopped    EventType = "TickerStopped"
+	TickerReset      EventType = "TickerReset"
+	ClockAdvanced    EventType = "ClockAdvanced"
+	ClockRestored    EventType = "ClockRestored"
+	AdvanceVetoed    EventType = "AdvanceVetoed"
+	CheckpointAdded  EventType = "CheckpointAdded"
+	CheckpointDone   EventType = "CheckpointDone"
+	CheckpointWaited EventType = "CheckpointWaited"
+)
+
+// Event describes a single thing that happened inside a mock clock. Only the
+// fields relevant to the Type are set.
+type Event struct {
+	Type       EventType
+	Time       time.Time      // mock time at which the event happened
+	TimerID    uint64         // timer or ticker involved, if any
+	Deadline   time.Time      // deadline of the timer or tick involved, if any
+	Duration   time.Duration  // timer duration or size of a clock advance, if any
+	Checkpoint CheckpointName // checkpoint involved, if any
+	Delta      int            // change to the checkpoint's expected count, if any
+}
+
+func (e Event) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", e.Time.UTC().Format(time.RFC3339Nano), e.Type)
+	if e.TimerID != 0 {
+		fmt.Fprintf(&b, " id=%d", e.TimerID)
+	}
+	if !e.Deadline.IsZero() {
+		fmt.Fprintf(&b, " deadline=%s", e.Deadline.UTC().Format(time.RFC3339Nano))
+	}
+	if e.Duration != 0 {
+		fmt.Fprintf(&b, " duration=%s", e.Duration)
+	}
+	if e.Checkpoint != "" {
+		fmt.Fprintf(&b, " checkpoint=%s", e.Checkpoint)
+	}
+	if e.Delta != 0 {
+		fmt.Fprintf(&b, " delta=%d", e.Delta)
+	}
+	return b.String()
+}
+
+// SetLogger registers a function that is called with every Event the mock
+// produces. Pass nil to stop logging. The logger is called synchronously
+// from whichever goroutine caused the event, without the mock's lock held,
+// so it may safely call back into the mock.
+func (m *UnsynchronizedMock) SetLogger(logger func(Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logger = logger
+}
+
+// DefaultHistoryLimit is the number of events a mock keeps in its History
+// unless changed with SetHistoryLimit.
+const DefaultHistoryLimit = 10000
+
+// History returns the Events the mock has produced, oldest first, since it
+// was created or since the last call to ResetHistory. Only the latest are
+// kept, up to the limit set with SetHistoryLimit.
+func (m *UnsynchronizedMock) History() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.historyLocked()
+}
+
+// ResetHistory discards all recorded events.
+func (m *UnsynchronizedMock) ResetHistory() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history = nil
+	m.historyStart = 0
+}
+
+// SetHistoryLimit sets how many of the latest events the mock keeps in its
+// History, dropping the oldest events beyond that. A limit of 0 turns the
+// history off, so that long-running tests don't pay for it, and a negative
+// limit keeps every event.
+func (m *UnsynchronizedMock) SetHistoryLimit(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.historyLocked()
+	if n >= 0 && len(h) > n {
+		h = append([]Event(nil), h[len(h)-n:]...)
+	}
+	m.history = h
+	m.historyStart = 0
+	m.historyLimit = n
+}
+
+// historyLocked returns a copy of the history in order. It must be called
+// with mu held.
+func (m *UnsynchronizedMock) historyLocked() []Event {
+	ret := make([]Event, 0, len(m.history))
+	ret = append(ret, m.history[m.historyStart:]...)
+	return append(ret, m.history[:m.historyStart]...)
+}
+
+// recordLocked adds e to the history, in place of the oldest event if the
+// history is at its limit. It must be called with mu held.
+func (m *UnsynchronizedMock) recordLocked(e Event) {
+	switch {
+	case m.historyLimit < 0 || len(m.history) < m.historyLimit:
+		m.history = append(m.history, e)
+	case m.historyLimit > 0:
+		m.history[m.historyStart] = e
+		m.historyStart = (m.historyStart + 1) % m.historyLimit
+	}
+}
+
+// logEvent records e in the history and passes it to the registered logger,
+// if any. It must not be called with mu held.
+func (m *UnsynchronizedMock) logEvent(e Event) {
+	m.mu.Lock()
+	m.recordLocked(e)
+	m.events++
+	m.countLocked(e)
+	logger := m.logger
+	m.mu.Unlock()
+	if logger != nil {
+		logger(e)
+	}
+}
+
+// checkpointName returns the name of cp, if it has one.
+func checkpointName(cp Checkpoint) CheckpointName {
+	if s, ok := cp.(fmt.Stringer); ok {
+		return CheckpointName(s.String())
+	}
+	return ""
+}
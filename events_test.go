@@ -0,0 +1,136 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the logger sees timer lifecycle and clock advances in order.
+func TestMock_SetLogger(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	var mu sync.Mutex
+	var types []EventType
+	clock.SetLogger(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		types = append(types, e.Type)
+	})
+
+	clock.ExpectStarts(1)
+	timer := clock.NewTimer(1 * time.Second)
+	ticker := clock.NewTicker(2 * time.Second)
+	clock.Wait()
+	clock.Add(2 * time.Second)
+	ticker.Stop()
+	timer.Reset(1 * time.Second)
+	timer.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []EventType{
+		CheckpointAdded,
+		TimerCreated, CheckpointDone,
+		TickerCreated, CheckpointDone,
+		CheckpointWaited,
+		TimerFired,
+		TickerFired,
+		ClockAdvanced,
+		TickerStopped,
+		TimerReset,
+		TimerStopped,
+	}, types)
+}
+
+// Ensure that events identify the timer involved.
+func TestMock_SetLogger_TimerID(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	var fired []uint64
+	clock.SetLogger(func(e Event) {
+		if e.Type == TimerFired {
+			fired = append(fired, e.TimerID)
+		}
+	})
+
+	a := clock.NewTimer(2 * time.Second)
+	b := clock.NewTimer(1 * time.Second)
+	clock.Add(2 * time.Second)
+
+	assert.NotEqual(t, a.ID(), b.ID())
+	assert.Equal(t, []uint64{b.ID(), a.ID()}, fired)
+
+	// Removing the logger stops further calls
+	clock.SetLogger(nil)
+	clock.NewTimer(1 * time.Second)
+	clock.Add(1 * time.Second)
+	assert.Len(t, fired, 2)
+}
+
+func TestEvent_String(t *testing.T) {
+	e := Event{Type: ClockAdvanced, Time: time.Unix(10, 0), Duration: 10 * time.Second}
+	assert.Equal(t, "1970-01-01T00:00:10Z ClockAdvanced duration=10s", e.String())
+}
+
+// Ensure that the history can be used to assert on the order of events.
+func TestMock_History(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	a := clock.NewTimer(3 * time.Second)
+	b := clock.NewTicker(2 * time.Second)
+	clock.Add(5 * time.Second)
+
+	var fired []uint64
+	for _, e := range clock.History() {
+		if e.Type == TimerFired || e.Type == TickerFired {
+			fired = append(fired, e.TimerID)
+		}
+	}
+	// ticker b at 2s, timer a at 3s, ticker b at 4s
+	assert.Equal(t, []uint64{b.ID(), a.ID(), b.ID()}, fired)
+
+	clock.ResetHistory()
+	assert.Empty(t, clock.History())
+
+	b.Stop()
+	history := clock.History()
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, TickerStopped, history[0].Type)
+		assert.Equal(t, time.Unix(5, 0), history[0].Time)
+	}
+}
+
+// Ensure that the history keeps only the latest events up to its limit, and
+// can be turned off or unbounded.
+func TestMock_SetHistoryLimit(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	clock.SetHistoryLimit(2)
+	for i := 1; i <= 5; i++ {
+		clock.Add(time.Duration(i) * time.Second)
+	}
+	history := clock.History()
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, 4*time.Second, history[0].Duration)
+		assert.Equal(t, 5*time.Second, history[1].Duration)
+	}
+
+	clock.SetHistoryLimit(1)
+	history = clock.History()
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, 5*time.Second, history[0].Duration)
+	}
+
+	clock.SetHistoryLimit(0)
+	clock.Add(time.Second)
+	assert.Empty(t, clock.History())
+	assert.Equal(t, 6, clock.Report().Advances)
+
+	clock.SetHistoryLimit(-1)
+	for i := 0; i < DefaultHistoryLimit+1; i++ {
+		clock.Add(0)
+	}
+	assert.Len(t, clock.History(), DefaultHistoryLimit+1)
+}
@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// Eventually checks cond, then advances the mock by step and checks it
+// again, until cond holds or the mock has advanced by maxVirtual. If
+// maxVirtual is not a multiple of step, the last advance is shorter, so
+// that cond is checked at maxVirtual. It fails tb
+// and returns false if cond never holds. Unlike a real-time retry loop, it
+// takes a predictable amount of virtual time regardless of machine load.
+func Eventually(tb testing.TB, mock Advancer, cond func() bool, maxVirtual, step time.Duration) bool {
+	tb.Helper()
+	if step <= 0 {
+		tb.Fatalf("Eventually: non-positive step %v", step)
+	}
+	start := mock.Now()
+	for {
+		if cond() {
+			return true
+		}
+		elapsed := mock.Now().Sub(start)
+		if elapsed >= maxVirtual {
+			tb.Errorf("condition not satisfied within %v of virtual time", maxVirtual)
+			return false
+		}
+		d := step
+		if elapsed+d > maxVirtual {
+			d = maxVirtual - elapsed
+		}
+		before := mock.Now()
+		mock.Add(d)
+		if !mock.Now().After(before) {
+			tb.Errorf("clock stopped advancing after %v of virtual time", before.Sub(start))
+			return false
+		}
+		// give goroutines woken by the advance a chance to run
+		gosched()
+	}
+}
@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Eventually advances the mock until the condition holds.
+func TestEventually(t *testing.T) {
+	var ok int32
+	clock := NewMock(t, 1)
+	go func() {
+		clock.Sleep(35 * time.Second)
+		atomic.StoreInt32(&ok, 1)
+	}()
+
+	held := Eventually(t, clock, func() bool { return atomic.LoadInt32(&ok) == 1 }, time.Minute, 10*time.Second)
+	assert.True(t, held)
+	assert.Equal(t, time.Unix(40, 0), clock.Now())
+}
+
+// Ensure that Eventually fails once the virtual time budget is spent.
+func TestEventually_Fail(t *testing.T) {
+	experiment := &testing.T{}
+	clock := NewUnsynchronizedMock()
+
+	held := Eventually(experiment, clock, func() bool { return false }, time.Minute, 10*time.Second)
+	assert.False(t, held)
+	assert.True(t, experiment.Failed(), "lack of failure on unsatisfied condition")
+	assert.Equal(t, time.Unix(60, 0), clock.Now())
+}
+
+// Ensure that the condition is checked at maxVirtual when it is not a
+// multiple of step.
+func TestEventually_PartialStep(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	deadline := time.Unix(65, 0)
+	held := Eventually(t, clock, func() bool { return !clock.Now().Before(deadline) }, 65*time.Second, 10*time.Second)
+	assert.True(t, held)
+	assert.Equal(t, deadline, clock.Now())
+
+	experiment := &recordingTB{TB: t}
+	clock = NewUnsynchronizedMock()
+	assert.False(t, Eventually(experiment, clock, func() bool { return false }, 65*time.Second, 10*time.Second))
+	assert.Equal(t, time.Unix(65, 0), clock.Now())
+}
@@ -0,0 +1,113 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// ForwardTimer returns a Timer for a clock that decorates another: it
+// delegates Stop, Reset and Reschedule to b, and delivers each value that t
+// sends on its own channel, after passing it to fire. b is the decorator's
+// backend, which in turn stops and resets t. fire may be nil.
+//
+// Each arming of the timer forwards at most one value, and its goroutine
+// exits once it has done so or the timer is stopped or reset, so a timer
+// that has fired or been stopped leaves nothing behind. Like a mock's timer,
+// a value that has not been received when the timer is stopped or reset is
+// discarded.
+func ForwardTimer(t *Timer, b TimerBackend, fire func(time.Time)) *Timer {
+	f := &forwardedTimer{b: b, src: t.C, c: make(chan time.Time, 1), fire: fire}
+	f.mu.Lock()
+	f.armLocked()
+	f.mu.Unlock()
+	return WrapTimer(f.c, f)
+}
+
+type forwardedTimer struct {
+	b    TimerBackend
+	src  <-chan time.Time // channel of the decorated timer
+	c    chan time.Time   // channel of the returned timer
+	fire func(time.Time)
+
+	mu   sync.Mutex
+	done chan struct{} // closed to end the current arming's goroutine, if any
+}
+
+func (f *forwardedTimer) Stop() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ret := f.b.Stop()
+	f.disarmLocked()
+	return ret
+}
+
+func (f *forwardedTimer) Reset(d time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disarmLocked()
+	ret := f.b.Reset(d)
+	f.armLocked()
+	return ret
+}
+
+func (f *forwardedTimer) Reschedule(at time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disarmLocked()
+	var ret bool
+	if r, ok := f.b.(rescheduler); ok {
+		ret = r.Reschedule(at)
+	} else {
+		ret = f.b.Reset(time.Until(at))
+	}
+	f.armLocked()
+	return ret
+}
+
+// armLocked starts forwarding the next value from src. It must be called
+// with mu held.
+func (f *forwardedTimer) armLocked() {
+	done := make(chan struct{})
+	f.done = done
+	go f.forward(done)
+}
+
+// disarmLocked ends the current arming's goroutine, and discards any value
+// it left unreceived. It must be called with mu held.
+func (f *forwardedTimer) disarmLocked() {
+	if f.done != nil {
+		close(f.done)
+		f.done = nil
+	}
+	// A clock with pre-Go 1.23 timers may leave a stale value in src.
+	select {
+	case <-f.src:
+	default:
+	}
+	select {
+	case <-f.c:
+	default:
+	}
+}
+
+// forward delivers one value from src, unless done is closed first.
+func (f *forwardedTimer) forward(done chan struct{}) {
+	select {
+	case v := <-f.src:
+		if f.fire != nil {
+			f.fire(v)
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		select {
+		case <-done:
+			// Stopped or reset since src fired.
+		default:
+			select {
+			case f.c <- v:
+			default:
+			}
+		}
+	case <-done:
+	}
+}
@@ -0,0 +1,101 @@
+package clock
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a forwarded timer passes its fire to the hook and its channel.
+func TestForwardTimer_Fire(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	inner := mock.NewTimer(time.Second)
+	fired := make(chan time.Time, 1)
+	timer := ForwardTimer(inner, inner, func(v time.Time) { fired <- v })
+
+	mock.Add(time.Second)
+	assert.Equal(t, time.Unix(1, 0), <-timer.C)
+	assert.Equal(t, time.Unix(1, 0), <-fired)
+}
+
+// Ensure that a fire that wasn't received is discarded by Stop and Reset,
+// rather than being received as a fire of the next arming.
+func TestForwardTimer_Drain(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	inner := mock.NewTimer(time.Second)
+	fired := make(chan struct{}, 2)
+	timer := ForwardTimer(inner, inner, func(time.Time) { fired <- struct{}{} })
+
+	mock.Add(time.Second)
+	<-fired
+	waitForwarded(timer)
+	assert.False(t, timer.Reset(time.Second))
+	select {
+	case v := <-timer.C:
+		t.Fatalf("received stale fire %v after Reset", v)
+	default:
+	}
+
+	mock.Add(time.Second)
+	<-fired
+	waitForwarded(timer)
+	assert.False(t, timer.Stop())
+	select {
+	case v := <-timer.C:
+		t.Fatalf("received stale fire %v after Stop", v)
+	default:
+	}
+
+	// The timer still works after being reset again.
+	timer.Reset(time.Second)
+	mock.Add(time.Second)
+	assert.Equal(t, time.Unix(3, 0), <-timer.C)
+}
+
+// Ensure that timers that have fired, or been stopped, leave no goroutines
+// behind.
+func TestForwardTimer_NoLeak(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	c := Chain(mock, ClockMiddleware{OnTimerFire: func(TimerKind, time.Time) {}})
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 1000; i++ {
+		ch := c.After(time.Second)
+		mock.Add(time.Second)
+		<-ch
+	}
+	for i := 0; i < 1000; i++ {
+		c.NewTimer(time.Second).Stop()
+	}
+	assert.True(t, waitGoroutines(before+10), "%d goroutines left, from %d", runtime.NumGoroutine(), before)
+}
+
+// waitForwarded waits for the forwarding goroutine of timer to finish with
+// the value it received, by taking the lock it delivers under.
+func waitForwarded(timer *Timer) {
+	f := timer.timer.(*forwardedTimer)
+	for {
+		f.mu.Lock()
+		n := len(f.c)
+		f.mu.Unlock()
+		if n > 0 {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// waitGoroutines reports whether the number of goroutines falls to n
+// within a few seconds.
+func waitGoroutines(n int) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
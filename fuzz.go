@@ -0,0 +1,80 @@
+package clock
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// ShuffleTiesOption causes timers and tickers that share a deadline to fire
+// in a pseudo-random order chosen by its seed, rather than in the order they
+// were created, to shake out assumptions about their ordering.
+type ShuffleTiesOption struct {
+	seed int64
+}
+
+// ShuffleTies returns an option that fires timers with equal deadlines in a
+// pseudo-random order chosen by seed.
+func ShuffleTies(seed int64) *ShuffleTiesOption {
+	return &ShuffleTiesOption{seed}
+}
+
+func (o *ShuffleTiesOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *ShuffleTiesOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.ties = rand.New(rand.NewSource(o.seed))
+}
+
+// FIFOTiesOption restores the default of firing timers with equal deadlines
+// in the order they were created.
+type FIFOTiesOption struct{}
+
+func (o *FIFOTiesOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *FIFOTiesOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.ties = nil
+}
+
+var FIFOTies = &FIFOTiesOption{}
+
+// FuzzAdvance advances mock by total, in pseudo-random steps of up to
+// maxStep, and fires timers with equal deadlines in a pseudo-random order.
+// Both are chosen by seed, so a failing schedule can be reproduced by running
+// again with the same seed, which is logged if tb has failed by the end of
+// the test. Once it returns, the mock fires equal deadlines in the order
+// they were created again, as with FIFOTies. It is intended for fuzz and
+// property tests:
+//
+//	f.Fuzz(func(t *testing.T, seed int64) {
+//		mock := clock.NewUnsynchronizedMock()
+//		...
+//		clock.FuzzAdvance(t, mock, seed, time.Hour, time.Minute)
+//	})
+func FuzzAdvance(tb testing.TB, mock Advancer, seed int64, total, maxStep time.Duration) {
+	tb.Helper()
+	if maxStep <= 0 {
+		tb.Fatalf("FuzzAdvance: non-positive maxStep %v", maxStep)
+	}
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			tb.Logf("clock.FuzzAdvance seed: %d", seed)
+		}
+	})
+
+	r := rand.New(rand.NewSource(seed))
+	opts := []Option{ShuffleTies(seed)}
+	for total > 0 {
+		step := time.Duration(r.Int63n(int64(maxStep))) + 1
+		if step >= total {
+			step = total
+			opts = append(opts, &PhasedOption{After: FIFOTies.UpcomingEventsOption})
+		}
+		mock.Add(step, opts...)
+		opts = nil
+		total -= step
+	}
+}
@@ -0,0 +1,90 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tieOrder returns the order in which three timers with the same deadline
+// fire under the given seed.
+func tieOrder(t *testing.T, seed int64) string {
+	clock := NewUnsynchronizedMock()
+	var order strings.Builder
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		clock.AfterFunc(time.Minute, func() { order.WriteString(name) })
+	}
+	FuzzAdvance(t, clock, seed, time.Hour, 10*time.Minute)
+	assert.Equal(t, time.Unix(3600, 0), clock.Now())
+	return order.String()
+}
+
+// Ensure that the seed determines the schedule, and that ties are permuted.
+func TestFuzzAdvance(t *testing.T) {
+	assert.Equal(t, tieOrder(t, 1), tieOrder(t, 1))
+
+	seen := map[string]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		order := tieOrder(t, seed)
+		assert.Len(t, order, 3)
+		seen[order] = true
+	}
+	assert.Greater(t, len(seen), 1, "equal deadlines always fired in the same order")
+}
+
+// Ensure that ties fire in creation order again once FuzzAdvance returns.
+func TestFuzzAdvance_RestoresFIFO(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		clock := NewUnsynchronizedMock()
+		FuzzAdvance(t, clock, seed, time.Hour, 10*time.Minute)
+		var order strings.Builder
+		for _, name := range []string{"a", "b", "c"} {
+			name := name
+			clock.AfterFunc(time.Minute, func() { order.WriteString(name) })
+		}
+		clock.Add(time.Minute)
+		assert.Equal(t, "abc", order.String(), "seed %d", seed)
+	}
+}
+
+// Ensure that the seed is logged when the test fails.
+func TestFuzzAdvance_ReportsSeed(t *testing.T) {
+	experiment := &recordingTB{TB: t}
+	FuzzAdvance(experiment, NewUnsynchronizedMock(), 42, time.Minute, time.Second)
+	experiment.failed = true
+	experiment.runCleanups()
+	assert.Equal(t, []string{"clock.FuzzAdvance seed: 42"}, experiment.logs)
+}
+
+// recordingTB captures what a helper reports, instead of reporting it.
+type recordingTB struct {
+	testing.TB
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (tb *recordingTB) Helper()          {}
+func (tb *recordingTB) Failed() bool     { return tb.failed }
+func (tb *recordingTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+func (tb *recordingTB) Logf(format string, args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprintf(format, args...))
+}
+func (tb *recordingTB) Error(args ...any) {
+	tb.failed = true
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.failed = true
+	tb.logs = append(tb.logs, fmt.Sprintf(format, args...))
+}
+
+func (tb *recordingTB) runCleanups() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
@@ -1,10 +1,18 @@
 module github.com/kraney/clock
 
-go 1.15
+go 1.18
+
+require (
+	github.com/benbjohnson/clock v1.3.5
+	github.com/jonboulle/clockwork v0.4.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/sync v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
-	github.com/stretchr/testify v1.7.0
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 )
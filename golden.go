@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to anything
+// but the empty string, makes CompareTimeline and Golden write golden files
+// instead of comparing against them, as in
+//
+//	CLOCK_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "CLOCK_UPDATE_GOLDEN"
+
+// Timeline formats the timer, ticker and clock events among events, one per
+// line, for comparison against a golden file. Checkpoint events are left
+// out, since their order depends on how goroutines are scheduled.
+func Timeline(events []Event) string {
+	var b strings.Builder
+	for _, e := range events {
+		switch e.Type {
+		case CheckpointAdded, CheckpointDone, CheckpointWaited:
+			continue
+		}
+		b.WriteString(e.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// CompareTimeline reports whether the Timeline of events matches the golden
+// file at path, failing tb with a diff if it doesn't, or if the file can't
+// be read. If UpdateGoldenEnv is set, it writes the timeline to path instead,
+// creating its directory if needed.
+func CompareTimeline(tb testing.TB, path string, events []Event) bool {
+	tb.Helper()
+	got := Timeline(events)
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Errorf("clock: writing golden timeline: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			tb.Errorf("clock: writing golden timeline: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		tb.Errorf("clock: golden timeline %s does not exist; run with %s=1 to create it", path, UpdateGoldenEnv)
+		return false
+	}
+	if err != nil {
+		tb.Errorf("clock: reading golden timeline: %v", err)
+		return false
+	}
+	if got == string(want) {
+		return true
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(want)),
+		B:        difflib.SplitLines(got),
+		FromFile: path,
+		ToFile:   "timeline",
+		Context:  3,
+	})
+	tb.Errorf("clock: timeline differs from golden timeline; run with %s=1 to update it if the change is expected:\n%s", UpdateGoldenEnv, diff)
+	return false
+}
+
+// GoldenOption compares the mock's timeline with a golden file when a test
+// ends.
+type GoldenOption struct {
+	tb   testing.TB
+	path string
+}
+
+// Golden registers a cleanup with tb that compares the timeline of the
+// mock's History, when the test ends, with the golden file at path using
+// CompareTimeline, so that a change in when the code under test starts,
+// stops and fires timers fails the test. The timeline has the mock's times
+// and timer IDs in it, so the test must set the mock to a fixed time and
+// start its timers in a fixed order. The option lifts the mock's history
+// limit, so that the whole timeline is compared. It is meant to be passed to
+// NewUnsynchronizedMock.
+func Golden(tb testing.TB, path string) *GoldenOption {
+	return &GoldenOption{tb, path}
+}
+
+func (o *GoldenOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *GoldenOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.SetHistoryLimit(-1)
+	o.tb.Cleanup(func() {
+		o.tb.Helper()
+		CompareTimeline(o.tb, o.path, mock.History())
+	})
+}
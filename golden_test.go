@@ -0,0 +1,68 @@
+package clock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runTimeline starts and fires a couple of timers, the first after d.
+func runTimeline(mock *UnsynchronizedMock, d time.Duration) {
+	timer := mock.NewTimer(d)
+	ticker := mock.NewTicker(time.Minute)
+	mock.Add(90 * time.Second)
+	<-timer.C
+	ticker.Stop()
+}
+
+func TestTimeline(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.ExpectStarts(1)
+	mock.AfterFunc(time.Second, func() {})
+	mock.Add(time.Second)
+
+	assert.Equal(t, ""+
+		"1970-01-01T00:00:00Z TimerCreated id=1 deadline=1970-01-01T00:00:01Z duration=1s\n"+
+		"1970-01-01T00:00:01Z TimerFired id=1 deadline=1970-01-01T00:00:01Z\n"+
+		"1970-01-01T00:00:01Z ClockAdvanced duration=1s\n",
+		Timeline(mock.History()))
+}
+
+// Ensure that Golden writes the timeline when updating, and fails with a
+// diff when a later run differs from it.
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "timeline.golden")
+
+	tb := &recordingTB{TB: t}
+	runTimeline(NewUnsynchronizedMock(Golden(tb, path)), time.Minute)
+	tb.runCleanups()
+	if assert.Len(t, tb.logs, 1) {
+		assert.Contains(t, tb.logs[0], "does not exist; run with CLOCK_UPDATE_GOLDEN=1")
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	tb = &recordingTB{TB: t}
+	runTimeline(NewUnsynchronizedMock(Golden(tb, path)), time.Minute)
+	tb.runCleanups()
+	assert.Empty(t, tb.logs)
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(want), "1970-01-01T00:01:00Z TimerFired id=1")
+
+	t.Setenv(UpdateGoldenEnv, "")
+	tb = &recordingTB{TB: t}
+	runTimeline(NewUnsynchronizedMock(Golden(tb, path)), time.Minute)
+	tb.runCleanups()
+	assert.Empty(t, tb.logs)
+
+	tb = &recordingTB{TB: t}
+	runTimeline(NewUnsynchronizedMock(Golden(tb, path)), 80*time.Second)
+	tb.runCleanups()
+	if assert.Len(t, tb.logs, 1) {
+		assert.Contains(t, tb.logs[0], "-1970-01-01T00:01:00Z TimerFired id=1")
+		assert.Contains(t, tb.logs[0], "+1970-01-01T00:01:20Z TimerFired id=1")
+	}
+}
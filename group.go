@@ -0,0 +1,64 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Group is an errgroup.Group whose members can be given a time budget
+// measured on a clock. When the budget runs out the members' context is
+// cancelled, so fan-out code with timeouts can be tested on a mock.
+type Group struct {
+	clock  MockableClock
+	group  *errgroup.Group
+	cancel context.CancelFunc
+}
+
+// NewGroup returns a Group, and the context its members should use. Like
+// errgroup.WithContext, the context is cancelled the first time a member
+// returns an error or Wait returns; it is also cancelled when a WaitTimeout
+// budget runs out.
+func NewGroup(ctx context.Context, c MockableClock) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	group, ctx := errgroup.WithContext(ctx)
+	return &Group{clock: c, group: group, cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine, as errgroup.Group.Go does.
+func (g *Group) Go(f func() error) {
+	g.group.Go(f)
+}
+
+// SetLimit limits the number of members running at once, as
+// errgroup.Group.SetLimit does.
+func (g *Group) SetLimit(n int) {
+	g.group.SetLimit(n)
+}
+
+// Wait blocks until every member has returned, then returns the first error
+// any of them returned.
+func (g *Group) Wait() error {
+	defer g.cancel()
+	return g.group.Wait()
+}
+
+// WaitTimeout is like Wait, but if the members haven't all returned after d
+// on the group's clock, it cancels their context, waits for them to return,
+// and returns an error wrapping context.DeadlineExceeded.
+func (g *Group) WaitTimeout(d time.Duration) error {
+	var expired int32
+	timer := g.clock.AfterFunc(d, func() {
+		atomic.StoreInt32(&expired, 1)
+		g.cancel()
+	})
+	err := g.Wait()
+	timer.Stop()
+	if atomic.LoadInt32(&expired) == 1 {
+		return fmt.Errorf("group timed out after %v: %w", d, context.DeadlineExceeded)
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a group whose budget runs out cancels its members and reports
+// the timeout.
+func TestGroup_WaitTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	g, ctx := NewGroup(context.Background(), mock)
+	for i := 0; i < 3; i++ {
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+
+	mock.ExpectStarts(1)
+	go mock.Add(time.Minute, WaitBefore)
+	err := g.WaitTimeout(time.Minute)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "group timed out after 1m0s: context deadline exceeded")
+}
+
+// Ensure that a group that finishes within its budget returns its members'
+// error and leaves no timer behind.
+func TestGroup_WaitTimeout_InTime(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	g, ctx := NewGroup(context.Background(), mock)
+	failed := errors.New("failed")
+	g.Go(func() error { return nil })
+	g.Go(func() error { return failed })
+
+	assert.Equal(t, failed, g.WaitTimeout(time.Minute))
+	assert.Error(t, ctx.Err())
+	assert.Empty(t, mock.PendingTimers())
+}
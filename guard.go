@@ -0,0 +1,99 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// GuardEpoch is the time a mock in guard mode is moved to: far from any
+// real time, so that a time that came from the real clock stands out from
+// the mock's.
+var GuardEpoch = time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// guardTolerance is how close a time must be to the real time, and how far
+// from the mock's, for it to look like it was read from the real clock.
+const guardTolerance = 24 * time.Hour
+
+// GuardOption puts a mock in guard mode.
+type GuardOption struct {
+	tb testing.TB
+}
+
+// guard is what a mock in guard mode reports to.
+type guard struct {
+	tb testing.TB
+}
+
+// Guard puts the mock in guard mode, to find code under test that reads the
+// real time rather than the injected clock. It moves the mock to
+// GuardEpoch, shifting pending timers with it, and then fails tb, with the
+// call stack, whenever a time that looks like it came from time.Now is
+// passed to Since, AfterAt, AtFunc or SleepUntil, as when code mixes a
+// deadline from the real clock with the mock. Use CheckVirtual on times the
+// code produces, such as timestamps in its output, to catch call sites that
+// never touch the mock at all. A mock that is already in guard mode is left
+// as it is, so the option can be passed to each Add or Set of a mock from
+// NewMock.
+func Guard(tb testing.TB) *GuardOption {
+	return &GuardOption{tb}
+}
+
+func (o *GuardOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *GuardOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.guard.Load() != nil {
+		return
+	}
+	mock.shiftLocked(GuardEpoch.Sub(mock.now))
+	mock.now = GuardEpoch
+	mock.publishNowLocked()
+	mock.guard.Store(&guard{o.tb})
+}
+
+// guardTime fails the guard's test if t, passed to the mock's op, looks
+// like it was read from the real clock. It does nothing unless the mock is
+// in guard mode.
+func (m *UnsynchronizedMock) guardTime(op string, t time.Time) {
+	g, _ := m.guard.Load().(*guard)
+	if g == nil || !looksReal(t, m.Now()) {
+		return
+	}
+	g.tb.Helper()
+	g.tb.Errorf("clock: %s(%v) was passed what looks like the real time, not the mock's %v; a caller may be using the time package directly:\n%s",
+		op, t.Format(time.RFC3339Nano), m.Now().Format(time.RFC3339Nano), formatStack(callers()))
+}
+
+// CheckVirtual reports whether each of ts could have come from c, failing
+// tb for any that look like they were read from the real clock instead:
+// within a day of time.Now, while c is further than that from it. With a
+// mock in guard mode, or otherwise set far from the real time, this finds
+// call sites that use time.Now where they should use the clock. If c is
+// close to the real time, every time passes.
+func CheckVirtual(tb testing.TB, c NowClock, ts ...time.Time) bool {
+	tb.Helper()
+	ok := true
+	now := c.Now()
+	for _, t := range ts {
+		if looksReal(t, now) {
+			tb.Errorf("clock: %v looks like the real time, not the clock's %v; was it read with time.Now?",
+				t.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// looksReal reports whether t is close to the real time and far from the
+// clock's time now.
+func looksReal(t, now time.Time) bool {
+	return absDuration(time.Since(t)) < guardTolerance && absDuration(t.Sub(now)) > guardTolerance
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
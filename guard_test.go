@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that guard mode moves the mock, and its timers, to the epoch.
+func TestMock_Guard(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	fired := false
+	mock.AfterFunc(time.Second, func() { fired = true })
+	mock.Add(0, Guard(t))
+	assert.Equal(t, GuardEpoch, mock.Now())
+	mock.Add(time.Second)
+	assert.True(t, fired)
+
+	// Passing the option again doesn't move the mock back.
+	mock.Add(time.Second, Guard(t))
+	assert.Equal(t, GuardEpoch.Add(2*time.Second), mock.Now())
+}
+
+// Ensure that real times passed to the mock, or produced by code under
+// test, fail the test.
+func TestMock_Guard_RealTime(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(Guard(tb))
+
+	mock.Since(mock.Now().Add(-time.Hour))
+	mock.AtFunc(mock.Now().Add(time.Hour), func() {})
+	assert.False(t, tb.failed)
+	assert.True(t, CheckVirtual(tb, mock, mock.Now()))
+
+	mock.Since(time.Now())
+	mock.AtFunc(time.Now().Add(time.Hour), func() {})
+	assert.False(t, CheckVirtual(tb, mock, mock.Now(), time.Now()))
+	if assert.Len(t, tb.logs, 3) {
+		assert.Contains(t, tb.logs[0], "clock: Since(")
+		assert.Contains(t, tb.logs[0], "TestMock_Guard_RealTime")
+		assert.Contains(t, tb.logs[1], "clock: AtFunc(")
+		assert.Contains(t, tb.logs[2], "was it read with time.Now?")
+	}
+
+	// A clock close to the real time can't tell.
+	tb = &recordingTB{TB: t}
+	assert.True(t, CheckVirtual(tb, New(), time.Now()))
+}
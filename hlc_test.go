@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that timestamps increase even when physical time doesn't.
+func TestHLC_Now(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	hlc := NewHLC(clock, 0)
+
+	a := hlc.Now()
+	b := hlc.Now()
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(0, 0), Logical: 1}, b)
+	assert.True(t, a.Before(b))
+
+	clock.Add(time.Second)
+	c := hlc.Now()
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(1, 0)}, c)
+	assert.True(t, b.Before(c))
+}
+
+// Ensure that receiving a message from a clock that is ahead preserves
+// causality.
+func TestHLC_Update(t *testing.T) {
+	sim := NewSimulation(time.Unix(0, 0))
+	behind := NewHLC(sim.NewClock(0, 0), 0)
+	ahead := NewHLC(sim.NewClock(5*time.Second, 0), 0)
+
+	sent := ahead.Now()
+	received, err := behind.Update(sent)
+	assert.NoError(t, err)
+	assert.True(t, sent.Before(received))
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(5, 0), Logical: 1}, received)
+
+	// Local events stay after the received message until physical time
+	// catches up.
+	sim.Add(time.Second)
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(5, 0), Logical: 2}, behind.Now())
+	sim.Add(5 * time.Second)
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(6, 0)}, behind.Now())
+}
+
+// Ensure that remote timestamps too far ahead are rejected.
+func TestHLC_Update_MaxOffset(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	hlc := NewHLC(clock, time.Second)
+
+	_, err := hlc.Update(HLCTimestamp{Wall: time.Unix(2, 0)})
+	assert.Error(t, err)
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(0, 0)}, hlc.Now())
+
+	ts, err := hlc.Update(HLCTimestamp{Wall: time.Unix(1, 0), Logical: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, HLCTimestamp{Wall: time.Unix(1, 0), Logical: 4}, ts)
+	assert.Equal(t, 0, ts.Compare(ts))
+}
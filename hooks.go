@@ -0,0 +1,116 @@
+package clock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// OnAdvance registers fn to be called after each advance of the clock, by
+// Add, Set and their variants, with the times the clock moved from and to.
+// It runs on the advancing goroutine once the timers due have fired, so it
+// can log a trace of virtual time or check invariants each time the clock
+// moves. Hooks run in the order they were registered.
+func (m *UnsynchronizedMock) OnAdvance(fn func(from, to time.Time)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onAdvance = append(m.onAdvance[:len(m.onAdvance):len(m.onAdvance)], fn)
+}
+
+// OnBeforeAdvance registers fn to be called before each advance of the
+// clock, with the times it is about to move from and to. If fn returns an
+// error, the advance is vetoed: no timers fire, the clock stays where it
+// is, the AdvanceReport's Vetoed is set to the error, and an AdvanceVetoed
+// event is logged. A hook that should fail the test must do so itself.
+func (m *UnsynchronizedMock) OnBeforeAdvance(fn func(from, to time.Time) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onBeforeAdvance = append(m.onBeforeAdvance[:len(m.onBeforeAdvance):len(m.onBeforeAdvance)], fn)
+}
+
+// OnTimerFire registers fn to be called each time a timer, ticker or
+// registered event is about to fire during an advance, with a description
+// of it and the clock's time.
+func (m *UnsynchronizedMock) OnTimerFire(fn func(t TimerInfo, now time.Time)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onTimerFire = append(m.onTimerFire[:len(m.onTimerFire):len(m.onTimerFire)], fn)
+}
+
+// vetoAdvance runs the OnBeforeAdvance hooks for an advance from from to to,
+// and returns the first error one of them returns.
+func (m *UnsynchronizedMock) vetoAdvance(from, to time.Time) error {
+	m.mu.Lock()
+	hooks := m.onBeforeAdvance
+	m.mu.Unlock()
+	for _, fn := range hooks {
+		if err := fn(from, to); err != nil {
+			m.logEvent(Event{Type: AdvanceVetoed, Time: from, Duration: to.Sub(from)})
+			return err
+		}
+	}
+	return nil
+}
+
+// advanced runs the OnAdvance hooks for an advance from from to to.
+func (m *UnsynchronizedMock) advanced(from, to time.Time) {
+	m.mu.Lock()
+	hooks := m.onAdvance
+	m.mu.Unlock()
+	for _, fn := range hooks {
+		fn(from, to)
+	}
+}
+
+// firingLocked returns a function that runs the OnTimerFire hooks for t at
+// now, to be called once mu is released. It must be called with mu held.
+func (m *UnsynchronizedMock) firingLocked(t clockTimer, now time.Time) func() {
+	hooks := m.onTimerFire
+	if len(hooks) == 0 {
+		return func() {}
+	}
+	info := t.info()
+	return func() {
+		for _, fn := range hooks {
+			fn(info, now)
+		}
+	}
+}
+
+// MaxVirtualTimeOption caps how far the mock can advance.
+type MaxVirtualTimeOption struct {
+	tb  testing.TB
+	max time.Duration
+}
+
+// MaxVirtualTime fails tb if the mock is asked to advance more than max past
+// the time the option is applied, and vetoes that advance and any later
+// one past the cap, as OnBeforeAdvance does. It catches runaway retry loops
+// that would otherwise silently consume years of virtual time. Run and
+// Eventually stop when an advance is vetoed. Only the first cap applied to
+// a mock counts, so the option can be passed to each Add or Set of a mock
+// from NewMock without adding another hook each time.
+func MaxVirtualTime(tb testing.TB, max time.Duration) *MaxVirtualTimeOption {
+	return &MaxVirtualTimeOption{tb, max}
+}
+
+func (o *MaxVirtualTimeOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *MaxVirtualTimeOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	capped := mock.capped
+	mock.capped = true
+	limit := mock.now.Add(o.max)
+	mock.mu.Unlock()
+	if capped {
+		return
+	}
+	mock.OnBeforeAdvance(func(from, to time.Time) error {
+		if !to.After(limit) {
+			return nil
+		}
+		err := fmt.Errorf("clock: advance to %v passes MaxVirtualTime of %v, at %v", to, o.max, limit)
+		o.tb.Error(err)
+		return err
+	})
+}
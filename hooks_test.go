@@ -0,0 +1,110 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMock_OnAdvance(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	var trace []string
+	mock.OnAdvance(func(from, to time.Time) {
+		trace = append(trace, "advance "+to.Sub(from).String())
+	})
+	mock.OnTimerFire(func(info TimerInfo, now time.Time) {
+		trace = append(trace, string(info.Kind)+" "+now.Sub(start).String())
+	})
+	mock.AfterFunc(time.Second, func() {})
+	ticker := mock.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	mock.Add(3 * time.Second)
+	mock.Set(start.Add(5 * time.Second))
+	assert.Equal(t, []string{"AfterFunc 1s", "Ticker 2s", "advance 3s", "Ticker 4s", "advance 2s"}, trace)
+}
+
+func TestMock_OnBeforeAdvance(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	limit := start.Add(time.Minute)
+	errPastLimit := errors.New("past limit")
+	mock.OnBeforeAdvance(func(from, to time.Time) error {
+		if to.After(limit) {
+			return errPastLimit
+		}
+		return nil
+	})
+	advanced := 0
+	mock.OnAdvance(func(from, to time.Time) { advanced++ })
+	fired := false
+	mock.AfterFunc(time.Second, func() { fired = true })
+
+	report := mock.AddReport(2 * time.Minute)
+	assert.Equal(t, errPastLimit, report.Vetoed)
+	assert.Equal(t, start, report.To)
+	assert.Equal(t, start, mock.Now())
+	assert.False(t, fired)
+	assert.Equal(t, 0, advanced)
+	history := mock.History()
+	assert.Equal(t, AdvanceVetoed, history[len(history)-1].Type)
+
+	mock.Add(time.Minute)
+	assert.True(t, fired)
+	assert.Equal(t, 1, advanced)
+}
+
+// Ensure that an advance past the cap fails the test and leaves the clock
+// where it was.
+func TestMock_MaxVirtualTime(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(MaxVirtualTime(tb, time.Hour))
+	start := mock.Now()
+
+	mock.Add(time.Hour)
+	assert.False(t, tb.failed)
+	mock.Add(time.Second)
+	assert.True(t, tb.failed)
+	assert.Equal(t, start.Add(time.Hour), mock.Now())
+	if assert.Len(t, tb.logs, 1) {
+		assert.Contains(t, tb.logs[0], "passes MaxVirtualTime of 1h0m0s")
+	}
+}
+
+// Ensure that passing the option to each advance keeps the first cap, and
+// doesn't pile up hooks.
+func TestMock_MaxVirtualTime_EachAdvance(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock()
+	for i := 0; i < 3; i++ {
+		mock.Add(30*time.Minute, MaxVirtualTime(tb, time.Hour))
+	}
+	assert.Equal(t, time.Hour, mock.Now().Sub(time.Unix(0, 0)))
+	assert.Len(t, mock.onBeforeAdvance, 1)
+	assert.Len(t, tb.logs, 1)
+}
+
+// Ensure that Run stops a runaway loop at the cap.
+func TestMock_MaxVirtualTime_Run(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(MaxVirtualTime(tb, time.Hour))
+	err := mock.Run(func(ctx context.Context) {
+		for ctx.Err() == nil {
+			mock.SleepContext(ctx, time.Minute)
+		}
+	}, 0)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "passes MaxVirtualTime")
+	}
+	assert.True(t, tb.failed)
+	assert.Equal(t, time.Hour, mock.Now().Sub(time.Unix(0, 0)))
+
+	tb = &recordingTB{TB: t}
+	mock = NewUnsynchronizedMock(MaxVirtualTime(tb, time.Minute))
+	assert.False(t, Eventually(tb, mock, func() bool { return false }, time.Hour, time.Second))
+	assert.Contains(t, tb.logs[len(tb.logs)-1], "clock stopped advancing after 1m0s")
+}
@@ -0,0 +1,235 @@
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// intervalSchedule is a Schedule parsed from an ISO 8601 repeating interval.
+// Its times are start plus whole multiples of the period, which has a
+// nominal calendar part and an exact part.
+type intervalSchedule struct {
+	start               time.Time
+	years, months, days int
+	exact               time.Duration
+	count               int // number of times to run, or -1 for no limit
+}
+
+// ParseRepeatingInterval parses an ISO 8601 repeating interval, such as
+// "R5/2024-01-01T00:00:00Z/PT1H", into a Schedule that runs at the start of
+// each repetition. The interval may be given as start/duration,
+// duration/end or start/end, with times in RFC 3339 form. "Rn" runs n times
+// and "R" or "R-1" repeats without limit, except with duration/end, which
+// needs a count to know where to start.
+//
+// Years, months, weeks and days in the duration are calendar units, counted
+// in the location of the start time, and each time is measured from the
+// start rather than the one before, so "P1M" from January 31 runs on the
+// last day of shorter months and on the 31st again after them. Only the
+// seconds may have a fraction.
+func ParseRepeatingInterval(spec string) (Schedule, error) {
+	parts := strings.Split(strings.TrimSpace(spec), "/")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "R") {
+		return nil, fmt.Errorf("interval %q: expected Rn/start/duration, Rn/duration/end or Rn/start/end", spec)
+	}
+	s := &intervalSchedule{count: -1}
+	if n := parts[0][1:]; n != "" && n != "-1" {
+		count, err := strconv.Atoi(n)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("interval %q: invalid repetitions %q", spec, n)
+		}
+		s.count = count
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(parts[1], "P"):
+		if s.count < 0 {
+			return nil, fmt.Errorf("interval %q: duration/end needs a number of repetitions", spec)
+		}
+		if err = s.parsePeriod(parts[1]); err != nil {
+			return nil, fmt.Errorf("interval %q: %w", spec, err)
+		}
+		end, err := time.Parse(time.RFC3339Nano, parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("interval %q: invalid end: %w", spec, err)
+		}
+		s.start = s.at(end, -s.count)
+	case strings.HasPrefix(parts[2], "P"):
+		if s.start, err = time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+			return nil, fmt.Errorf("interval %q: invalid start: %w", spec, err)
+		}
+		if err = s.parsePeriod(parts[2]); err != nil {
+			return nil, fmt.Errorf("interval %q: %w", spec, err)
+		}
+	default:
+		if s.start, err = time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+			return nil, fmt.Errorf("interval %q: invalid start: %w", spec, err)
+		}
+		end, err := time.Parse(time.RFC3339Nano, parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("interval %q: invalid end: %w", spec, err)
+		}
+		s.exact = end.Sub(s.start)
+	}
+	if s.years == 0 && s.months == 0 && s.days == 0 && s.exact <= 0 {
+		return nil, fmt.Errorf("interval %q: non-positive duration", spec)
+	}
+	return s, nil
+}
+
+// parsePeriod parses an ISO 8601 duration, PnYnMnWnDTnHnMnS, into s.
+func (s *intervalSchedule) parsePeriod(p string) error {
+	rest, inTime := p[1:], false
+	if rest == "" || rest == "T" || strings.HasSuffix(rest, "T") {
+		return fmt.Errorf("invalid duration %q", p)
+	}
+	for rest != "" {
+		if rest[0] == 'T' && !inTime {
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return fmt.Errorf("invalid duration %q", p)
+		}
+		num, unit := strings.Replace(rest[:i], ",", ".", 1), rest[i]
+		rest = rest[i+1:]
+		if inTime && unit == 'S' {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", p)
+			}
+			s.exact += time.Duration(f * float64(time.Second))
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", p)
+		}
+		switch {
+		case !inTime && unit == 'Y':
+			s.years += n
+		case !inTime && unit == 'M':
+			s.months += n
+		case !inTime && unit == 'W':
+			s.days += 7 * n
+		case !inTime && unit == 'D':
+			s.days += n
+		case inTime && unit == 'H':
+			s.exact += time.Duration(n) * time.Hour
+		case inTime && unit == 'M':
+			s.exact += time.Duration(n) * time.Minute
+		default:
+			return fmt.Errorf("invalid duration %q", p)
+		}
+	}
+	return nil
+}
+
+// at returns the time k periods after from. Adding months keeps to the end
+// of a shorter month rather than overflowing into the next, as AddDate would.
+func (s *intervalSchedule) at(from time.Time, k int) time.Time {
+	y, m, d := from.Date()
+	hour, min, sec := from.Clock()
+	month := time.Date(y+k*s.years, m+time.Month(k*s.months), 1, 12, 0, 0, 0, from.Location())
+	if last := month.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	t := time.Date(month.Year(), month.Month(), d+k*s.days, hour, min, sec, from.Nanosecond(), from.Location())
+	return t.Add(time.Duration(k) * s.exact)
+}
+
+func (s *intervalSchedule) Next(t time.Time) time.Time {
+	k := 0
+	if t.After(s.start) {
+		// estimate from the average length of the period, then correct
+		approx := time.Duration(s.years)*8765*time.Hour + time.Duration(s.months)*730*time.Hour +
+			time.Duration(s.days)*24*time.Hour + s.exact
+		k = int(t.Sub(s.start) / approx)
+		for k > 0 && s.at(s.start, k-1).After(t) {
+			k--
+		}
+	}
+	for !s.at(s.start, k).After(t) {
+		k++
+	}
+	if s.count >= 0 && k >= s.count {
+		return time.Time{}
+	}
+	return s.at(s.start, k)
+}
+
+// ScheduleTicker delivers the times of a Schedule on a channel, like a
+// Ticker whose ticks follow the schedule rather than a fixed interval.
+type ScheduleTicker struct {
+	// C receives the current time at each scheduled time. As with a
+	// Ticker, a value is dropped if the previous one hasn't been received.
+	// C is closed after the last time, if the schedule ends.
+	C <-chan time.Time
+
+	c        chan time.Time
+	clock    MockableClock
+	schedule Schedule
+	mu       sync.Mutex
+	timer    MockableTimer
+	stopped  bool
+}
+
+// NewScheduleTicker returns a ScheduleTicker that uses c to tick at each
+// time in s after the current time. It holds a single timer on the clock,
+// which it resets after every tick.
+func NewScheduleTicker(c MockableClock, s Schedule) *ScheduleTicker {
+	ch := make(chan time.Time, 1)
+	t := &ScheduleTicker{C: ch, c: ch, clock: c, schedule: s}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scheduleLocked(c.Now())
+	return t
+}
+
+// scheduleLocked sets the timer for the next time after now, or closes the
+// channel if there is none. It must be called with mu held.
+func (t *ScheduleTicker) scheduleLocked(now time.Time) {
+	next := t.schedule.Next(now)
+	switch {
+	case next.IsZero():
+		t.stopped = true
+		close(t.c)
+	case t.timer == nil:
+		t.timer = t.clock.AfterFunc(next.Sub(now), t.tick)
+	default:
+		t.timer.Reset(next.Sub(now))
+	}
+}
+
+func (t *ScheduleTicker) tick() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	now := t.clock.Now()
+	select {
+	case t.c <- now:
+	default:
+	}
+	t.scheduleLocked(now)
+}
+
+// Stop turns off the ticker. Like Ticker.Stop, it does not close the
+// channel.
+func (t *ScheduleTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.stopped = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
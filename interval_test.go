@@ -0,0 +1,102 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepeatingInterval_Next(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"R5/2024-01-01T00:00:00Z/PT1H", start.Add(-time.Hour), start},
+		{"R5/2024-01-01T00:00:00Z/PT1H", start, start.Add(time.Hour)},
+		{"R5/2024-01-01T00:00:00Z/PT1H", start.Add(210 * time.Minute), start.Add(4 * time.Hour)},
+		{"R5/2024-01-01T00:00:00Z/PT1H", start.Add(4 * time.Hour), time.Time{}},
+		{"R0/2024-01-01T00:00:00Z/PT1H", start.Add(-time.Hour), time.Time{}},
+		{"R/2024-01-01T00:00:00Z/P1D", start.AddDate(10, 0, 0), start.AddDate(10, 0, 1)},
+		{"R-1/2024-01-01T00:00:00Z/PT1M30.5S", start, start.Add(90500 * time.Millisecond)},
+		{"R/2024-01-01T00:00:00Z/P1W", start, start.AddDate(0, 0, 7)},
+		{"R/2024-01-01T00:00:00Z/P1DT12H", start, start.Add(36 * time.Hour)},
+		{"R2/PT1H/2024-01-01T02:00:00Z", start.Add(-time.Hour), start},
+		{"R2/PT1H/2024-01-01T02:00:00Z", start.Add(time.Hour), time.Time{}},
+		{"R3/2024-01-01T00:00:00Z/2024-01-01T00:15:00Z", start, start.Add(15 * time.Minute)},
+		{"R/2024-01-01T09:00:00+01:00/P1D", start, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseRepeatingInterval(tt.spec)
+		if !assert.NoError(t, err, tt.spec) {
+			continue
+		}
+		assert.True(t, tt.want.Equal(s.Next(tt.from)), "%s: got %v, want %v", tt.spec, s.Next(tt.from), tt.want)
+	}
+}
+
+func TestParseRepeatingInterval_Months(t *testing.T) {
+	s, err := ParseRepeatingInterval("R/2024-01-31T00:00:00Z/P1M")
+	assert.NoError(t, err)
+	var got []string
+	for next := s.Next(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)); len(got) < 4; next = s.Next(next) {
+		got = append(got, next.Format("2006-01-02"))
+	}
+	assert.Equal(t, []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}, got)
+}
+
+func TestParseRepeatingInterval_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"2024-01-01T00:00:00Z/PT1H",
+		"R5/2024-01-01T00:00:00Z",
+		"Rx/2024-01-01T00:00:00Z/PT1H",
+		"R-2/2024-01-01T00:00:00Z/PT1H",
+		"R5/2024-01-01/PT1H",
+		"R5/2024-01-01T00:00:00Z/P",
+		"R5/2024-01-01T00:00:00Z/PT",
+		"R5/2024-01-01T00:00:00Z/P1H",
+		"R5/2024-01-01T00:00:00Z/PT1D",
+		"R5/2024-01-01T00:00:00Z/P1.5D",
+		"R5/2024-01-01T00:00:00Z/PT0S",
+		"R/PT1H/2024-01-01T00:00:00Z",
+		"R5/2024-01-01T01:00:00Z/2024-01-01T00:00:00Z",
+	} {
+		_, err := ParseRepeatingInterval(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduleTicker(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s, err := ParseRepeatingInterval("R3/2024-01-01T00:30:00Z/PT1H")
+	assert.NoError(t, err)
+
+	ticker := NewScheduleTicker(mock, s)
+	var got []time.Time
+	for i := 0; i < 3; i++ {
+		mock.Add(time.Hour)
+		got = append(got, <-ticker.C)
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 2, 30, 0, 0, time.UTC),
+	}, got)
+	_, ok := <-ticker.C
+	assert.False(t, ok, "channel not closed after the last time")
+	ticker.Stop()
+
+	ticker = NewScheduleTicker(mock, Daily(9, 0, time.UTC))
+	ticker.Stop()
+	mock.Add(48 * time.Hour)
+	select {
+	case <-ticker.C:
+		t.Error("stopped ticker ticked")
+	default:
+	}
+	assert.Empty(t, mock.PendingTimers())
+}
@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"math/rand"
+	"time"
+)
+
+// SeedOption reseeds the mock's source of randomness.
+type SeedOption struct {
+	seed int64
+}
+
+// Seed returns an option that reseeds the random source that Jitter and Rand
+// use for the mock, so that a test can pick, or vary, the delays it
+// produces. A mock that has not been seeded uses seed 0, so its delays are
+// the same on every run.
+func Seed(seed int64) *SeedOption {
+	return &SeedOption{seed}
+}
+
+func (o *SeedOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *SeedOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.rng = rand.New(rand.NewSource(o.seed))
+}
+
+// mockSource is a rand.Source drawing on a mock's seeded source.
+type mockSource struct {
+	m *UnsynchronizedMock
+}
+
+func (s mockSource) Int63() int64 {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	if s.m.rng == nil {
+		s.m.rng = rand.New(rand.NewSource(0))
+	}
+	return s.m.rng.Int63()
+}
+
+func (s mockSource) Seed(seed int64) {
+	(&SeedOption{seed}).UpcomingEventsOption(s.m)
+}
+
+func (m *UnsynchronizedMock) randSource() rand.Source { return mockSource{m} }
+
+// globalSource is a rand.Source drawing on the math/rand functions, which
+// are safe for concurrent use.
+type globalSource struct{}
+
+func (globalSource) Int63() int64    { return rand.Int63() }
+func (globalSource) Seed(seed int64) {}
+
+// Rand returns a source of randomness for code that runs on c. If c is a
+// mock, it draws on the mock's seeded source, so that code which randomizes
+// its delays can be tested reproducibly; otherwise it draws on math/rand's
+// global source. It is safe for concurrent use, except for Read.
+func Rand(c MockableClock) *rand.Rand {
+	if m, ok := c.(interface{ randSource() rand.Source }); ok {
+		return rand.New(m.randSource())
+	}
+	return rand.New(globalSource{})
+}
+
+// Jitter returns d randomized by up to fraction of its length in either
+// direction, using Rand(c), so that a fraction of 0.1 turns 10s into a
+// duration between 9s and 11s. On a mock, the same seed gives the same
+// durations, and so the same virtual schedule.
+func Jitter(c MockableClock, d time.Duration, fraction float64) time.Duration {
+	r := Rand(c).Float64()
+	return time.Duration(float64(d) * (1 + fraction*(2*r-1)))
+}
@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jitters(c MockableClock) []time.Duration {
+	var ret []time.Duration
+	for i := 0; i < 10; i++ {
+		ret = append(ret, Jitter(c, 10*time.Second, 0.1))
+	}
+	return ret
+}
+
+func TestJitter(t *testing.T) {
+	seeded := jitters(NewUnsynchronizedMock(Seed(42)))
+	for _, d := range seeded {
+		assert.GreaterOrEqual(t, d, 9*time.Second)
+		assert.Less(t, d, 11*time.Second)
+	}
+	assert.Equal(t, seeded, jitters(NewUnsynchronizedMock(Seed(42))), "same seed gave different delays")
+	assert.NotEqual(t, seeded, jitters(NewUnsynchronizedMock(Seed(43))), "different seeds gave the same delays")
+	assert.Equal(t, jitters(NewMock(t, 0)), jitters(NewUnsynchronizedMock()), "unseeded mocks differ")
+
+	// reseeding starts the sequence again
+	mock := NewUnsynchronizedMock(Seed(42))
+	jitters(mock)
+	Seed(42).UpcomingEventsOption(mock)
+	assert.Equal(t, seeded, jitters(mock))
+
+	for _, d := range jitters(New()) {
+		assert.GreaterOrEqual(t, d, 9*time.Second)
+		assert.Less(t, d, 11*time.Second)
+	}
+	assert.Equal(t, 10*time.Second, Jitter(New(), 10*time.Second, 0))
+}
+
+func TestRand(t *testing.T) {
+	a, b := Rand(NewUnsynchronizedMock(Seed(7))), Rand(NewUnsynchronizedMock(Seed(7)))
+	assert.Equal(t, a.Perm(10), b.Perm(10))
+
+	// JitteredBackoff can draw on the mock too.
+	policy := &JitteredBackoff{Policy: ConstantBackoff{Interval: time.Second}, Fraction: 0.5, Rand: Rand(NewUnsynchronizedMock(Seed(7)))}
+	d, _ := policy.Backoff(1)
+	assert.Equal(t, Jitter(NewUnsynchronizedMock(Seed(7)), time.Second, 0.5), d)
+}
@@ -0,0 +1,90 @@
+package clock
+
+import (
+	"time"
+)
+
+// JumpPolicy says what happens to pending timers when the clock jumps.
+type JumpPolicy int
+
+const (
+	// JumpShift moves every pending deadline by as much as the clock moves,
+	// so no timer fires. It is what happens to Go's timers, which run on the
+	// monotonic clock, when a machine sleeps through a suspend.
+	JumpShift JumpPolicy = iota
+	// JumpExpire fires every timer that is overdue once, at the new time,
+	// and tickers carry on from the new time, as if a wall-clock scheduler
+	// catching up after a suspend had skipped the missed ticks.
+	JumpExpire
+)
+
+// JumpOption makes an advance jump to the new time rather than run through
+// every timer on the way.
+type JumpOption struct {
+	policy JumpPolicy
+}
+
+// Jump makes Set or Add move the clock without firing the timers in between
+// one by one, to simulate wall time jumping, such as when a machine wakes
+// from suspend. policy says what becomes of the timers that were pending.
+func Jump(policy JumpPolicy) *JumpOption {
+	return &JumpOption{policy}
+}
+
+func (o *JumpOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *JumpOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	policy := o.policy
+	mock.jump = &policy
+}
+
+// jumpTo moves the clock to t under policy. If report is not nil, the timers
+// executed are added to it.
+func (m *UnsynchronizedMock) jumpTo(t time.Time, policy JumpPolicy, report *AdvanceReport) {
+	m.mu.Lock()
+	from := m.now
+	m.now = t
+	m.publishNowLocked()
+	var due []clockTimer
+	var firing []func()
+	switch policy {
+	case JumpShift:
+		m.shiftLocked(t.Sub(from))
+	case JumpExpire:
+		for _, timer := range m.timers.sorted() {
+			if timer.Next().After(t) {
+				break
+			}
+			due = append(due, timer)
+			firing = append(firing, m.firingLocked(timer, t))
+			if report != nil {
+				report.Fired = append(report.Fired, timer.info())
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	for i, timer := range due {
+		firing[i]()
+		timer.Tick(t)
+	}
+	m.logEvent(Event{Type: ClockAdvanced, Time: t, Duration: t.Sub(from)})
+}
+
+// shiftLocked moves every pending deadline by delta. Shifting them alike
+// keeps them in the same order, so the queue needs no fixing. It must be
+// called with mu held.
+func (m *UnsynchronizedMock) shiftLocked(delta time.Duration) {
+	for _, timer := range m.timers.heap {
+		switch timer := timer.(type) {
+		case *internalTimer:
+			timer.next = timer.next.Add(delta)
+		case *internalTicker:
+			timer.next = timer.next.Add(delta)
+		case *registeredEvent:
+			timer.next = timer.next.Add(delta)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that JumpShift moves pending deadlines along with the clock.
+func TestMock_Jump_Shift(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	timer := mock.NewTimer(time.Minute)
+	ticker := mock.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	mock.Set(time.Unix(3600, 0), Jump(JumpShift))
+	assert.Equal(t, time.Unix(3600, 0), mock.Now())
+	assert.Len(t, timer.C, 0)
+	assert.Len(t, ticker.C, 0)
+
+	mock.Add(time.Second)
+	assert.Equal(t, time.Unix(3601, 0), <-ticker.C)
+	mock.Add(59 * time.Second)
+	assert.Equal(t, time.Unix(3660, 0), <-timer.C)
+}
+
+// Ensure that JumpExpire fires overdue timers once at the new time, and
+// tickers carry on from it.
+func TestMock_Jump_Expire(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	timer := mock.NewTimer(time.Minute)
+	later := mock.NewTimer(2 * time.Hour)
+	ticker := mock.NewTicker(time.Second)
+	defer ticker.Stop()
+	var ran []time.Time
+	mock.AfterFunc(time.Minute, func() { ran = append(ran, mock.Now()) })
+
+	report := mock.SetReport(time.Unix(3600, 0), Jump(JumpExpire))
+	assert.Equal(t, time.Unix(3600, 0), <-timer.C)
+	assert.Equal(t, time.Unix(3600, 0), <-ticker.C)
+	assert.Equal(t, []time.Time{time.Unix(3600, 0)}, ran)
+	assert.Equal(t, 3, len(report.Fired))
+	assert.Len(t, later.C, 0)
+
+	mock.Add(time.Second)
+	assert.Equal(t, time.Unix(3601, 0), <-ticker.C)
+	assert.Len(t, ticker.C, 0)
+}
+
+// Ensure that a jump applies only to the advance it is passed to.
+func TestMock_Jump_Once(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ticker := mock.NewTicker(time.Second)
+	defer ticker.Stop()
+	fired := 0
+	mock.SetLogger(func(e Event) {
+		if e.Type == TickerFired {
+			fired++
+		}
+	})
+
+	mock.Add(time.Hour, Jump(JumpShift))
+	mock.Add(3 * time.Second)
+	assert.Equal(t, 3, fired)
+}
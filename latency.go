@@ -0,0 +1,105 @@
+package clock
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyRecorder measures durations on a clock and reports their
+// quantiles. It keeps every sample, so quantiles are exact, and code that
+// instruments its latency can be tested against the exact virtual durations
+// of a mock.
+type LatencyRecorder struct {
+	clock   NowClock
+	mu      sync.Mutex
+	samples []time.Duration
+	sorted  bool
+}
+
+// NewLatencyRecorder returns a LatencyRecorder that measures durations on c.
+func NewLatencyRecorder(c NowClock) *LatencyRecorder {
+	return &LatencyRecorder{clock: c}
+}
+
+// Start begins a measurement. The returned function records the time
+// elapsed since Start and returns it; it should be called once.
+func (r *LatencyRecorder) Start() func() time.Duration {
+	start := r.clock.Now()
+	return func() time.Duration {
+		d := r.clock.Since(start)
+		r.Record(d)
+		return d
+	}
+}
+
+// Time calls fn, and records and returns how long it took.
+func (r *LatencyRecorder) Time(fn func()) time.Duration {
+	stop := r.Start()
+	fn()
+	return stop()
+}
+
+// Record adds a duration measured elsewhere.
+func (r *LatencyRecorder) Record(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, d)
+	r.sorted = false
+}
+
+// Count returns the number of durations recorded.
+func (r *LatencyRecorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.samples)
+}
+
+// Mean returns the mean of the durations recorded, or 0 if there are none.
+func (r *LatencyRecorder) Mean() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.samples) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range r.samples {
+		total += d
+	}
+	return total / time.Duration(len(r.samples))
+}
+
+// Quantile returns the q-quantile of the durations recorded, by the
+// nearest-rank method: the smallest recorded duration that at least q of
+// them are no greater than. Quantile(0.5) is the median and Quantile(1) the
+// maximum. It returns 0 if there are none, and panics if q is not between 0
+// and 1.
+func (r *LatencyRecorder) Quantile(q float64) time.Duration {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		panic(fmt.Sprintf("quantile %v out of range for Quantile", q))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.samples) == 0 {
+		return 0
+	}
+	if !r.sorted {
+		sort.Slice(r.samples, func(i, j int) bool { return r.samples[i] < r.samples[j] })
+		r.sorted = true
+	}
+	i := int(math.Ceil(q*float64(len(r.samples)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return r.samples[i]
+}
+
+// Reset discards the durations recorded.
+func (r *LatencyRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = nil
+	r.sorted = false
+}
@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	r := NewLatencyRecorder(mock)
+	assert.Equal(t, time.Duration(0), r.Quantile(0.5))
+	assert.Equal(t, time.Duration(0), r.Mean())
+
+	for i := 1; i <= 10; i++ {
+		stop := r.Start()
+		mock.Add(time.Duration(i) * time.Millisecond)
+		assert.Equal(t, time.Duration(i)*time.Millisecond, stop())
+	}
+	d := r.Time(func() { mock.Add(100 * time.Millisecond) })
+	assert.Equal(t, 100*time.Millisecond, d)
+
+	assert.Equal(t, 11, r.Count())
+	assert.Equal(t, 1*time.Millisecond, r.Quantile(0))
+	assert.Equal(t, 6*time.Millisecond, r.Quantile(0.5))
+	assert.Equal(t, 10*time.Millisecond, r.Quantile(0.9))
+	assert.Equal(t, 100*time.Millisecond, r.Quantile(0.99))
+	assert.Equal(t, 100*time.Millisecond, r.Quantile(1))
+	assert.Equal(t, 155*time.Millisecond/11, r.Mean())
+
+	r.Record(time.Microsecond)
+	assert.Equal(t, time.Microsecond, r.Quantile(0))
+
+	assert.PanicsWithValue(t, "quantile 1.5 out of range for Quantile", func() { r.Quantile(1.5) })
+
+	r.Reset()
+	assert.Equal(t, 0, r.Count())
+}
@@ -0,0 +1,189 @@
+package clock
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Leak describes a timer or ticker that a LeakTrackingClock created and that
+// has not been stopped, or for timers, has not yet fired.
+type Leak struct {
+	Kind    TimerKind // how the timer was created
+	Created time.Time // when the timer was created
+	Stack   string    // call stack that created the timer
+}
+
+// LeakTrackingClock is a real-time clock that remembers where each of its
+// tickers and timers was created until it is stopped, or for timers, until it
+// fires. Tickers that are never stopped run forever, which the time package
+// silently tolerates; ReportLeaks finds them.
+//
+// Tracking captures a call stack for every timer, so it has a cost, and is
+// meant to be switched on while looking for leaks.
+type LeakTrackingClock struct {
+	clock
+
+	mu   sync.Mutex
+	live map[*tracked]struct{}
+}
+
+// tracked is an entry in a LeakTrackingClock's live set.
+type tracked struct {
+	kind    TimerKind
+	created time.Time
+	stack   []uintptr
+}
+
+// NewLeakTracking returns a real-time clock that tracks unstopped tickers and
+// timers.
+func NewLeakTracking() *LeakTrackingClock {
+	return &LeakTrackingClock{live: map[*tracked]struct{}{}}
+}
+
+// ReportLeaks returns every ticker that hasn't been stopped, and every timer
+// that hasn't been stopped or fired, oldest first.
+func (c *LeakTrackingClock) ReportLeaks() []Leak {
+	c.mu.Lock()
+	ret := make([]Leak, 0, len(c.live))
+	for t := range c.live {
+		ret = append(ret, Leak{Kind: t.kind, Created: t.created, Stack: formatStack(t.stack)})
+	}
+	c.mu.Unlock()
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Created.Before(ret[j].Created) })
+	return ret
+}
+
+// Var returns an expvar.Var that reports the leaks, for publishing with
+// expvar.Publish.
+func (c *LeakTrackingClock) Var() expvar.Var {
+	return expvar.Func(func() interface{} { return c.ReportLeaks() })
+}
+
+func (c *LeakTrackingClock) track(t *tracked) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.live[t] = struct{}{}
+}
+
+func (c *LeakTrackingClock) untrack(t *tracked) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.live, t)
+}
+
+func (c *LeakTrackingClock) After(d time.Duration) <-chan time.Time {
+	return c.newTimer(d, nil, callers()).C
+}
+
+func (c *LeakTrackingClock) AfterAt(t time.Time) <-chan time.Time {
+	return c.newTimer(time.Until(t), nil, callers()).C
+}
+
+func (c *LeakTrackingClock) AfterFunc(d time.Duration, f func()) MockableTimer {
+	return c.newTimer(d, f, callers())
+}
+
+func (c *LeakTrackingClock) AtFunc(t time.Time, f func()) MockableTimer {
+	return c.newTimer(time.Until(t), f, callers())
+}
+
+func (c *LeakTrackingClock) NewTimer(d time.Duration) *Timer {
+	return c.newTimer(d, nil, callers())
+}
+
+func (c *LeakTrackingClock) newTimer(d time.Duration, f func(), stack []uintptr) *Timer {
+	t := &trackedTimer{c: c, entry: &tracked{kind: KindTimer, created: time.Now(), stack: stack}, fn: f}
+	if f != nil {
+		t.entry.kind = KindAfterFunc
+	} else {
+		t.ch = make(chan time.Time, 1)
+	}
+	c.track(t.entry)
+	// The timer is built on time.AfterFunc, rather than time.NewTimer, so
+	// the clock can tell when it fires.
+	t.timer = time.AfterFunc(d, t.fire)
+	if f != nil {
+		return WrapTimer(nil, t)
+	}
+	return WrapTimer(t.ch, t)
+}
+
+func (c *LeakTrackingClock) Tick(d time.Duration) <-chan time.Time {
+	return c.newTicker(c.clock.NewTicker(d), callers()).C
+}
+
+func (c *LeakTrackingClock) NewTicker(d time.Duration) *Ticker {
+	return c.newTicker(c.clock.NewTicker(d), callers())
+}
+
+func (c *LeakTrackingClock) NewAlignedTicker(d time.Duration) *Ticker {
+	return c.newTicker(c.clock.NewAlignedTicker(d), callers())
+}
+
+func (c *LeakTrackingClock) newTicker(ticker *Ticker, stack []uintptr) *Ticker {
+	t := &trackedTicker{c: c, entry: &tracked{kind: KindTicker, created: time.Now(), stack: stack}, ticker: ticker}
+	c.track(t.entry)
+	return WrapTicker(ticker.C, t)
+}
+
+type trackedTimer struct {
+	c     *LeakTrackingClock
+	entry *tracked
+	timer *time.Timer
+	ch    chan time.Time
+	fn    func()
+}
+
+func (t *trackedTimer) fire() {
+	t.c.untrack(t.entry)
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.ch <- time.Now():
+	default:
+	}
+}
+
+func (t *trackedTimer) Stop() bool {
+	t.c.untrack(t.entry)
+	ret := t.timer.Stop()
+	t.drain()
+	return ret
+}
+
+func (t *trackedTimer) Reset(d time.Duration) bool {
+	t.c.track(t.entry)
+	ret := t.timer.Stop()
+	t.drain()
+	t.timer.Reset(d)
+	return ret
+}
+
+// drain discards a fire that has not been received, as a mock's timer does
+// when it is stopped or reset.
+func (t *trackedTimer) drain() {
+	select {
+	case <-t.ch:
+	default:
+	}
+}
+
+type trackedTicker struct {
+	c      *LeakTrackingClock
+	entry  *tracked
+	ticker *Ticker
+}
+
+func (t *trackedTicker) Stop() {
+	t.c.untrack(t.entry)
+	t.ticker.Stop()
+}
+
+func (t *trackedTicker) Reset(d time.Duration) {
+	t.c.track(t.entry)
+	t.ticker.Reset(d)
+}
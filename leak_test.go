@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that tickers and timers are reported until they are stopped or
+// fire, with the stack that created them.
+func TestLeakTracking_ReportLeaks(t *testing.T) {
+	c := NewLeakTracking()
+	ticker := c.NewTicker(time.Hour)
+	timer := c.NewTimer(time.Hour)
+	c.AfterFunc(time.Hour, func() {})
+	fired := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-c.After(time.Millisecond)
+	<-fired
+
+	leaks := c.ReportLeaks()
+	if assert.Len(t, leaks, 3) {
+		assert.Equal(t, KindTicker, leaks[0].Kind)
+		assert.Contains(t, leaks[0].Stack, "TestLeakTracking_ReportLeaks")
+		assert.Equal(t, KindTimer, leaks[1].Kind)
+		assert.Equal(t, KindAfterFunc, leaks[2].Kind)
+	}
+
+	ticker.Stop()
+	assert.True(t, timer.Stop())
+	assert.Len(t, c.ReportLeaks(), 1)
+
+	timer.Reset(time.Hour)
+	assert.Len(t, c.ReportLeaks(), 2)
+}
+
+// Ensure that the leaks can be published with expvar.
+func TestLeakTracking_Var(t *testing.T) {
+	c := NewLeakTracking()
+	ticker := c.NewAlignedTicker(time.Hour)
+	defer ticker.Stop()
+
+	var leaks []Leak
+	assert.NoError(t, json.Unmarshal([]byte(c.Var().String()), &leaks))
+	if assert.Len(t, leaks, 1) {
+		assert.Equal(t, KindTicker, leaks[0].Kind)
+	}
+}
+
+// Ensure that a fire that wasn't received is discarded by Stop and Reset.
+func TestLeakTracking_Drain(t *testing.T) {
+	c := NewLeakTracking()
+	timer := c.NewTimer(time.Millisecond)
+	waitFired(timer)
+	assert.False(t, timer.Reset(time.Millisecond))
+	assert.Empty(t, timer.C)
+
+	waitFired(timer)
+	assert.False(t, timer.Stop())
+	assert.Empty(t, timer.C)
+}
+
+// waitFired waits until timer has a fire waiting to be received.
+func waitFired(timer *Timer) {
+	for len(timer.C) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,250 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLeaseLost is returned by the heartbeat from Lease.Renewer when the
+// lease has expired or been released.
+var ErrLeaseLost = errors.New("lease: lost")
+
+// Lease is a claim that lasts for a TTL unless it is renewed, as held by
+// the leader in leader election or the owner of a distributed lock. It
+// expires exactly TTL after it was last acquired or renewed, as measured on
+// a MockableClock: a renewal at or after that instant fails, even if the
+// expiry callback has yet to run, so races between renewal and expiry are
+// decided by virtual time alone. On a mock, the Lease starts one timer,
+// when it is first acquired, and resets it after that.
+type Lease struct {
+	clock MockableClock
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	held      bool
+	expires   time.Time
+	timer     MockableTimer
+	onAcquire func(expires time.Time)
+	onRenew   func(expires time.Time)
+	onExpire  func()
+}
+
+// NewLease returns an unheld Lease timed by c that lasts ttl from each
+// acquisition or renewal. It panics if ttl is not positive.
+func NewLease(c MockableClock, ttl time.Duration) *Lease {
+	if ttl <= 0 {
+		panic("non-positive ttl for NewLease")
+	}
+	return &Lease{clock: c, ttl: ttl}
+}
+
+// OnAcquire registers a function that is called with the expiry time each
+// time the lease is acquired.
+func (l *Lease) OnAcquire(fn func(expires time.Time)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onAcquire = fn
+}
+
+// OnRenew registers a function that is called with the new expiry time each
+// time the lease is renewed.
+func (l *Lease) OnRenew(fn func(expires time.Time)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onRenew = fn
+}
+
+// OnExpire registers a function that is called each time the lease expires
+// without being renewed. It is not called when the lease is released.
+func (l *Lease) OnExpire(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onExpire = fn
+}
+
+// Acquire takes the lease if it is not held, and reports whether it did.
+func (l *Lease) Acquire() bool {
+	now := l.clock.Now()
+	l.mu.Lock()
+	if l.held && now.Before(l.expires) {
+		l.mu.Unlock()
+		return false
+	}
+	onExpire := l.expireLocked()
+	l.held = true
+	l.extendLocked(now)
+	onAcquire, expires := l.onAcquire, l.expires
+	l.mu.Unlock()
+	if onExpire != nil {
+		onExpire()
+	}
+	if onAcquire != nil {
+		onAcquire(expires)
+	}
+	return true
+}
+
+// Renew extends the lease to TTL from now, and reports whether it did. It
+// fails if the lease is not held, including if it has just expired.
+func (l *Lease) Renew() bool {
+	now := l.clock.Now()
+	l.mu.Lock()
+	if !l.held || !now.Before(l.expires) {
+		onExpire := l.expireLocked()
+		l.mu.Unlock()
+		if onExpire != nil {
+			onExpire()
+		}
+		return false
+	}
+	l.extendLocked(now)
+	onRenew, expires := l.onRenew, l.expires
+	l.mu.Unlock()
+	if onRenew != nil {
+		onRenew(expires)
+	}
+	return true
+}
+
+// Release gives up the lease, if it is held, without calling OnExpire.
+func (l *Lease) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held = false
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
+
+// Held reports whether the lease is held and unexpired.
+func (l *Lease) Held() bool {
+	return l.Remaining() > 0
+}
+
+// Expires returns the time the lease expires, or the zero time if it is
+// not held.
+func (l *Lease) Expires() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.held {
+		return time.Time{}
+	}
+	return l.expires
+}
+
+// Remaining returns how long the lease has left before it expires, or 0 if
+// it is not held.
+func (l *Lease) Remaining() time.Duration {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.held || !now.Before(l.expires) {
+		return 0
+	}
+	return l.expires.Sub(now)
+}
+
+// Renewer returns a heartbeat, for Heartbeater.Run, that renews l, and
+// fails with ErrLeaseLost if it cannot.
+func (l *Lease) Renewer() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if !l.Renew() {
+			return ErrLeaseLost
+		}
+		return nil
+	}
+}
+
+// extendLocked moves the expiry to TTL after now and arms the timer for it.
+// It must be called with mu held.
+func (l *Lease) extendLocked(now time.Time) {
+	l.expires = now.Add(l.ttl)
+	if l.timer == nil {
+		l.timer = l.clock.AfterFunc(l.ttl, l.expire)
+	} else {
+		l.timer.Reset(l.ttl)
+	}
+}
+
+// expire runs when the timer fires, and expires the lease unless it has
+// been renewed or released since the timer was armed.
+func (l *Lease) expire() {
+	now := l.clock.Now()
+	l.mu.Lock()
+	var onExpire func()
+	if !now.Before(l.expires) {
+		onExpire = l.expireLocked()
+	}
+	l.mu.Unlock()
+	if onExpire != nil {
+		onExpire()
+	}
+}
+
+// expireLocked marks a held lease whose time is up as expired, and returns
+// the OnExpire callback to call once mu is released, or nil if there is
+// nothing to report. It must be called with mu held.
+func (l *Lease) expireLocked() func() {
+	if !l.held {
+		return nil
+	}
+	l.held = false
+	l.timer.Stop()
+	return l.onExpire
+}
+
+// Heartbeater sends a heartbeat every Interval on a clock, as a lease
+// holder renews its lease or a worker reports that it is alive. With a mock
+// clock, missed and late heartbeats can be tested by advancing it past
+// their deadlines; the Heartbeater starts one ticker, when Run is called.
+type Heartbeater struct {
+	Clock    MockableClock
+	Interval time.Duration
+
+	// OnMiss, if set, is called with the error from each heartbeat that
+	// fails, and the number of heartbeats that have failed in a row.
+	OnMiss func(err error, misses int)
+
+	// MaxMisses, if positive, is the number of heartbeats that may fail in
+	// a row before Run gives up and returns the last error.
+	MaxMisses int
+}
+
+// NewHeartbeater returns a Heartbeater that beats every interval on c, and
+// never gives up. It panics if interval is not positive.
+func NewHeartbeater(c MockableClock, interval time.Duration) *Heartbeater {
+	if interval <= 0 {
+		panic("non-positive interval for NewHeartbeater")
+	}
+	return &Heartbeater{Clock: c, Interval: interval}
+}
+
+// Run calls beat every Interval, the first time one Interval after Run is
+// called, until ctx is done or MaxMisses heartbeats in a row have failed.
+// It returns ctx's error or the last heartbeat's.
+func (h *Heartbeater) Run(ctx context.Context, beat func(ctx context.Context) error) error {
+	ticker := h.Clock.NewTicker(h.Interval)
+	defer ticker.Stop()
+
+	misses := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := beat(ctx); err != nil {
+			misses++
+			if h.OnMiss != nil {
+				h.OnMiss(err, misses)
+			}
+			if h.MaxMisses > 0 && misses >= h.MaxMisses {
+				return err
+			}
+			continue
+		}
+		misses = 0
+	}
+}
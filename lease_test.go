@@ -0,0 +1,95 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLease(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	l := NewLease(mock, 10*time.Second)
+	var log []string
+	l.OnAcquire(func(exp time.Time) { log = append(log, "acquire "+exp.Format("05")) })
+	l.OnRenew(func(exp time.Time) { log = append(log, "renew "+exp.Format("05")) })
+	l.OnExpire(func() { log = append(log, "expire") })
+
+	assert.False(t, l.Renew())
+	assert.True(t, l.Acquire())
+	assert.False(t, l.Acquire())
+	assert.True(t, l.Held())
+
+	mock.Add(9 * time.Second)
+	assert.Equal(t, time.Second, l.Remaining())
+	assert.True(t, l.Renew())
+	mock.Add(9 * time.Second)
+	assert.True(t, l.Held())
+	mock.Add(time.Second)
+	assert.False(t, l.Held())
+	assert.False(t, l.Renew())
+	assert.Equal(t, time.Time{}, l.Expires())
+
+	assert.True(t, l.Acquire())
+	l.Release()
+	mock.Add(time.Minute)
+	assert.Equal(t, []string{"acquire 10", "renew 19", "expire", "acquire 29"}, log)
+
+	assert.PanicsWithValue(t, "non-positive ttl for NewLease", func() { NewLease(mock, 0) })
+}
+
+// Ensure that a renewal at the instant of expiry fails, even if it happens
+// before the expiry callback runs.
+func TestLease_RenewAtExpiry(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	l := NewLease(mock, 10*time.Second)
+	expired := 0
+	l.OnExpire(func() { expired++ })
+	var renewed bool
+	mock.AfterFunc(10*time.Second, func() {
+		renewed = l.Renew()
+		assert.Equal(t, 1, expired)
+	})
+	l.Acquire()
+
+	mock.Add(10 * time.Second)
+	assert.False(t, renewed)
+	assert.Equal(t, 1, expired)
+}
+
+func TestHeartbeater(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.ExpectStarts(2)
+	l := NewLease(mock, 3*time.Second)
+	l.Acquire()
+	h := NewHeartbeater(mock, 2*time.Second)
+	h.MaxMisses = 2
+	var misses []int
+	h.OnMiss = func(err error, n int) { misses = append(misses, n) }
+
+	fail := make(chan bool, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Run(context.Background(), func(ctx context.Context) error {
+			if <-fail {
+				return errors.New("unreachable")
+			}
+			return l.Renewer()(ctx)
+		})
+	}()
+
+	fail <- false
+	mock.Add(2*time.Second, WaitBefore)
+	assert.Eventually(t, func() bool { return l.Expires().Equal(mock.Now().Add(3 * time.Second)) }, time.Second, time.Millisecond)
+	fail <- true
+	mock.Add(2 * time.Second)
+	assert.Eventually(t, func() bool { return l.Remaining() == time.Second }, time.Second, time.Millisecond)
+	assert.True(t, l.Held())
+	fail <- false
+	mock.Add(2 * time.Second)
+	assert.Equal(t, ErrLeaseLost, <-done)
+	assert.Equal(t, []int{1, 2}, misses)
+	assert.False(t, l.Held())
+}
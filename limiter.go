@@ -0,0 +1,347 @@
+// The token bucket in this file is adapted from golang.org/x/time/rate, to
+// read the time from a MockableClock. That package is distributed under the
+// following license:
+//
+// Copyright (c) 2009 The Go Authors. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//    * Redistributions of source code must retain the above copyright
+// notice, this list of conditions and the following disclaimer.
+//    * Redistributions in binary form must reproduce the above
+// copyright notice, this list of conditions and the following disclaimer
+// in the documentation and/or other materials provided with the
+// distribution.
+//    * Neither the name of Google Inc. nor the names of its
+// contributors may be used to endorse or promote products derived from
+// this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+// LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+// DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+// THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package clock
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit defines the maximum frequency of some events, in events per second.
+// A zero Limit allows no events beyond the initial burst.
+type Limit float64
+
+// Inf is the infinite rate limit; it allows all events, even if burst is zero.
+const Inf = Limit(math.MaxFloat64)
+
+// infDuration is the duration returned by Delay when a Reservation is not OK.
+const infDuration = time.Duration(math.MaxInt64)
+
+// Every converts a minimum time interval between events to a Limit.
+func Every(interval time.Duration) Limit {
+	if interval <= 0 {
+		return Inf
+	}
+	return 1 / Limit(interval.Seconds())
+}
+
+func (limit Limit) durationFromTokens(tokens float64) time.Duration {
+	if limit <= 0 {
+		return infDuration
+	}
+	return time.Duration(tokens / float64(limit) * float64(time.Second))
+}
+
+func (limit Limit) tokensFromDuration(d time.Duration) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return d.Seconds() * float64(limit)
+}
+
+// Limiter is a token bucket rate limiter with the same API and behavior as
+// golang.org/x/time/rate.Limiter, except that it reads the time from a
+// MockableClock and waits using the clock's timers. The bucket holds up to
+// burst tokens and is refilled at limit tokens per second.
+type Limiter struct {
+	clock MockableClock
+
+	mu     sync.Mutex
+	limit  Limit
+	burst  int
+	tokens float64
+	// last is the last time the tokens field was updated
+	last time.Time
+	// lastEvent is the latest time of a rate-limited event (past or future)
+	lastEvent time.Time
+}
+
+// NewLimiter returns a new Limiter timed by c that allows events up to rate r
+// and permits bursts of at most b tokens. The bucket starts full.
+func NewLimiter(c MockableClock, r Limit, b int) *Limiter {
+	return &Limiter{clock: c, limit: r, burst: b, tokens: float64(b), last: c.Now()}
+}
+
+// Limit returns the maximum overall event rate.
+func (lim *Limiter) Limit() Limit {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return lim.limit
+}
+
+// Burst returns the maximum burst size.
+func (lim *Limiter) Burst() int {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return lim.burst
+}
+
+// Tokens returns the number of tokens available now.
+func (lim *Limiter) Tokens() float64 {
+	return lim.TokensAt(lim.clock.Now())
+}
+
+// TokensAt returns the number of tokens available at time t.
+func (lim *Limiter) TokensAt(t time.Time) float64 {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return lim.advance(t)
+}
+
+// SetLimit sets a new Limit for the limiter, as of now.
+func (lim *Limiter) SetLimit(newLimit Limit) {
+	lim.SetLimitAt(lim.clock.Now(), newLimit)
+}
+
+// SetLimitAt sets a new Limit for the limiter, as of time t.
+func (lim *Limiter) SetLimitAt(t time.Time, newLimit Limit) {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	lim.tokens = lim.advance(t)
+	lim.last = t
+	lim.limit = newLimit
+}
+
+// SetBurst sets a new burst size for the limiter, as of now.
+func (lim *Limiter) SetBurst(newBurst int) {
+	lim.SetBurstAt(lim.clock.Now(), newBurst)
+}
+
+// SetBurstAt sets a new burst size for the limiter, as of time t.
+func (lim *Limiter) SetBurstAt(t time.Time, newBurst int) {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	lim.tokens = lim.advance(t)
+	lim.last = t
+	lim.burst = newBurst
+}
+
+// Allow reports whether an event may happen now.
+func (lim *Limiter) Allow() bool {
+	return lim.AllowN(lim.clock.Now(), 1)
+}
+
+// AllowN reports whether n events may happen at time t. Use this method if
+// you intend to drop or skip events that exceed the rate limit.
+func (lim *Limiter) AllowN(t time.Time, n int) bool {
+	return lim.reserveN(t, n, 0).ok
+}
+
+// Reserve is shorthand for ReserveN(clock.Now(), 1).
+func (lim *Limiter) Reserve() *Reservation {
+	return lim.ReserveN(lim.clock.Now(), 1)
+}
+
+// ReserveN returns a Reservation that indicates how long the caller must
+// wait before n events happen. The Limiter takes this Reservation into
+// account when allowing future events. The returned Reservation's OK method
+// returns false if n exceeds the Limiter's burst size.
+func (lim *Limiter) ReserveN(t time.Time, n int) *Reservation {
+	r := lim.reserveN(t, n, infDuration)
+	return &r
+}
+
+// Wait is shorthand for WaitN(ctx, 1).
+func (lim *Limiter) Wait(ctx context.Context) error {
+	return lim.WaitN(ctx, 1)
+}
+
+// WaitN blocks until the limiter permits n events to happen, waiting on the
+// limiter's clock. It returns an error if n exceeds the Limiter's burst size,
+// the context is canceled, or the expected wait time exceeds the context's
+// deadline as measured on the clock.
+func (lim *Limiter) WaitN(ctx context.Context, n int) error {
+	lim.mu.Lock()
+	burst, limit := lim.burst, lim.limit
+	lim.mu.Unlock()
+
+	if n > burst && limit != Inf {
+		return fmt.Errorf("rate: Wait(n=%d) exceeds limiter's burst %d", n, burst)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	now := lim.clock.Now()
+	waitLimit := infDuration
+	if deadline, ok := ctx.Deadline(); ok {
+		waitLimit = deadline.Sub(now)
+	}
+	r := lim.reserveN(now, n, waitLimit)
+	if !r.ok {
+		return fmt.Errorf("rate: Wait(n=%d) would exceed context deadline", n)
+	}
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	t := lim.clock.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// reserveN is a helper for AllowN, ReserveN, and WaitN. maxWait limits how
+// long the caller is willing to wait for the tokens.
+func (lim *Limiter) reserveN(t time.Time, n int, maxWait time.Duration) Reservation {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	if lim.limit == Inf {
+		return Reservation{ok: true, lim: lim, tokens: n, timeToAct: t}
+	}
+
+	tokens := lim.advance(t)
+	tokens -= float64(n)
+
+	var waitDuration time.Duration
+	if tokens < 0 {
+		waitDuration = lim.limit.durationFromTokens(-tokens)
+	}
+
+	r := Reservation{
+		ok:    n <= lim.burst && waitDuration <= maxWait && waitDuration != infDuration,
+		lim:   lim,
+		limit: lim.limit,
+	}
+	if r.ok {
+		r.tokens = n
+		r.timeToAct = t.Add(waitDuration)
+
+		lim.last = t
+		lim.tokens = tokens
+		lim.lastEvent = r.timeToAct
+	}
+	return r
+}
+
+// advance returns the number of tokens the bucket holds at time t. It must
+// be called with mu held.
+func (lim *Limiter) advance(t time.Time) float64 {
+	last := lim.last
+	if t.Before(last) {
+		last = t
+	}
+	tokens := lim.tokens + lim.limit.tokensFromDuration(t.Sub(last))
+	if burst := float64(lim.burst); tokens > burst {
+		tokens = burst
+	}
+	return tokens
+}
+
+// A Reservation holds information about events that are permitted by a
+// Limiter to happen after a delay. A Reservation may be canceled, which may
+// enable the Limiter to permit additional events.
+type Reservation struct {
+	ok        bool
+	lim       *Limiter
+	tokens    int
+	timeToAct time.Time
+	// limit at reservation time
+	limit Limit
+}
+
+// OK returns whether the limiter can provide the requested number of tokens
+// within the maximum wait time.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay is shorthand for DelayFrom(clock.Now()).
+func (r *Reservation) Delay() time.Duration {
+	return r.DelayFrom(r.lim.clock.Now())
+}
+
+// DelayFrom returns the duration for which the reservation holder must wait
+// before taking the reserved action, measured from t. A zero duration means
+// act immediately. A Reservation that is not OK returns an infinite delay.
+func (r *Reservation) DelayFrom(t time.Time) time.Duration {
+	if !r.ok {
+		return infDuration
+	}
+	delay := r.timeToAct.Sub(t)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// Cancel is shorthand for CancelAt(clock.Now()).
+func (r *Reservation) Cancel() {
+	r.CancelAt(r.lim.clock.Now())
+}
+
+// CancelAt indicates that the reservation holder will not perform the
+// reserved action and reverses the effects of this Reservation on the rate
+// limit as much as possible, considering that other reservations may have
+// already been made.
+func (r *Reservation) CancelAt(t time.Time) {
+	if !r.ok {
+		return
+	}
+
+	r.lim.mu.Lock()
+	defer r.lim.mu.Unlock()
+
+	if r.lim.limit == Inf || r.tokens == 0 || r.timeToAct.Before(t) {
+		return
+	}
+
+	// Tokens reserved after this one can't be restored.
+	restoreTokens := float64(r.tokens) - r.limit.tokensFromDuration(r.lim.lastEvent.Sub(r.timeToAct))
+	if restoreTokens <= 0 {
+		return
+	}
+	tokens := r.lim.advance(t) + restoreTokens
+	if burst := float64(r.lim.burst); tokens > burst {
+		tokens = burst
+	}
+	r.lim.last = t
+	r.lim.tokens = tokens
+	if r.timeToAct.Equal(r.lim.lastEvent) {
+		prevEvent := r.timeToAct.Add(r.limit.durationFromTokens(float64(-r.tokens)))
+		if !prevEvent.Before(t) {
+			r.lim.lastEvent = prevEvent
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the limiter refills at the configured rate on the mock clock.
+func TestLimiter_Allow(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	lim := NewLimiter(clock, Every(time.Second), 3)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, lim.Allow(), "burst event %d", i)
+	}
+	assert.False(t, lim.Allow())
+
+	clock.Add(999 * time.Millisecond)
+	assert.False(t, lim.Allow())
+	clock.Add(1 * time.Millisecond)
+	assert.True(t, lim.Allow())
+
+	// The bucket never holds more than the burst.
+	clock.Add(1 * time.Hour)
+	assert.Equal(t, 3.0, lim.Tokens())
+	assert.True(t, lim.AllowN(clock.Now(), 3))
+	assert.False(t, lim.AllowN(clock.Now(), 4))
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	lim := NewLimiter(clock, 2, 1)
+
+	r := lim.Reserve()
+	assert.True(t, r.OK())
+	assert.Equal(t, time.Duration(0), r.Delay())
+
+	r = lim.Reserve()
+	assert.True(t, r.OK())
+	assert.Equal(t, 500*time.Millisecond, r.Delay())
+
+	// Cancelling the reservation returns its token.
+	r.Cancel()
+	clock.Add(500 * time.Millisecond)
+	assert.True(t, lim.Allow())
+
+	// Reservations larger than the burst can never be satisfied.
+	r = lim.ReserveN(clock.Now(), 2)
+	assert.False(t, r.OK())
+}
+
+func TestLimiter_Inf(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	lim := NewLimiter(clock, Inf, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, lim.Allow())
+	}
+
+	lim = NewLimiter(clock, 0, 1)
+	assert.True(t, lim.Allow())
+	assert.False(t, lim.Allow())
+	clock.Add(1 * time.Hour)
+	assert.False(t, lim.Allow())
+}
+
+// Ensure that Wait blocks until the mock has been advanced far enough.
+func TestLimiter_Wait(t *testing.T) {
+	clock := NewMock(t, 0)
+	lim := NewLimiter(clock, Every(10*time.Second), 1)
+	assert.NoError(t, lim.Wait(context.Background()))
+
+	clock.ExpectStarts(1)
+	done := make(chan error, 1)
+	go func() {
+		done <- lim.Wait(context.Background())
+	}()
+
+	clock.Add(9 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("too early")
+	default:
+	}
+
+	clock.Add(1 * time.Second)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("too late")
+	}
+}
+
+func TestLimiter_Wait_Errors(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	lim := NewLimiter(clock, Every(10*time.Second), 1)
+
+	assert.Error(t, lim.WaitN(context.Background(), 2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, lim.Wait(ctx))
+
+	// The deadline is compared with the clock, not real time.
+	assert.NoError(t, lim.Wait(context.Background()))
+	ctx, cancel = context.WithDeadline(context.Background(), clock.Now().Add(5*time.Second))
+	defer cancel()
+	assert.Error(t, lim.Wait(ctx))
+}
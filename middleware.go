@@ -0,0 +1,169 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ClockMiddleware is a set of hooks on a clock's activity, applied with
+// Chain. Any hook may be nil. Hooks that return a value replace the value
+// passed in, so a middleware can observe calls, as instrumentation and
+// logging do, or alter them, as chaos injection does.
+type ClockMiddleware struct {
+	// OnNow is called with each time read from the clock, by Now and
+	// Since, and returns the time to report instead.
+	OnNow func(t time.Time) time.Time
+	// OnSleep is called with the duration of each sleep and returns the
+	// duration to sleep instead.
+	OnSleep func(d time.Duration) time.Duration
+	// OnTimerCreate is called with the duration of each timer or ticker
+	// when it is created or reset, and returns the duration to use instead.
+	OnTimerCreate func(kind TimerKind, d time.Duration) time.Duration
+	// OnTimerFire is called each time a timer fires or a ticker ticks,
+	// before the value is delivered or the AfterFunc function is called.
+	OnTimerFire func(kind TimerKind, t time.Time)
+}
+
+// Chain returns a clock that passes calls to c through each middleware in
+// turn. As with HTTP middleware, the first is outermost: it sees calls
+// first, and sees times and timer fires from the clock last.
+//
+// When a middleware has an OnTimerFire hook, channels of timers and tickers
+// are fed by a goroutine that calls it before passing the value on, so a
+// value may arrive slightly after a mock has been advanced rather than
+// immediately.
+func Chain(c MockableClock, mw ...ClockMiddleware) MockableClock {
+	for i := len(mw) - 1; i >= 0; i-- {
+		c = &middlewareClock{clock: c, mw: mw[i]}
+	}
+	return c
+}
+
+// middlewareClock applies one ClockMiddleware to the clock it wraps.
+type middlewareClock struct {
+	clock MockableClock
+	mw    ClockMiddleware
+}
+
+func (c *middlewareClock) Now() time.Time {
+	t := c.clock.Now()
+	if c.mw.OnNow != nil {
+		t = c.mw.OnNow(t)
+	}
+	return t
+}
+
+func (c *middlewareClock) Since(t time.Time) time.Duration { return c.Now().Sub(t) }
+
+func (c *middlewareClock) sleep(d time.Duration) time.Duration {
+	if c.mw.OnSleep != nil {
+		d = c.mw.OnSleep(d)
+	}
+	return d
+}
+
+func (c *middlewareClock) Sleep(d time.Duration) { c.clock.Sleep(c.sleep(d)) }
+
+func (c *middlewareClock) SleepUntil(t time.Time) { c.Sleep(t.Sub(c.Now())) }
+
+func (c *middlewareClock) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.clock.SleepContext(ctx, c.sleep(d))
+}
+
+func (c *middlewareClock) create(kind TimerKind, d time.Duration) time.Duration {
+	if c.mw.OnTimerCreate != nil {
+		d = c.mw.OnTimerCreate(kind, d)
+	}
+	return d
+}
+
+func (c *middlewareClock) After(d time.Duration) <-chan time.Time { return c.NewTimer(d).C }
+
+func (c *middlewareClock) AfterAt(t time.Time) <-chan time.Time {
+	return c.NewTimer(t.Sub(c.Now())).C
+}
+
+func (c *middlewareClock) AfterFunc(d time.Duration, f func()) MockableTimer {
+	t := c.clock.AfterFunc(c.create(KindAfterFunc, d), func() {
+		if c.mw.OnTimerFire != nil {
+			c.mw.OnTimerFire(KindAfterFunc, c.clock.Now())
+		}
+		f()
+	})
+	return WrapTimer(nil, &middlewareTimer{c: c, kind: KindAfterFunc, t: t})
+}
+
+func (c *middlewareClock) AtFunc(t time.Time, f func()) MockableTimer {
+	return c.AfterFunc(t.Sub(c.Now()), f)
+}
+
+func (c *middlewareClock) NewTimer(d time.Duration) *Timer {
+	t := c.clock.NewTimer(c.create(KindTimer, d))
+	mt := &middlewareTimer{c: c, kind: KindTimer, t: t}
+	if c.mw.OnTimerFire == nil {
+		return WrapTimer(t.C, mt)
+	}
+	return ForwardTimer(t, mt, func(v time.Time) { c.mw.OnTimerFire(KindTimer, v) })
+}
+
+func (c *middlewareClock) Tick(d time.Duration) <-chan time.Time { return c.NewTicker(d).C }
+
+func (c *middlewareClock) NewTicker(d time.Duration) *Ticker {
+	return c.newTicker(c.clock.NewTicker(c.create(KindTicker, d)))
+}
+
+func (c *middlewareClock) NewAlignedTicker(d time.Duration) *Ticker {
+	return c.newTicker(c.clock.NewAlignedTicker(c.create(KindTicker, d)))
+}
+
+func (c *middlewareClock) newTicker(t *Ticker) *Ticker {
+	tk := &middlewareTicker{c: c, t: t, done: make(chan struct{})}
+	if c.mw.OnTimerFire == nil {
+		return WrapTicker(t.C, tk)
+	}
+	ch := make(chan time.Time, 1)
+	go func() {
+		for {
+			select {
+			case v := <-t.C:
+				c.mw.OnTimerFire(KindTicker, v)
+				select {
+				case ch <- v:
+				default:
+				}
+			case <-tk.done:
+				return
+			}
+		}
+	}()
+	return WrapTicker(ch, tk)
+}
+
+type middlewareTimer struct {
+	c    *middlewareClock
+	kind TimerKind
+	t    MockableTimer
+}
+
+func (t *middlewareTimer) Stop() bool { return t.t.Stop() }
+
+func (t *middlewareTimer) Reset(d time.Duration) bool { return t.t.Reset(t.c.create(t.kind, d)) }
+
+func (t *middlewareTimer) Reschedule(at time.Time) bool {
+	return t.Reset(at.Sub(t.c.clock.Now()))
+}
+
+type middlewareTicker struct {
+	c    *middlewareClock
+	t    *Ticker
+	once sync.Once
+	done chan struct{}
+}
+
+func (t *middlewareTicker) Stop() {
+	t.t.Stop()
+	t.once.Do(func() { close(t.done) })
+}
+
+func (t *middlewareTicker) Reset(d time.Duration) { t.t.Reset(t.c.create(KindTicker, d)) }
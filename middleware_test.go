@@ -0,0 +1,78 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the first middleware is outermost: it sees durations first
+// and times last.
+func TestChain_Order(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	var calls []string
+	c := Chain(mock,
+		ClockMiddleware{
+			OnNow: func(t time.Time) time.Time {
+				calls = append(calls, "outer now")
+				return t.Add(time.Hour)
+			},
+			OnSleep: func(d time.Duration) time.Duration {
+				calls = append(calls, "outer sleep")
+				return d * 2
+			},
+		},
+		ClockMiddleware{
+			OnNow: func(t time.Time) time.Time {
+				calls = append(calls, "inner now")
+				return t.Add(time.Minute)
+			},
+			OnSleep: func(d time.Duration) time.Duration {
+				calls = append(calls, "inner sleep")
+				return d + time.Second
+			},
+		},
+	)
+
+	assert.Equal(t, mock.Now().Add(time.Hour+time.Minute), c.Now())
+	mock.ExpectStarts(1)
+	go mock.Add(3*time.Second, WaitBefore)
+	c.Sleep(time.Second)
+	assert.Equal(t, []string{"inner now", "outer now", "outer sleep", "inner sleep"}, calls)
+}
+
+// Ensure that timer hooks see creation, reset and fires of each kind.
+func TestChain_Timers(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	created := make(chan TimerKind, 10)
+	fired := make(chan TimerKind, 10)
+	c := Chain(mock, ClockMiddleware{
+		OnTimerCreate: func(kind TimerKind, d time.Duration) time.Duration {
+			created <- kind
+			return d / 2
+		},
+		OnTimerFire: func(kind TimerKind, t time.Time) { fired <- kind },
+	})
+
+	timer := c.NewTimer(2 * time.Second)
+	ticker := c.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	called := make(chan struct{})
+	c.AfterFunc(2*time.Second, func() { close(called) })
+	assert.Equal(t, KindTimer, <-created)
+	assert.Equal(t, KindTicker, <-created)
+	assert.Equal(t, KindAfterFunc, <-created)
+
+	mock.Add(time.Second)
+	<-timer.C
+	<-ticker.C
+	<-called
+	kinds := map[TimerKind]bool{<-fired: true, <-fired: true, <-fired: true}
+	assert.Equal(t, map[TimerKind]bool{KindTimer: true, KindTicker: true, KindAfterFunc: true}, kinds)
+
+	timer.Reset(4 * time.Second)
+	assert.Equal(t, KindTimer, <-created)
+	mock.Add(2 * time.Second)
+	<-timer.C
+}
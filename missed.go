@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// MissedTick is a tick from a MissedTicker.
+type MissedTick struct {
+	Time time.Time
+	// Missed is the number of ticks dropped since the previous one that
+	// was received, because the consumer had not received that one yet or
+	// because the ticker itself fell behind.
+	Missed int
+}
+
+// MissedTicker is a ticker that, like time.Ticker, drops ticks that a slow
+// consumer isn't ready for, but reports how many it dropped with the next
+// tick it delivers. It works on any MockableClock. On a mock, its ticks are
+// delivered by an AfterFunc timer that it starts when it is created, so
+// they are counted as the mock advances, not when the consumer gets to them,
+// and the counts are the same on every run.
+type MissedTicker struct {
+	C <-chan MissedTick // The channel on which the ticks are delivered.
+
+	clock MockableClock
+	c     chan MissedTick
+	timer MockableTimer
+
+	mu      sync.Mutex
+	d       time.Duration
+	next    time.Time // when the next tick is due
+	missed  int       // ticks dropped since the last one delivered
+	stopped bool
+}
+
+// NewMissedTicker returns a MissedTicker that ticks every d on c. It panics
+// if d is not positive.
+func NewMissedTicker(c MockableClock, d time.Duration) *MissedTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewMissedTicker")
+	}
+	ch := make(chan MissedTick, 1)
+	t := &MissedTicker{C: ch, clock: c, c: ch, d: d}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next = c.Now().Add(d)
+	t.timer = c.AfterFunc(d, t.tick)
+	return t
+}
+
+// Stop turns off the ticker. Like time.Ticker.Stop, it does not close C.
+func (t *MissedTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	t.timer.Stop()
+}
+
+// Reset stops the ticker and restarts it with period d, the next tick due d
+// from now. Ticks already dropped are still reported with the next one.
+func (t *MissedTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for MissedTicker.Reset")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.d = d
+	t.stopped = false
+	t.next = t.clock.Now().Add(d)
+	t.timer.Reset(d)
+}
+
+// tick delivers the tick that is due, or counts it as dropped, along with
+// any that came due while the timer was late, and schedules the next.
+func (t *MissedTicker) tick() {
+	now := t.clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || now.Before(t.next) {
+		return
+	}
+	late := int(now.Sub(t.next) / t.d)
+	t.missed += late
+	t.next = t.next.Add(time.Duration(late+1) * t.d)
+	select {
+	case t.c <- MissedTick{Time: now, Missed: t.missed}:
+		t.missed = 0
+	default:
+		t.missed++
+	}
+	t.timer.Reset(t.next.Sub(now))
+}
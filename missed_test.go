@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissedTicker(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	mock.ExpectStarts(1)
+	ticker := NewMissedTicker(mock, time.Second)
+
+	mock.Add(time.Second)
+	assert.Equal(t, MissedTick{Time: start.Add(time.Second)}, <-ticker.C)
+
+	mock.Add(3 * time.Second)
+	assert.Equal(t, MissedTick{Time: start.Add(2 * time.Second)}, <-ticker.C)
+	mock.Add(time.Second)
+	assert.Equal(t, MissedTick{Time: start.Add(5 * time.Second), Missed: 2}, <-ticker.C)
+
+	ticker.Reset(2 * time.Second)
+	mock.Add(2 * time.Second)
+	assert.Equal(t, MissedTick{Time: start.Add(7 * time.Second)}, <-ticker.C)
+
+	ticker.Stop()
+	mock.Add(time.Minute)
+	select {
+	case tick := <-ticker.C:
+		t.Fatalf("unexpected tick %v", tick)
+	default:
+	}
+
+	assert.PanicsWithValue(t, "non-positive interval for NewMissedTicker", func() { NewMissedTicker(mock, 0) })
+}
+
+// Ensure that ticks that come due while the realtime ticker's timer is late
+// are counted as missed.
+func TestMissedTicker_Realtime(t *testing.T) {
+	ticker := NewMissedTicker(New(), 10*time.Millisecond)
+	defer ticker.Stop()
+	<-ticker.C
+	time.Sleep(55 * time.Millisecond)
+	<-ticker.C
+	tick := <-ticker.C
+	assert.GreaterOrEqual(t, tick.Missed, 3)
+}
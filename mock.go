@@ -16,20 +16,41 @@ type Mock struct {
 func NewMock(t *testing.T, expectedStarts int) *Mock {
 	ret := &Mock{
 		UnsynchronizedMock: UnsynchronizedMock{
-			now:             time.Unix(0, 0),
-			startCheckpoint: NewFailOnUnexpectedCheckpoint(TimerStart, t),
+			now:                 time.Unix(0, 0),
+			startCheckpoint:     NewFailOnUnexpectedCheckpoint(TimerStart, t),
+			afterFuncCheckpoint: NewOptionalCheckPoint(AfterFuncDone),
+			historyLimit:        DefaultHistoryLimit,
 		},
 	}
 	ExpectUpcomingStarts(expectedStarts).UpcomingEventsOption(&ret.UnsynchronizedMock)
 	return ret
 }
 
+// NewAsyncMock is like NewMock, but runs AfterFunc callbacks on their own
+// goroutine as time.AfterFunc does. Add and Set still wait for the callbacks
+// they start to return before returning themselves.
+func NewAsyncMock(t *testing.T, expectedStarts int) *Mock {
+	ret := NewMock(t, expectedStarts)
+	AsyncAfterFuncs.UpcomingEventsOption(&ret.UnsynchronizedMock)
+	return ret
+}
+
 func (m *Mock) Add(d time.Duration, opts ...Option) {
-	opts = append(opts, WaitBefore)
+	opts = append(opts, WaitBefore, WaitAfter)
 	m.UnsynchronizedMock.Add(d, opts...)
 }
 
 func (m *Mock) Set(t time.Time, opts ...Option) {
-	opts = append(opts, WaitBefore)
+	opts = append(opts, WaitBefore, WaitAfter)
 	m.UnsynchronizedMock.Set(t, opts...)
 }
+
+func (m *Mock) AddReport(d time.Duration, opts ...Option) *AdvanceReport {
+	opts = append(opts, WaitBefore, WaitAfter)
+	return m.UnsynchronizedMock.AddReport(d, opts...)
+}
+
+func (m *Mock) SetReport(t time.Time, opts ...Option) *AdvanceReport {
+	opts = append(opts, WaitBefore, WaitAfter)
+	return m.UnsynchronizedMock.SetReport(t, opts...)
+}
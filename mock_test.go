@@ -1,7 +1,11 @@
 package clock
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -102,6 +106,40 @@ func TestMock_Now(t *testing.T) {
 	}
 }
 
+// Ensure that Now stays consistent with the clock when it is read
+// concurrently with Add and Set, including Sets to other locations and far
+// away times.
+func TestMock_Now_Concurrent(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	ny := time.FixedZone("EST", -5*60*60)
+	far := time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			clock.Add(time.Second)
+			clock.Set(time.Unix(int64(i), 0).In(ny))
+			clock.Set(far)
+			clock.Set(time.Unix(int64(i), 0))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			if now := clock.Now(); now != time.Unix(99, 0) {
+				t.Fatalf("unexpected time: %v", now)
+			}
+			return
+		default:
+		}
+		now := clock.Now()
+		if now.Location() == ny && now.Unix() >= 100 || now.Year() > 1970 && !now.Equal(far) {
+			t.Fatalf("torn read: %v", now)
+		}
+	}
+}
+
 func TestMock_Since(t *testing.T) {
 	clock := NewUnsynchronizedMock()
 	clock.Set(time.Now())
@@ -143,6 +181,95 @@ func TestMock_Sleep(t *testing.T) {
 	}
 }
 
+// Ensure that the mock's SleepContext wakes on the mock clock or on cancellation.
+func TestMock_SleepContext(t *testing.T) {
+	clock := NewMock(t, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- clock.SleepContext(context.Background(), 10*time.Second)
+	}()
+	clock.Add(10 * time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	clock.ExpectStarts(1)
+	go func() {
+		done <- clock.SleepContext(ctx, time.Hour)
+	}()
+	clock.Wait()
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending := clock.PendingTimers(); len(pending) != 0 {
+		t.Fatalf("sleep timer was not stopped: %v", pending)
+	}
+}
+
+// Ensure that the mock's AfterAt and SleepUntil wake at an absolute time.
+func TestMock_AfterAt(t *testing.T) {
+	clock := NewMock(t, 2)
+	midnight := time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	ch := clock.AfterAt(midnight)
+	done := make(chan struct{})
+	go func() {
+		clock.SleepUntil(midnight)
+		close(done)
+	}()
+
+	clock.Set(midnight.Add(-time.Second))
+	select {
+	case <-ch:
+		t.Fatal("too early")
+	case <-done:
+		t.Fatal("too early")
+	default:
+	}
+
+	clock.Set(midnight)
+	if now := <-ch; !now.Equal(midnight) {
+		t.Fatalf("unexpected time: %v", now)
+	}
+	<-done
+
+	// A time that has already passed fires immediately.
+	clock.ExpectStarts(2)
+	<-clock.AfterAt(midnight)
+	clock.SleepUntil(time.Unix(0, 0))
+}
+
+// Ensure that the mock's AtFunc runs at an absolute time, however the clock
+// reaches it.
+func TestMock_AtFunc(t *testing.T) {
+	clock := NewMock(t, 1)
+	midnight := time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var n int32
+	clock.AtFunc(midnight, func() { atomic.AddInt32(&n, 1) })
+	clock.Set(midnight.Add(-time.Second))
+	if atomic.LoadInt32(&n) != 0 {
+		t.Fatal("too early")
+	}
+	clock.Set(midnight.Add(time.Hour))
+	if atomic.LoadInt32(&n) != 1 {
+		t.Fatal("expected function to run")
+	}
+
+	// A stopped timer doesn't run.
+	clock.ExpectStarts(1)
+	timer := clock.AtFunc(midnight.Add(2*time.Hour), func() { atomic.AddInt32(&n, 1) })
+	if !timer.Stop() {
+		t.Fatal("expected timer to be active")
+	}
+	clock.Add(2 * time.Hour)
+	if atomic.LoadInt32(&n) != 1 {
+		t.Fatal("stopped timer ran")
+	}
+}
+
 // Ensure that the mock's Tick channel sends at the correct time.
 func TestMock_Tick(t *testing.T) {
 	var n int32
@@ -477,3 +604,425 @@ func ExampleMock_NewTimer() {
 	// Output:
 	// Count is 1 after 10 seconds
 }
+
+// Ensure that AfterFunc callbacks can run on their own goroutine.
+func TestMock_AfterFunc_Async(t *testing.T) {
+	clock := NewUnsynchronizedMock(AsyncAfterFuncs)
+	release := make(chan struct{})
+	var ok int32
+
+	// A callback that blocks would hang Add if it were run inline.
+	clock.AfterFunc(10*time.Second, func() {
+		<-release
+		atomic.StoreInt32(&ok, 1)
+	})
+	clock.Add(10 * time.Second)
+	if atomic.LoadInt32(&ok) == 1 {
+		t.Fatal("callback did not block")
+	}
+
+	close(release)
+	clock.WaitAfterFuncs()
+	if atomic.LoadInt32(&ok) == 0 {
+		t.Fatal("callback did not run")
+	}
+}
+
+// Ensure that the async mock waits for AfterFunc callbacks before Add returns.
+func TestAsyncMock_AfterFunc(t *testing.T) {
+	var ok int32
+	clock := NewAsyncMock(t, 1)
+
+	clock.AfterFunc(10*time.Second, func() {
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&ok, 1)
+	})
+	clock.Add(10 * time.Second)
+	if atomic.LoadInt32(&ok) == 0 {
+		t.Fatal("Add returned before callback finished")
+	}
+}
+
+// Ensure that timers with identical deadlines fire in creation order.
+func TestMock_SameDeadline_FIFO(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	var order []int
+	for i := 0; i < 20; i++ {
+		i := i
+		clock.AfterFunc(10*time.Second, func() { order = append(order, i) })
+	}
+	// A ticker created later also yields to earlier timers on a shared deadline.
+	ticker := clock.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	clock.AfterFunc(10*time.Second, func() { order = append(order, 20) })
+
+	clock.Add(10 * time.Second)
+	for i := range order {
+		if order[i] != i {
+			t.Fatalf("unexpected order: %v", order)
+		}
+	}
+
+	var fired []uint64
+	for _, e := range clock.History() {
+		if e.Type == TimerFired || e.Type == TickerFired {
+			fired = append(fired, e.TimerID)
+		}
+	}
+	if fired[1] != 1 || fired[len(fired)-2] != ticker.ID() || fired[len(fired)-1] != 22 {
+		t.Fatalf("unexpected firing order: %v", fired)
+	}
+}
+
+// Ensure that resetting a timer whose fire was never received discards it,
+// like time.Timer since Go 1.23, rather than blocking the next advance.
+func TestMock_Timer_ResetUndrained(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	timer := clock.NewTimer(time.Second)
+	clock.Add(time.Second)
+	timer.Reset(time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("stale fire received after Reset")
+	default:
+	}
+
+	done := make(chan struct{})
+	go func() {
+		clock.Add(time.Second)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("advance blocked on undrained timer")
+	}
+	if got := <-timer.C; !got.Equal(time.Unix(2, 0)) {
+		t.Fatalf("fired at %v, want %v", got, time.Unix(2, 0))
+	}
+
+	clock.Add(time.Second)
+	timer.Stop()
+	select {
+	case <-timer.C:
+		t.Fatal("stale fire received after Stop")
+	default:
+	}
+}
+
+// Ensure that Stop and Reset report whether the timer was active, like time.Timer.
+func TestMock_Timer_StopResetResult(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+
+	timer := clock.NewTimer(10 * time.Second)
+	if !timer.Reset(5 * time.Second) {
+		t.Fatal("active timer reported inactive by Reset")
+	}
+	clock.Add(5 * time.Second)
+	<-timer.C
+	if timer.Stop() {
+		t.Fatal("expired timer reported stopped")
+	}
+	if timer.Reset(5 * time.Second) {
+		t.Fatal("expired timer reported active by Reset")
+	}
+	if !timer.Stop() {
+		t.Fatal("reset timer not stopped")
+	}
+	if timer.Stop() {
+		t.Fatal("stopped timer reported stopped again")
+	}
+
+	// From within its own callback, an AfterFunc timer has already expired.
+	var stopped, reset bool
+	var fn MockableTimer
+	fn = clock.AfterFunc(10*time.Second, func() {
+		stopped = fn.Stop()
+		reset = fn.Reset(10 * time.Second)
+	})
+	clock.Add(10 * time.Second)
+	if stopped || reset {
+		t.Fatalf("expired AfterFunc reported active: stop=%v reset=%v", stopped, reset)
+	}
+	if !fn.Stop() {
+		t.Fatal("AfterFunc reset from its callback was not rescheduled")
+	}
+}
+
+// Ensure that a timer rescheduled to an absolute time fires at exactly that
+// time, including one that has already fired or is rescheduled into the past.
+func TestMock_Timer_Reschedule(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	start := clock.Now()
+	deadline := start.Add(1500 * time.Millisecond)
+
+	timer := clock.NewTimer(time.Minute)
+	if !timer.Reschedule(deadline) {
+		t.Fatal("active timer reported inactive by Reschedule")
+	}
+	clock.Add(time.Minute)
+	if got := <-timer.C; !got.Equal(deadline) {
+		t.Fatalf("expected fire at %v, got %v", deadline, got)
+	}
+	if timer.Reschedule(start) {
+		t.Fatal("expired timer reported active by Reschedule")
+	}
+	clock.Add(0)
+	if got := <-timer.C; !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected a past deadline to fire at once, got %v", got)
+	}
+
+	var fired time.Time
+	fn := clock.AfterFunc(time.Second, func() { fired = clock.Now() })
+	fn.Reschedule(start.Add(2 * time.Minute))
+	clock.Add(2 * time.Minute)
+	if !fired.Equal(start.Add(2 * time.Minute)) {
+		t.Fatalf("expected AfterFunc at %v, got %v", start.Add(2*time.Minute), fired)
+	}
+}
+
+// Ensure that options act in all three phases, in order around the advance.
+func TestMock_OptionPhases(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	var phases []string
+	record := func(phase string) func(*UnsynchronizedMock) {
+		return func(m *UnsynchronizedMock) { phases = append(phases, phase+" "+m.Now().Format("15:04")) }
+	}
+	opt := &PhasedOption{Prior: record("prior"), Upcoming: record("upcoming"), After: record("after")}
+	clock.AfterFunc(time.Hour, func() { phases = append(phases, "fired") })
+
+	clock.Add(time.Hour, opt)
+	clock.SetReport(clock.Now().Add(time.Hour), &PhasedOption{After: record("report")})
+	want := "[prior 00:00 upcoming 00:00 fired after 01:00 report 02:00]"
+	if got := fmt.Sprint(phases); got != want {
+		t.Fatalf("phases = %s, want %s", got, want)
+	}
+}
+
+// Ensure that WaitAfter waits for asynchronous callbacks.
+func TestMock_WaitAfter(t *testing.T) {
+	clock := NewUnsynchronizedMock(AsyncAfterFuncs)
+	done := false
+	clock.AfterFunc(time.Second, func() {
+		time.Sleep(10 * time.Millisecond)
+		done = true
+	})
+	clock.Add(time.Second, WaitAfter)
+	if !done {
+		t.Fatal("Add returned before the callback")
+	}
+}
+
+// Ensure that OnUnexpectedUpcomingEvent reports timers that weren't
+// expected, without a testing.T.
+func TestMock_OnUnexpectedUpcomingEvent(t *testing.T) {
+	var unexpected []CheckpointName
+	mock := NewUnsynchronizedMock(OnUnexpectedUpcomingEvent(func(name CheckpointName) {
+		unexpected = append(unexpected, name)
+	}))
+
+	mock.ExpectStarts(1)
+	mock.NewTimer(time.Second)
+	if len(unexpected) != 0 {
+		t.Fatalf("unexpected events for an expected timer: %v", unexpected)
+	}
+
+	mock.NewTimer(time.Second)
+	if len(unexpected) != 1 || unexpected[0] != TimerStart {
+		t.Fatalf("expected one unexpected TimerStart, got %v", unexpected)
+	}
+}
+
+// Ensure that VerifyStarts fails a test that ends with timer starts still
+// outstanding.
+func TestMock_VerifyStarts(t *testing.T) {
+	experiment := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(VerifyStarts(experiment))
+	mock.ExpectStarts(2)
+	mock.NewTimer(time.Second)
+	experiment.runCleanups()
+	if !experiment.failed || len(experiment.logs) != 1 || experiment.logs[0] != "TimerStart: 1 expected Done calls never arrived" {
+		t.Fatalf("unexpected verification: %v", experiment.logs)
+	}
+}
+
+// Ensure that a timer held as a MockableTimer can be received from.
+func TestMock_Timer_Chan(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	var timer MockableTimer = mock.NewTimer(time.Second)
+	mock.Add(time.Second)
+	if now := <-timer.Chan(); !now.Equal(time.Unix(1, 0)) {
+		t.Fatalf("unexpected fire time: %v", now)
+	}
+
+	if c := mock.AfterFunc(time.Second, func() {}).Chan(); c != nil {
+		t.Fatal("expected no channel for AfterFunc")
+	}
+}
+
+// Ensure that an AfterFunc timer runs its callback again after each Reset,
+// whether it had fired, been stopped, or was still pending.
+func TestMock_AfterFunc_Reset(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		var opts []Option
+		if async {
+			opts = append(opts, AsyncAfterFuncs)
+		}
+		clock := NewUnsynchronizedMock(opts...)
+		var mu sync.Mutex
+		var runs []time.Time
+		timer := clock.AfterFunc(time.Second, func() {
+			mu.Lock()
+			runs = append(runs, clock.Now())
+			mu.Unlock()
+		})
+
+		// Pending: Reset moves the deadline.
+		if !timer.Reset(2 * time.Second) {
+			t.Fatal("pending AfterFunc reported inactive by Reset")
+		}
+		clock.Add(2*time.Second, WaitAfter)
+
+		// Fired: Reset schedules another run.
+		if timer.Reset(time.Second) {
+			t.Fatal("fired AfterFunc reported active by Reset")
+		}
+		clock.Add(time.Second, WaitAfter)
+
+		// Stopped: Reset schedules another run.
+		timer.Reset(time.Second)
+		timer.Stop()
+		if timer.Reset(time.Second) {
+			t.Fatal("stopped AfterFunc reported active by Reset")
+		}
+		clock.Add(5*time.Second, WaitAfter)
+
+		mu.Lock()
+		want := []time.Time{time.Unix(2, 0), time.Unix(3, 0), time.Unix(4, 0)}
+		if fmt.Sprint(runs) != fmt.Sprint(want) {
+			t.Fatalf("async=%v: runs = %v, want %v", async, runs, want)
+		}
+		mu.Unlock()
+	}
+}
+
+// Ensure that the fired event reports the deadline that fired, even if the
+// callback resets the timer.
+func TestMock_AfterFunc_ResetFromCallback(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	var fired []time.Time
+	clock.SetLogger(func(e Event) {
+		if e.Type == TimerFired {
+			fired = append(fired, e.Deadline)
+		}
+	})
+	var timer MockableTimer
+	timer = clock.AfterFunc(time.Second, func() { timer.Reset(time.Second) })
+	clock.Add(2 * time.Second)
+	want := []time.Time{time.Unix(1, 0), time.Unix(2, 0)}
+	if fmt.Sprint(fired) != fmt.Sprint(want) {
+		t.Fatalf("fired deadlines = %v, want %v", fired, want)
+	}
+}
+
+// Ensure that many timers fire in deadline order, however they were
+// stopped and reset along the way.
+func TestMock_ManyTimers(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	rng := rand.New(rand.NewSource(1))
+
+	var fired, want []int
+	timers := make([]MockableTimer, 300)
+	deadlines := make([]time.Duration, len(timers))
+	for i := range timers {
+		i := i
+		deadlines[i] = time.Duration(rng.Intn(100)) * time.Second
+		timers[i] = clock.AfterFunc(deadlines[i], func() { fired = append(fired, i) })
+	}
+	for i := range timers {
+		switch rng.Intn(3) {
+		case 0:
+			timers[i].Stop()
+			deadlines[i] = -1
+		case 1:
+			deadlines[i] = time.Duration(rng.Intn(100)) * time.Second
+			timers[i].Reset(deadlines[i])
+		}
+	}
+	for i, d := range deadlines {
+		if d >= 0 {
+			want = append(want, i)
+		}
+	}
+	// Timers due together fire in the order they were created.
+	sort.SliceStable(want, func(i, j int) bool { return deadlines[want[i]] < deadlines[want[j]] })
+
+	clock.Add(100 * time.Second)
+	if fmt.Sprint(fired) != fmt.Sprint(want) {
+		t.Fatalf("timers fired out of order:\n got %v\nwant %v", fired, want)
+	}
+	if n := len(clock.PendingTimers()); n != 0 {
+		t.Fatalf("expected no pending timers, got %d", n)
+	}
+}
+
+const benchTimers = 1000000
+
+// newBenchMock returns a mock with a million pending timers, as a
+// connection manager with a timeout per connection might have.
+func newBenchMock(b *testing.B) *UnsynchronizedMock {
+	b.Helper()
+	clock := NewUnsynchronizedMock()
+	for i := 0; i < benchTimers; i++ {
+		clock.AfterFunc(time.Duration(i+1)*time.Hour, func() {})
+	}
+	b.ResetTimer()
+	return clock
+}
+
+func BenchmarkMock_Add_MillionTimers(b *testing.B) {
+	clock := newBenchMock(b)
+	for i := 0; i < b.N; i++ {
+		clock.Add(time.Nanosecond)
+	}
+}
+
+func BenchmarkMock_AfterFunc_MillionTimers(b *testing.B) {
+	clock := newBenchMock(b)
+	for i := 0; i < b.N; i++ {
+		clock.AfterFunc(time.Duration(i%benchTimers)*time.Hour, func() {})
+	}
+}
+
+func BenchmarkMock_Reset_MillionTimers(b *testing.B) {
+	clock := newBenchMock(b)
+	timer := clock.AfterFunc(time.Hour, func() {})
+	for i := 0; i < b.N; i++ {
+		timer.Reset(time.Duration(i%benchTimers) * time.Hour)
+	}
+}
+
+func BenchmarkMock_Stop_MillionTimers(b *testing.B) {
+	clock := newBenchMock(b)
+	for i := 0; i < b.N; i++ {
+		clock.AfterFunc(time.Duration(i%benchTimers)*time.Hour, func() {}).Stop()
+	}
+}
+
+func BenchmarkMock_Now(b *testing.B) {
+	clock := NewUnsynchronizedMock()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			clock.Now()
+		}
+	})
+}
+
+// Ensure that both mocks implement the interfaces that packages driving a
+// mock accept.
+func TestMock_MockClock(t *testing.T) {
+	var _ MockClock = NewMock(t, 0)
+	var _ MockClock = NewUnsynchronizedMock()
+}
@@ -0,0 +1,54 @@
+package clock
+
+import "testing"
+
+// FailOnConcurrentAdvanceOption makes the mock check that advances don't
+// overlap.
+type FailOnConcurrentAdvanceOption struct {
+	tb testing.TB
+}
+
+// FailOnConcurrentAdvance fails tb, with the call stacks of both, whenever
+// an Add or Set starts while another is still advancing the clock. Advances
+// are meant to come from a single goroutine at a time; overlapping ones
+// interleave their timers in ways that are hard to make sense of. An
+// advance made from within a timer's callback overlaps too.
+func FailOnConcurrentAdvance(tb testing.TB) *FailOnConcurrentAdvanceOption {
+	return &FailOnConcurrentAdvanceOption{tb}
+}
+
+func (o *FailOnConcurrentAdvanceOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *FailOnConcurrentAdvanceOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.overlapTB = o.tb
+}
+
+// beginAdvance records that an advance is underway, failing the test if one
+// already was and overlaps are being checked. The returned function ends it.
+func (m *UnsynchronizedMock) beginAdvance() func() {
+	m.mu.Lock()
+	tb := m.overlapTB
+	if tb == nil {
+		m.mu.Unlock()
+		return func() {}
+	}
+	stack := callers()
+	prior := m.advancing
+	if prior == nil {
+		m.advancing = stack
+	}
+	m.mu.Unlock()
+
+	if prior != nil {
+		tb.Errorf("clock advanced concurrently\nthis advance:\n%s\noverlaps the advance from:\n%s",
+			formatStack(stack), formatStack(prior))
+		return func() {}
+	}
+	return func() {
+		m.mu.Lock()
+		m.advancing = nil
+		m.mu.Unlock()
+	}
+}
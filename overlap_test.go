@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that overlapping advances fail the test with both call stacks.
+func TestMock_FailOnConcurrentAdvance(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(FailOnConcurrentAdvance(tb))
+	started, release := make(chan struct{}), make(chan struct{})
+	mock.AfterFunc(time.Second, func() {
+		close(started)
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		mock.Add(time.Second)
+		close(done)
+	}()
+	<-started
+	mock.Add(0)
+	close(release)
+	<-done
+
+	assert.True(t, tb.failed)
+	if assert.Len(t, tb.logs, 1) {
+		assert.Contains(t, tb.logs[0], "clock advanced concurrently")
+		assert.Contains(t, tb.logs[0], "TestMock_FailOnConcurrentAdvance.func2")
+	}
+}
+
+// Ensure that advances one after another are not reported.
+func TestMock_FailOnConcurrentAdvance_Sequential(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(FailOnConcurrentAdvance(tb))
+	mock.AfterFunc(time.Second, func() {})
+	mock.Add(time.Second)
+	mock.Set(time.Unix(10, 0))
+	assert.False(t, tb.failed)
+}
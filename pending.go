@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// TimerKind describes how a pending timer was created.
+type TimerKind string
+
+const (
+	KindTimer     TimerKind = "Timer"
+	KindTicker    TimerKind = "Ticker"
+	KindAfterFunc TimerKind = "AfterFunc"
+	KindEvent     TimerKind = "Event" // a TimedEvent, from RegisterEvent
+)
+
+// TimerInfo describes a timer or ticker that is scheduled on a mock clock.
+type TimerInfo struct {
+	ID       uint64        // matches Timer.ID or Ticker.ID
+	Name     string        // name given with TimerName, if any
+	Kind     TimerKind     // how the timer was created
+	Deadline time.Time     // next time the timer will fire
+	Interval time.Duration // time between ticks, for tickers
+	Stack    string        // call stack that created the timer
+}
+
+// PendingTimers returns every timer and ticker that is currently scheduled
+// on the mock, in the order they will fire. Their Stack is empty unless the
+// mock was set to CaptureStacks when they were created.
+func (m *UnsynchronizedMock) PendingTimers() []TimerInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sorted := m.timers.sorted()
+	ret := make([]TimerInfo, 0, len(sorted))
+	for _, t := range sorted {
+		ret = append(ret, t.info())
+	}
+	return ret
+}
+
+func (t *internalTimer) info() TimerInfo {
+	kind := KindTimer
+	if t.fn != nil {
+		kind = KindAfterFunc
+	}
+	return TimerInfo{ID: t.id, Name: t.name, Kind: kind, Deadline: t.next, Stack: formatStack(t.stack)}
+}
+
+func (t *internalTicker) info() TimerInfo {
+	return TimerInfo{ID: t.id, Name: t.name, Kind: KindTicker, Deadline: t.next, Interval: t.d, Stack: formatStack(t.stack)}
+}
+
+// CaptureStacks sets whether the mock records the call stack that creates
+// each timer and ticker, for the Stack of PendingTimers. It is off by
+// default, since capturing a stack for every timer is slow and keeps memory
+// for each, which adds up in tests with many timers.
+func (m *UnsynchronizedMock) CaptureStacks(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&m.captureStacks, v)
+}
+
+// callers is like the package-level callers, but returns nil unless the
+// mock captures stacks.
+func (m *UnsynchronizedMock) callers() []uintptr {
+	if atomic.LoadInt32(&m.captureStacks) == 0 {
+		return nil
+	}
+	return stack(4)
+}
+
+// callers returns the call stack of the function that called the caller of
+// callers, i.e. whoever asked for a new timer.
+func callers() []uintptr {
+	return stack(4)
+}
+
+// stack returns the call stack, skipping skip frames as runtime.Callers
+// does, in a slice of exactly its length.
+func stack(skip int) []uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// formatStack renders a stack captured by callers in the style of
+// runtime/debug.Stack.
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that pending timers are reported in deadline order with their kind.
+func TestMock_PendingTimers(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	clock.CaptureStacks(true)
+	clock.Add(10 * time.Second)
+
+	timer := clock.NewTimer(30 * time.Second)
+	ticker := clock.NewTicker(5 * time.Second)
+	fn := clock.AfterFunc(20*time.Second, func() {})
+
+	pending := clock.PendingTimers()
+	if assert.Len(t, pending, 3) {
+		assert.Equal(t, ticker.ID(), pending[0].ID)
+		assert.Equal(t, KindTicker, pending[0].Kind)
+		assert.Equal(t, time.Unix(15, 0), pending[0].Deadline)
+		assert.Equal(t, 5*time.Second, pending[0].Interval)
+
+		assert.Equal(t, fn.(*Timer).ID(), pending[1].ID)
+		assert.Equal(t, KindAfterFunc, pending[1].Kind)
+		assert.Equal(t, time.Unix(30, 0), pending[1].Deadline)
+
+		assert.Equal(t, timer.ID(), pending[2].ID)
+		assert.Equal(t, KindTimer, pending[2].Kind)
+		assert.Equal(t, time.Unix(40, 0), pending[2].Deadline)
+		assert.Contains(t, pending[2].Stack, "TestMock_PendingTimers")
+	}
+
+	// Fired and stopped timers are no longer pending
+	timer.Stop()
+	clock.Add(20 * time.Second)
+	pending = clock.PendingTimers()
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, ticker.ID(), pending[0].ID)
+		assert.Equal(t, time.Unix(35, 0), pending[0].Deadline)
+	}
+}
+
+// Ensure that stacks are only captured when asked for, and are kept without
+// spare capacity.
+func TestMock_CaptureStacks(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	timer := clock.NewTimer(time.Second)
+	assert.Nil(t, timer.stack)
+	assert.Empty(t, clock.PendingTimers()[0].Stack)
+
+	clock.CaptureStacks(true)
+	timer = clock.NewTimer(time.Second)
+	assert.NotEmpty(t, timer.stack)
+	assert.Equal(t, len(timer.stack), cap(timer.stack))
+	assert.Contains(t, clock.PendingTimers()[1].Stack, "TestMock_CaptureStacks")
+}
@@ -0,0 +1,48 @@
+package clock
+
+import "time"
+
+// StartOfDay returns midnight at the start of the current day on c, in loc.
+// If loc is nil, the location of c's Now is used. On days when midnight is
+// skipped by a daylight saving change, it returns the first instant of the
+// day.
+func StartOfDay(c NowClock, loc *time.Location) time.Time {
+	now := in(c, loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// StartOfWeek returns midnight at the start of the current week on c, in
+// loc. Weeks start on Monday, as in ISO 8601. If loc is nil, the location of
+// c's Now is used.
+func StartOfWeek(c NowClock, loc *time.Location) time.Time {
+	now := in(c, loc)
+	days := (int(now.Weekday()) + 6) % 7 // days since Monday
+	return time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, now.Location())
+}
+
+// StartOfMonth returns midnight at the start of the current month on c, in
+// loc. If loc is nil, the location of c's Now is used.
+func StartOfMonth(c NowClock, loc *time.Location) time.Time {
+	now := in(c, loc)
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// NextBoundary returns the first multiple of d after c's Now, counting from
+// the zero time, as the ticks of an aligned ticker fall. Like Truncate, it
+// works in absolute time, so boundaries of an hour or more don't follow
+// loc's offset from UTC.
+func NextBoundary(c NowClock, d time.Duration) time.Time {
+	if d <= 0 {
+		panic("non-positive interval for NextBoundary")
+	}
+	now := c.Now()
+	return now.Truncate(d).Add(d)
+}
+
+func in(c NowClock, loc *time.Location) time.Time {
+	now := c.Now()
+	if loc == nil {
+		return now
+	}
+	return now.In(loc)
+}
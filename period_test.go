@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the start-of-period helpers follow the clock.
+func TestStartOfPeriod(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Date(2024, time.March, 14, 15, 9, 26, 0, time.UTC)) // a Thursday
+
+	assert.Equal(t, time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC), StartOfDay(mock, nil))
+	assert.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), StartOfWeek(mock, nil))
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), StartOfMonth(mock, nil))
+
+	// Sundays belong to the week that started the Monday before.
+	mock.Set(time.Date(2024, time.March, 17, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), StartOfWeek(mock, nil))
+
+	// In another zone, the day may not have started yet.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	assert.Equal(t, time.Date(2024, time.March, 18, 0, 0, 0, 0, tokyo), StartOfDay(mock, tokyo))
+	assert.Equal(t, time.Date(2024, time.March, 18, 0, 0, 0, 0, tokyo), StartOfWeek(mock, tokyo))
+}
+
+// Ensure that NextBoundary is strictly after now.
+func TestNextBoundary(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Unix(90, 0))
+	assert.Equal(t, time.Unix(120, 0), NextBoundary(mock, time.Minute))
+	mock.Set(time.Unix(120, 0))
+	assert.Equal(t, time.Unix(180, 0), NextBoundary(mock, time.Minute))
+	assert.Panics(t, func() { NextBoundary(mock, 0) })
+}
@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// playFrame is how often, in real time, a Playback moves the mock.
+const playFrame = 10 * time.Millisecond
+
+// Playback advances a mock clock continuously, in step with real time. It is
+// returned by Play.
+type Playback struct {
+	mock  *UnsynchronizedMock
+	speed float64
+	end   time.Time
+
+	mu     sync.Mutex
+	paused bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Play advances the mock by d, continuously rather than all at once, at
+// speed times real time, so that demos and soak tests can watch behavior
+// unfold. A speed of 60 plays a minute of virtual time every real second.
+// Timers fire as the clock reaches them, as with Set. The clock should not be
+// advanced by anything else until the playback is done.
+func (m *UnsynchronizedMock) Play(d time.Duration, speed float64) *Playback {
+	if speed <= 0 {
+		panic("non-positive speed for Play")
+	}
+	p := &Playback{
+		mock:  m,
+		speed: speed,
+		end:   m.current().Add(d),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Pause stops the clock moving until Resume is called.
+func (p *Playback) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// Resume carries on after Pause.
+func (p *Playback) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+}
+
+// Stop ends the playback where it is, and waits for the clock to stop
+// moving.
+func (p *Playback) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// Done returns a channel that is closed once the playback has played all of
+// its duration, or been stopped.
+func (p *Playback) Done() <-chan struct{} {
+	return p.done
+}
+
+func (p *Playback) run() {
+	defer close(p.done)
+	frame := time.NewTicker(playFrame)
+	defer frame.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-frame.C:
+			elapsed := now.Sub(last)
+			last = now
+			p.mu.Lock()
+			paused := p.paused
+			p.mu.Unlock()
+			if paused {
+				continue
+			}
+
+			next := p.mock.current().Add(time.Duration(float64(elapsed) * p.speed))
+			if !next.Before(p.end) {
+				p.mock.Set(p.end)
+				return
+			}
+			p.mock.Set(next)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that playback moves the clock through its timers and stops at the
+// end of its duration.
+func TestMock_Play(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	timer := mock.NewTimer(500 * time.Millisecond)
+
+	start := time.Now()
+	p := mock.Play(time.Second, 20)
+	<-p.Done()
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, time.Unix(1, 0), mock.Now())
+	assert.Equal(t, time.Unix(0, 0).Add(500*time.Millisecond), <-timer.C)
+}
+
+// Ensure that a paused playback holds the clock still, and a stopped one
+// ends early.
+func TestMock_Play_PauseStop(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	p := mock.Play(time.Hour, 1000)
+	time.Sleep(5 * playFrame)
+	p.Pause()
+	time.Sleep(2 * playFrame)
+	paused := mock.Now()
+	assert.True(t, paused.After(time.Unix(0, 0)))
+	time.Sleep(5 * playFrame)
+	assert.Equal(t, paused, mock.Now())
+
+	p.Resume()
+	time.Sleep(5 * playFrame)
+	p.Stop()
+	stopped := mock.Now()
+	assert.True(t, stopped.After(paused))
+	assert.True(t, stopped.Before(time.Unix(3600, 0)))
+	select {
+	case <-p.Done():
+	default:
+		t.Fatal("stopped playback not done")
+	}
+}
+
+// Ensure that Play rejects a speed that would never reach the end.
+func TestMock_Play_Speed(t *testing.T) {
+	assert.PanicsWithValue(t, "non-positive speed for Play", func() {
+		NewUnsynchronizedMock().Play(time.Second, 0)
+	})
+}
@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPollExhausted is returned by PollBackoff when the policy gives up
+// before the condition is met.
+var ErrPollExhausted = errors.New("clock: gave up polling")
+
+// Poll calls cond every interval on c until it reports true. See
+// PollBackoff.
+func Poll(ctx context.Context, c MockableClock, interval time.Duration, cond func(ctx context.Context) (bool, error)) error {
+	return PollBackoff(ctx, c, ConstantBackoff{Interval: interval}, cond)
+}
+
+// PollBackoff calls cond until it reports true, waiting between calls as
+// directed by policy, so polling can back off or be jittered with
+// ExponentialBackoff and JitteredBackoff. cond is called once straight away.
+// Waits are measured on c, so a mock will see one timer start per non-zero
+// wait.
+//
+// It returns nil once cond reports true. Otherwise it returns cond's error
+// if it returns one, ErrPollExhausted if the policy gives up, or ctx's error
+// if ctx is done first.
+func PollBackoff(ctx context.Context, c MockableClock, policy BackoffPolicy, cond func(ctx context.Context) (bool, error)) error {
+	for attempts := 1; ; attempts++ {
+		done, err := cond(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		d, ok := policy.Backoff(attempts)
+		if !ok {
+			return ErrPollExhausted
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d <= 0 {
+			continue
+		}
+		if err := c.SleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
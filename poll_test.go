@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Poll checks the condition every interval until it holds.
+func TestPoll(t *testing.T) {
+	clock := NewMock(t, 1)
+
+	var checks int
+	done := make(chan error, 1)
+	go func() {
+		done <- Poll(context.Background(), clock, time.Second, func(ctx context.Context) (bool, error) {
+			checks++
+			return checks == 3, nil
+		})
+	}()
+
+	clock.Add(time.Second, ExpectUpcomingStarts(1))
+	select {
+	case <-done:
+		t.Fatal("too early")
+	default:
+	}
+	clock.Add(time.Second)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, 3, checks)
+	case <-time.After(time.Second):
+		t.Fatal("too late")
+	}
+}
+
+// Ensure that polling stops on an error, when the policy gives up, or when
+// the context is done.
+func TestPollBackoff_Stop(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	failure := errors.New("failure")
+	err := PollBackoff(context.Background(), clock, ConstantBackoff{}, func(ctx context.Context) (bool, error) {
+		return false, failure
+	})
+	assert.Equal(t, failure, err)
+
+	err = PollBackoff(context.Background(), clock, ConstantBackoff{MaxAttempts: 3}, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	assert.Equal(t, ErrPollExhausted, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = Poll(ctx, clock, time.Hour, func(ctx context.Context) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
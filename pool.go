@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+var timerPool sync.Pool
+
+// AcquireTimer is like c.NewTimer, but for the realtime clock it reuses a
+// timer released with ReleaseTimer, rather than allocating a new Timer and
+// runtime timer on every call. It suits high-frequency timeout patterns:
+//
+//	t := clock.AcquireTimer(c, timeout)
+//	defer clock.ReleaseTimer(t)
+//	select {
+//	case v := <-ch:
+//	case <-t.C:
+//	}
+//
+// Other clocks, including the mocks, return c.NewTimer(d).
+func AcquireTimer(c MockableClock, d time.Duration) *Timer {
+	if !IsRealtime(c) {
+		return c.NewTimer(d)
+	}
+	if t, ok := timerPool.Get().(*Timer); ok {
+		t.timer.Reset(d)
+		return t
+	}
+	return c.NewTimer(d)
+}
+
+// ReleaseTimer stops t and returns it to the pool for AcquireTimer to reuse,
+// if it is a realtime timer with a channel; timers from AfterFunc are only
+// stopped. t must not be used afterwards, and nothing may still be receiving
+// from its channel.
+func ReleaseTimer(t *Timer) {
+	rt, ok := t.timer.(*time.Timer)
+	if !ok || t.C == nil || t.C != rt.C {
+		t.Stop()
+		return
+	}
+	if !rt.Stop() {
+		// Drain a value that was sent and not received, so that the next
+		// user doesn't see it.
+		select {
+		case <-rt.C:
+		default:
+		}
+	}
+	timerPool.Put(t)
+}
@@ -0,0 +1,95 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that released timers are reused without leaking stale values.
+func TestAcquireTimer(t *testing.T) {
+	c := New()
+	timer := AcquireTimer(c, time.Millisecond)
+	time.Sleep(5 * time.Millisecond) // fired, but not received
+	ReleaseTimer(timer)
+
+	timer = AcquireTimer(c, time.Hour)
+	select {
+	case <-timer.C:
+		t.Fatal("stale value from a released timer")
+	case <-time.After(10 * time.Millisecond):
+	}
+	ReleaseTimer(timer)
+
+	timer = AcquireTimer(c, time.Millisecond)
+	<-timer.C
+	ReleaseTimer(timer)
+}
+
+// Ensure that AfterFunc timers are stopped but not pooled, so their callback
+// isn't rearmed by AcquireTimer.
+func TestReleaseTimer_AfterFunc(t *testing.T) {
+	c := New()
+	called := make(chan struct{}, 1)
+	ReleaseTimer(c.AfterFunc(time.Hour, func() { called <- struct{}{} }).(*Timer))
+
+	for i := 0; i < 10; i++ {
+		timer := AcquireTimer(c, time.Millisecond)
+		if timer.C == nil {
+			t.Fatal("AcquireTimer returned an AfterFunc timer")
+		}
+		<-timer.C
+		ReleaseTimer(timer)
+	}
+	select {
+	case <-called:
+		t.Fatal("released AfterFunc callback ran")
+	default:
+	}
+}
+
+// Ensure that other clocks get ordinary timers, which aren't pooled.
+func TestAcquireTimer_Mock(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	timer := AcquireTimer(mock, time.Second)
+	assert.Len(t, mock.PendingTimers(), 1)
+	mock.Add(time.Second)
+	<-timer.C
+	ReleaseTimer(timer)
+}
+
+func BenchmarkNewTimer(b *testing.B) {
+	c := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t := c.NewTimer(time.Hour)
+		t.Stop()
+	}
+}
+
+func BenchmarkAcquireTimer(b *testing.B) {
+	c := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReleaseTimer(AcquireTimer(c, time.Hour))
+	}
+}
+
+func BenchmarkSleepContext(b *testing.B) {
+	c := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.SleepContext(ctx, time.Hour)
+	}
+}
+
+// Ensure that releasing a mock timer stops it, though it isn't pooled.
+func TestReleaseTimer_Mock(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ReleaseTimer(AcquireTimer(mock, time.Second))
+	assert.Empty(t, mock.PendingTimers())
+}
@@ -0,0 +1,421 @@
+package clock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordOp identifies what happened in a Record.
+type RecordOp string
+
+const (
+	OpStart  RecordOp = "start"  // the recorder was created
+	OpNow    RecordOp = "now"    // the time was read
+	OpSleep  RecordOp = "sleep"  // a sleep returned
+	OpTimer  RecordOp = "timer"  // a timer was created
+	OpTicker RecordOp = "ticker" // a ticker was created
+	OpFire   RecordOp = "fire"   // a timer fired or a ticker ticked
+	OpStop   RecordOp = "stop"   // a timer or ticker was stopped
+	OpReset  RecordOp = "reset"  // a timer or ticker was reset
+)
+
+// Record is one line of a timeline written by a Recorder.
+type Record struct {
+	Op       RecordOp      `json:"op"`
+	Time     time.Time     `json:"time"`               // clock time when it happened
+	ID       uint64        `json:"id,omitempty"`       // timer or ticker involved, if any
+	Duration time.Duration `json:"duration,omitempty"` // sleep, timer or ticker duration, if any
+	Aligned  bool          `json:"aligned,omitempty"`  // the ticker was aligned
+	Canceled bool          `json:"canceled,omitempty"` // the sleep was interrupted by its context
+}
+
+// Recorder is a MockableClock that writes every call it passes on to another
+// clock, and every timer event, to a timeline of JSON Records. Replaying the
+// timeline with NewReplay reproduces the same times and timer events
+// deterministically, for turning timing-dependent bugs seen in a real run
+// into tests.
+//
+// Channels of timers and tickers created through the Recorder are fed by a
+// goroutine that records each value before passing it on.
+type Recorder struct {
+	clock MockableClock
+
+	mu     sync.Mutex
+	enc    *json.Encoder
+	nextID uint64
+	err    error
+}
+
+// NewRecorder returns a Recorder that passes calls on to c and writes the
+// timeline to w.
+func NewRecorder(c MockableClock, w io.Writer) *Recorder {
+	r := &Recorder{clock: c, enc: json.NewEncoder(w)}
+	r.record(Record{Op: OpStart, Time: c.Now()})
+	return r
+}
+
+// Err returns the first error encountered writing the timeline, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(rec)
+	}
+}
+
+func (r *Recorder) newID() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	return r.nextID
+}
+
+func (r *Recorder) Now() time.Time {
+	t := r.clock.Now()
+	r.record(Record{Op: OpNow, Time: t})
+	return t
+}
+
+func (r *Recorder) Since(t time.Time) time.Duration { return r.Now().Sub(t) }
+
+func (r *Recorder) Sleep(d time.Duration) {
+	r.clock.Sleep(d)
+	r.record(Record{Op: OpSleep, Time: r.clock.Now(), Duration: d})
+}
+
+func (r *Recorder) SleepUntil(t time.Time) { r.Sleep(t.Sub(r.clock.Now())) }
+
+func (r *Recorder) SleepContext(ctx context.Context, d time.Duration) error {
+	err := r.clock.SleepContext(ctx, d)
+	r.record(Record{Op: OpSleep, Time: r.clock.Now(), Duration: d, Canceled: err != nil})
+	return err
+}
+
+func (r *Recorder) After(d time.Duration) <-chan time.Time { return r.NewTimer(d).C }
+
+func (r *Recorder) AfterAt(t time.Time) <-chan time.Time {
+	return r.NewTimer(t.Sub(r.clock.Now())).C
+}
+
+func (r *Recorder) AfterFunc(d time.Duration, f func()) MockableTimer {
+	id := r.newID()
+	r.record(Record{Op: OpTimer, Time: r.clock.Now(), ID: id, Duration: d})
+	t := r.clock.AfterFunc(d, func() {
+		r.record(Record{Op: OpFire, Time: r.clock.Now(), ID: id})
+		f()
+	})
+	return WrapTimer(nil, &recordedTimer{r: r, id: id, t: t})
+}
+
+func (r *Recorder) AtFunc(t time.Time, f func()) MockableTimer {
+	return r.AfterFunc(t.Sub(r.clock.Now()), f)
+}
+
+func (r *Recorder) NewTimer(d time.Duration) *Timer {
+	id := r.newID()
+	r.record(Record{Op: OpTimer, Time: r.clock.Now(), ID: id, Duration: d})
+	t := r.clock.NewTimer(d)
+	return ForwardTimer(t, &recordedTimer{r: r, id: id, t: t}, func(v time.Time) {
+		r.record(Record{Op: OpFire, Time: v, ID: id})
+	})
+}
+
+func (r *Recorder) Tick(d time.Duration) <-chan time.Time { return r.NewTicker(d).C }
+
+func (r *Recorder) NewTicker(d time.Duration) *Ticker {
+	return r.newTicker(d, false, r.clock.NewTicker(d))
+}
+
+func (r *Recorder) NewAlignedTicker(d time.Duration) *Ticker {
+	return r.newTicker(d, true, r.clock.NewAlignedTicker(d))
+}
+
+func (r *Recorder) newTicker(d time.Duration, aligned bool, t *Ticker) *Ticker {
+	id := r.newID()
+	r.record(Record{Op: OpTicker, Time: r.clock.Now(), ID: id, Duration: d, Aligned: aligned})
+	rt := &recordedTicker{r: r, id: id, t: t, done: make(chan struct{})}
+	c := make(chan time.Time, 1)
+	go func() {
+		for {
+			select {
+			case v := <-t.C:
+				r.record(Record{Op: OpFire, Time: v, ID: id})
+				select {
+				case c <- v:
+				default:
+				}
+			case <-rt.done:
+				return
+			}
+		}
+	}()
+	return WrapTicker(c, rt)
+}
+
+type recordedTimer struct {
+	r  *Recorder
+	id uint64
+	t  MockableTimer
+}
+
+func (t *recordedTimer) Stop() bool {
+	ret := t.t.Stop()
+	t.r.record(Record{Op: OpStop, Time: t.r.clock.Now(), ID: t.id})
+	return ret
+}
+
+func (t *recordedTimer) Reset(d time.Duration) bool {
+	ret := t.t.Reset(d)
+	t.r.record(Record{Op: OpReset, Time: t.r.clock.Now(), ID: t.id, Duration: d})
+	return ret
+}
+
+func (t *recordedTimer) Reschedule(at time.Time) bool {
+	now := t.r.clock.Now()
+	ret := t.t.Reschedule(at)
+	t.r.record(Record{Op: OpReset, Time: now, ID: t.id, Duration: at.Sub(now)})
+	return ret
+}
+
+type recordedTicker struct {
+	r    *Recorder
+	id   uint64
+	t    *Ticker
+	once sync.Once
+	done chan struct{}
+}
+
+func (t *recordedTicker) Stop() {
+	t.t.Stop()
+	t.once.Do(func() { close(t.done) })
+	t.r.record(Record{Op: OpStop, Time: t.r.clock.Now(), ID: t.id})
+}
+
+func (t *recordedTicker) Reset(d time.Duration) {
+	t.t.Reset(d)
+	t.r.record(Record{Op: OpReset, Time: t.r.clock.Now(), ID: t.id, Duration: d})
+}
+
+// ErrTimelineMismatch is reported by a Replay when the code under test makes
+// a different call from the one recorded.
+var ErrTimelineMismatch = errors.New("call does not match recorded timeline")
+
+// Replay is a MockableClock that plays back a timeline written by a Recorder.
+// Each call returns the recorded result, and moves virtual time to when the
+// recorded call returned, so timers created during replay fire at the same
+// points in the timeline as they did when it was recorded.
+//
+// Timers deliver the recorded values, but tickers tick at exact multiples of
+// their interval, without the jitter of the recorded ticks.
+//
+// Calls are matched to the timeline in order, so replay is deterministic only
+// when the code under test makes its calls in a deterministic order. If a
+// call doesn't match the timeline, Err reports it, and the call behaves as it
+// would on a mock without moving virtual time.
+type Replay struct {
+	mock *UnsynchronizedMock
+
+	mu      sync.Mutex
+	records []Record
+	pos     int
+	err     error
+}
+
+// NewReplay reads a timeline written by a Recorder and returns a Replay
+// starting at its start time.
+func NewReplay(r io.Reader) (*Replay, error) {
+	dec := json.NewDecoder(r)
+	var records []Record
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("timeline record %d: %w", len(records)+1, err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) == 0 || records[0].Op != OpStart {
+		return nil, fmt.Errorf("timeline does not begin with a %q record", OpStart)
+	}
+	mock := NewUnsynchronizedMock()
+	mock.Set(records[0].Time)
+	return &Replay{mock: mock, records: records, pos: 1}, nil
+}
+
+// Err returns the first mismatch between the calls made and the timeline.
+func (r *Replay) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Done reports whether every call in the timeline has been replayed.
+func (r *Replay) Done() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range r.records[r.pos:] {
+		if isCall(rec.Op) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCall reports whether op records a call to the clock, as opposed to
+// something done to, or by, a timer.
+func isCall(op RecordOp) bool {
+	switch op {
+	case OpNow, OpSleep, OpTimer, OpTicker:
+		return true
+	}
+	return false
+}
+
+// next consumes the next call in the timeline, which should be op, and moves
+// virtual time to when it returned.
+func (r *Replay) next(op RecordOp) (Record, bool) {
+	r.settle()
+	r.mu.Lock()
+	var rec Record
+	found := false
+	if r.pos >= len(r.records) {
+		if r.err == nil {
+			r.err = fmt.Errorf("%w: %s called after the end of the timeline", ErrTimelineMismatch, op)
+		}
+	} else if rec = r.records[r.pos]; rec.Op == op {
+		r.pos++
+		found = true
+	} else if r.err == nil {
+		r.err = fmt.Errorf("%w: %s called, but %s recorded at %s", ErrTimelineMismatch, op, rec.Op, rec.Time)
+	}
+	r.mu.Unlock()
+	if found {
+		r.advanceTo(rec.Time)
+	}
+	return rec, found
+}
+
+// settle consumes the timer events that were recorded before the next call,
+// moving virtual time to each in turn so that the replayed timers fire. It is
+// called after each call takes effect, since the events may involve a timer
+// the call has just created.
+func (r *Replay) settle() {
+	for {
+		r.mu.Lock()
+		if r.pos >= len(r.records) || isCall(r.records[r.pos].Op) {
+			r.mu.Unlock()
+			return
+		}
+		rec := r.records[r.pos]
+		r.pos++
+		r.mu.Unlock()
+		r.advanceTo(rec.Time)
+	}
+}
+
+func (r *Replay) advanceTo(t time.Time) {
+	if t.After(r.mock.Now()) {
+		r.mock.Set(t)
+	}
+}
+
+func (r *Replay) Now() time.Time {
+	defer r.settle()
+	if rec, ok := r.next(OpNow); ok {
+		return rec.Time
+	}
+	return r.mock.Now()
+}
+
+func (r *Replay) Since(t time.Time) time.Duration { return r.Now().Sub(t) }
+
+func (r *Replay) Sleep(d time.Duration) {
+	r.next(OpSleep)
+	r.settle()
+}
+
+func (r *Replay) SleepUntil(t time.Time) { r.Sleep(t.Sub(r.mock.Now())) }
+
+func (r *Replay) SleepContext(ctx context.Context, d time.Duration) error {
+	defer r.settle()
+	rec, ok := r.next(OpSleep)
+	if !ok || !rec.Canceled {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return context.Canceled
+}
+
+func (r *Replay) After(d time.Duration) <-chan time.Time { return r.NewTimer(d).C }
+
+func (r *Replay) AfterAt(t time.Time) <-chan time.Time { return r.NewTimer(t.Sub(r.mock.Now())).C }
+
+func (r *Replay) AfterFunc(d time.Duration, f func()) MockableTimer {
+	defer r.settle()
+	if rec, ok := r.next(OpTimer); ok {
+		d = r.timerDuration(rec)
+	}
+	return r.mock.AfterFunc(d, f)
+}
+
+func (r *Replay) AtFunc(t time.Time, f func()) MockableTimer {
+	return r.AfterFunc(t.Sub(r.mock.Now()), f)
+}
+
+func (r *Replay) NewTimer(d time.Duration) *Timer {
+	defer r.settle()
+	if rec, ok := r.next(OpTimer); ok {
+		d = r.timerDuration(rec)
+	}
+	return r.mock.NewTimer(d)
+}
+
+// timerDuration returns the duration for replaying the timer created by rec.
+// If it next fired rather than being stopped or reset, that is the time it
+// took to fire, so the replayed timer fires at the recorded time and delivers
+// the recorded value.
+func (r *Replay) timerDuration(rec Record) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, later := range r.records[r.pos:] {
+		if later.ID == rec.ID && !isCall(later.Op) {
+			if later.Op == OpFire {
+				return later.Time.Sub(rec.Time)
+			}
+			break
+		}
+	}
+	return rec.Duration
+}
+
+func (r *Replay) Tick(d time.Duration) <-chan time.Time { return r.NewTicker(d).C }
+
+func (r *Replay) NewTicker(d time.Duration) *Ticker {
+	defer r.settle()
+	if rec, ok := r.next(OpTicker); ok {
+		d = rec.Duration
+	}
+	return r.mock.NewTicker(d)
+}
+
+func (r *Replay) NewAlignedTicker(d time.Duration) *Ticker {
+	defer r.settle()
+	if rec, ok := r.next(OpTicker); ok {
+		d = rec.Duration
+	}
+	return r.mock.NewAlignedTicker(d)
+}
@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// timedProgram stands in for timing-dependent code under test, returning the
+// times it observed.
+func timedProgram(c MockableClock) []time.Time {
+	seen := []time.Time{c.Now()}
+	c.Sleep(2 * time.Millisecond)
+	seen = append(seen, c.Now())
+
+	timer := c.NewTimer(3 * time.Millisecond)
+	seen = append(seen, <-timer.C)
+
+	done := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.SleepContext(ctx, time.Hour); err == nil {
+		seen = append(seen, time.Time{})
+	}
+	return append(seen, c.Now())
+}
+
+// Ensure that replaying a recorded timeline reproduces the same times.
+func TestRecorder_Replay(t *testing.T) {
+	var timeline bytes.Buffer
+	recorder := NewRecorder(New(), &timeline)
+	recorded := timedProgram(recorder)
+	assert.NoError(t, recorder.Err())
+
+	replay, err := NewReplay(bytes.NewReader(timeline.Bytes()))
+	assert.NoError(t, err)
+	replayed := timedProgram(replay)
+	assert.NoError(t, replay.Err())
+	assert.True(t, replay.Done())
+
+	if assert.Equal(t, len(recorded), len(replayed)) {
+		for i := range recorded {
+			assert.True(t, recorded[i].Equal(replayed[i]), "time %d: recorded %v, replayed %v", i, recorded[i], replayed[i])
+		}
+	}
+}
+
+// Ensure that calls which differ from the timeline are reported.
+func TestReplay_Mismatch(t *testing.T) {
+	var timeline bytes.Buffer
+	recorder := NewRecorder(NewUnsynchronizedMock(), &timeline)
+	recorder.Now()
+
+	replay, err := NewReplay(&timeline)
+	assert.NoError(t, err)
+	replay.NewTimer(time.Second)
+	assert.True(t, errors.Is(replay.Err(), ErrTimelineMismatch))
+	assert.False(t, replay.Done())
+
+	_, err = NewReplay(strings.NewReader(`{"op": "now"}`))
+	assert.Error(t, err)
+}
+
+// Ensure that recorded timers that have fired leave no goroutines behind.
+func TestRecorder_TimerNoLeak(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	recorder := NewRecorder(mock, io.Discard)
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 1000; i++ {
+		ch := recorder.After(time.Second)
+		mock.Add(time.Second)
+		<-ch
+	}
+	assert.True(t, waitGoroutines(before+10), "%d goroutines left, from %d", runtime.NumGoroutine(), before)
+}
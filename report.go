@@ -0,0 +1,60 @@
+package clock
+
+import "time"
+
+// AdvanceReport summarizes what happened during an advance of a mock.
+type AdvanceReport struct {
+	From time.Time // time before the advance
+	To   time.Time // time after the advance
+
+	// Fired lists each timer that fired and each tick, in the order they
+	// happened. Deadline is the time each fired at. Ticks are counted
+	// whether or not the ticker had room for them.
+	Fired []TimerInfo
+
+	// Vetoed is the error from the OnBeforeAdvance hook that vetoed the
+	// advance, if one did, in which case To is From.
+	Vetoed error
+}
+
+// Count returns how many times timers of the given kind fired: channel
+// timers for KindTimer, ticks for KindTicker, and callbacks run for
+// KindAfterFunc.
+func (r *AdvanceReport) Count(kind TimerKind) int {
+	n := 0
+	for _, t := range r.Fired {
+		if t.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// AddReport is like Add, but returns a report of the timers that fired and
+// ticks and callbacks that were delivered as the mock moved forward.
+func (m *UnsynchronizedMock) AddReport(d time.Duration, opts ...Option) *AdvanceReport {
+	m.applyOptions(opts)
+	m.mu.Lock()
+	t := m.now.Add(d)
+	m.mu.Unlock()
+	report := m.advanceReport(t)
+	m.finishOptions(opts)
+	return report
+}
+
+// SetReport is like Set, but returns a report of the timers that fired and
+// ticks and callbacks that were delivered as the mock moved forward.
+func (m *UnsynchronizedMock) SetReport(t time.Time, opts ...Option) *AdvanceReport {
+	m.applyOptions(opts)
+	report := m.advanceReport(t)
+	m.finishOptions(opts)
+	return report
+}
+
+func (m *UnsynchronizedMock) advanceReport(t time.Time) *AdvanceReport {
+	m.mu.Lock()
+	report := &AdvanceReport{From: m.now, To: t}
+	m.mu.Unlock()
+	m.advance(t, report)
+	return report
+}
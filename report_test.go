@@ -0,0 +1,33 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that the report lists every timer, tick and callback in order.
+func TestMock_AddReport(t *testing.T) {
+	mock := NewMock(t, 3)
+	start := mock.Now()
+	timer := mock.NewTimer(1500 * time.Millisecond)
+	ticker := mock.NewTicker(time.Second)
+	defer ticker.Stop()
+	mock.AfterFunc(2*time.Second, func() {})
+
+	report := mock.AddReport(2 * time.Second)
+	assert.Equal(t, start, report.From)
+	assert.Equal(t, start.Add(2*time.Second), report.To)
+	assert.Equal(t, 1, report.Count(KindTimer))
+	assert.Equal(t, 2, report.Count(KindTicker))
+	assert.Equal(t, 1, report.Count(KindAfterFunc))
+	if assert.Len(t, report.Fired, 4) {
+		assert.Equal(t, ticker.ID(), report.Fired[0].ID)
+		assert.Equal(t, timer.ID(), report.Fired[1].ID)
+		assert.Equal(t, start.Add(1500*time.Millisecond), report.Fired[1].Deadline)
+	}
+
+	report = mock.SetReport(start.Add(2500 * time.Millisecond))
+	assert.Empty(t, report.Fired)
+}
@@ -0,0 +1,166 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffPolicy decides how long Retry waits between attempts.
+type BackoffPolicy interface {
+	// Backoff returns how long to wait after the given number of failed
+	// attempts, counting from 1, or false to stop retrying.
+	Backoff(attempts int) (time.Duration, bool)
+}
+
+// ConstantBackoff waits the same Interval between every attempt. If
+// MaxAttempts is positive, it gives up after that many attempts.
+type ConstantBackoff struct {
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+func (b ConstantBackoff) Backoff(attempts int) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempts >= b.MaxAttempts {
+		return 0, false
+	}
+	return b.Interval, true
+}
+
+// LinearBackoff waits Initial after the first attempt, and Step longer after
+// each further attempt, up to Max if Max is positive. If MaxAttempts is
+// positive, it gives up after that many attempts.
+type LinearBackoff struct {
+	Initial     time.Duration
+	Step        time.Duration
+	Max         time.Duration
+	MaxAttempts int
+}
+
+func (b LinearBackoff) Backoff(attempts int) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempts >= b.MaxAttempts {
+		return 0, false
+	}
+	d := b.Initial + time.Duration(attempts-1)*b.Step
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d, true
+}
+
+// ExponentialBackoff waits Initial after the first attempt, and Multiplier
+// times longer after each further attempt, up to Max if Max is positive. A
+// Multiplier of zero doubles the wait each time. If MaxAttempts is positive,
+// it gives up after that many attempts.
+type ExponentialBackoff struct {
+	Initial     time.Duration
+	Multiplier  float64
+	Max         time.Duration
+	MaxAttempts int
+}
+
+func (b ExponentialBackoff) Backoff(attempts int) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempts >= b.MaxAttempts {
+		return 0, false
+	}
+	m := b.Multiplier
+	if m == 0 {
+		m = 2
+	}
+	f := float64(b.Initial) * math.Pow(m, float64(attempts-1))
+	if b.Max > 0 && f > float64(b.Max) {
+		return b.Max, true
+	}
+	if f > math.MaxInt64 {
+		return time.Duration(math.MaxInt64), true
+	}
+	return time.Duration(f), true
+}
+
+// JitteredBackoff randomizes the waits of another policy by up to Fraction
+// of their length in either direction, so that a Fraction of 0.5 turns a wait
+// of 10s into one between 5s and 15s. Rand is used as the source of
+// randomness if set, which makes the waits reproducible.
+type JitteredBackoff struct {
+	Policy   BackoffPolicy
+	Fraction float64
+	Rand     *rand.Rand
+
+	mu sync.Mutex
+}
+
+func (b *JitteredBackoff) Backoff(attempts int) (time.Duration, bool) {
+	d, ok := b.Policy.Backoff(attempts)
+	if !ok {
+		return 0, false
+	}
+	b.mu.Lock()
+	var r float64
+	if b.Rand != nil {
+		r = b.Rand.Float64()
+	} else {
+		r = rand.Float64()
+	}
+	b.mu.Unlock()
+	return time.Duration(float64(d) * (1 + b.Fraction*(2*r-1))), true
+}
+
+// permanentError marks an error that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry returns it immediately instead of
+// retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// Retry calls fn until it succeeds, waiting between attempts as directed by
+// policy. Waits are measured on the system clock. See RetryWith.
+func Retry(ctx context.Context, policy BackoffPolicy, fn func(ctx context.Context) error) error {
+	return RetryWith(ctx, systemClock, policy, fn)
+}
+
+// RetryWith calls fn until it succeeds, waiting between attempts as directed
+// by policy. Waits are measured on c, so retry logic can be tested by
+// advancing a mock, which will see one timer start per non-zero wait.
+//
+// It returns nil once fn succeeds. Otherwise it returns fn's error once the
+// policy gives up or fn returns an error wrapped by Permanent, or ctx's
+// error if ctx is done first.
+func RetryWith(ctx context.Context, c MockableClock, policy BackoffPolicy, fn func(ctx context.Context) error) error {
+	for attempts := 1; ; attempts++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		d, ok := policy.Backoff(attempts)
+		if !ok {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d <= 0 {
+			continue
+		}
+		if err := c.SleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
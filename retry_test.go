@@ -0,0 +1,123 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffPolicies(t *testing.T) {
+	backoffs := func(p BackoffPolicy) []time.Duration {
+		var ret []time.Duration
+		for attempts := 1; attempts < 10; attempts++ {
+			d, ok := p.Backoff(attempts)
+			if !ok {
+				break
+			}
+			ret = append(ret, d)
+		}
+		return ret
+	}
+
+	assert.Equal(t,
+		[]time.Duration{time.Second, time.Second},
+		backoffs(ConstantBackoff{Interval: time.Second, MaxAttempts: 3}))
+	assert.Equal(t,
+		[]time.Duration{1 * time.Second, 3 * time.Second, 5 * time.Second, 6 * time.Second},
+		backoffs(LinearBackoff{Initial: time.Second, Step: 2 * time.Second, Max: 6 * time.Second, MaxAttempts: 5}))
+	assert.Equal(t,
+		[]time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second},
+		backoffs(ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second, MaxAttempts: 6}))
+	assert.Equal(t,
+		[]time.Duration{1 * time.Second, 3 * time.Second},
+		backoffs(ExponentialBackoff{Initial: time.Second, Multiplier: 3, MaxAttempts: 3}))
+
+	jittered := backoffs(&JitteredBackoff{
+		Policy:   ConstantBackoff{Interval: 10 * time.Second, MaxAttempts: 10},
+		Fraction: 0.5,
+		Rand:     rand.New(rand.NewSource(1)),
+	})
+	assert.Len(t, jittered, 9)
+	for _, d := range jittered {
+		assert.True(t, d >= 5*time.Second && d <= 15*time.Second, "jittered backoff %v", d)
+	}
+	again := backoffs(&JitteredBackoff{
+		Policy:   ConstantBackoff{Interval: 10 * time.Second, MaxAttempts: 10},
+		Fraction: 0.5,
+		Rand:     rand.New(rand.NewSource(1)),
+	})
+	assert.Equal(t, jittered, again, "same seed should give the same backoffs")
+}
+
+// Ensure that retries wait on the mock clock.
+func TestRetryWith(t *testing.T) {
+	clock := NewMock(t, 1)
+	failure := errors.New("failure")
+
+	var attempts int
+	done := make(chan error, 1)
+	go func() {
+		done <- RetryWith(context.Background(), clock, ExponentialBackoff{Initial: time.Second}, func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return failure
+			}
+			return nil
+		})
+	}()
+
+	clock.Add(1*time.Second, ExpectUpcomingStarts(1))
+	clock.Add(1 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("too early")
+	default:
+	}
+	clock.Add(1 * time.Second)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	case <-time.After(1 * time.Second):
+		t.Fatal("too late")
+	}
+}
+
+func TestRetryWith_GiveUp(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	failure := errors.New("failure")
+
+	// The policy gives up.
+	var attempts int
+	err := RetryWith(context.Background(), clock, ConstantBackoff{MaxAttempts: 3}, func(ctx context.Context) error {
+		attempts++
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 3, attempts)
+
+	// The error is permanent.
+	attempts = 0
+	err = RetryWith(context.Background(), clock, ConstantBackoff{}, func(ctx context.Context) error {
+		attempts++
+		return Permanent(failure)
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, attempts)
+
+	// The context is done while waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	err = RetryWith(ctx, clock, ConstantBackoff{Interval: time.Hour}, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return failure
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, attempts)
+}
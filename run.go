@@ -0,0 +1,90 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrVirtualTimeLimit is returned by Run when its function doesn't finish
+// within the virtual time allowed.
+var ErrVirtualTimeLimit = errors.New("virtual time limit reached")
+
+// quietChecks is how many consecutive millisecond checks without any mock
+// activity Run takes as meaning every participant is blocked.
+const quietChecks = 3
+
+// Run starts fn, and advances the mock to the next timer or ticker deadline
+// whenever the mock has been idle, until fn returns. This runs a worker to
+// completion under virtual time without manual calls to Add.
+//
+// If fn hasn't returned once the mock has advanced by limit, the context
+// passed to fn is canceled, and Run returns ErrVirtualTimeLimit after fn
+// returns. A non-positive limit means no limit. If an OnBeforeAdvance hook
+// vetoes an advance, the context is canceled too, and Run returns the hook's
+// error.
+//
+// The mock is idle when a few milliseconds pass without it creating, firing,
+// stopping or resetting any timer. Participants that run for longer than
+// that without using the clock may see it advance before they block. Run
+// should be used with a mock that doesn't fail on unexpected timer starts,
+// such as one from NewUnsynchronizedMock, and nothing else should advance
+// the mock while it runs.
+func (m *UnsynchronizedMock) Run(fn func(ctx context.Context), limit time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(ctx)
+	}()
+
+	start := m.current()
+	for {
+		if m.waitIdle(done) {
+			return nil
+		}
+		next, ok := m.nextDeadline()
+		if !ok {
+			// Nothing is scheduled, so wait for fn to finish, or for a
+			// participant that was busy to use the clock.
+			continue
+		}
+		if limit > 0 && next.Sub(start) > limit {
+			m.Set(start.Add(limit))
+			cancel()
+			<-done
+			return ErrVirtualTimeLimit
+		}
+		if report := m.SetReport(next); report.Vetoed != nil {
+			cancel()
+			<-done
+			return report.Vetoed
+		}
+	}
+}
+
+// waitIdle waits until the mock is idle, and reports whether done was closed
+// first.
+func (m *UnsynchronizedMock) waitIdle(done <-chan struct{}) bool {
+	last := m.eventCount()
+	for quiet := 0; quiet < quietChecks; {
+		select {
+		case <-done:
+			return true
+		case <-time.After(time.Millisecond):
+		}
+		if n := m.eventCount(); n != last {
+			last, quiet = n, 0
+		} else {
+			quiet++
+		}
+	}
+	return false
+}
+
+func (m *UnsynchronizedMock) eventCount() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events
+}
@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Run advances the mock until the worker finishes.
+func TestMock_Run(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	var mu sync.Mutex
+	var order []string
+	log := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, s)
+	}
+
+	err := clock.Run(func(ctx context.Context) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Sleep(90 * time.Second)
+			log("background")
+		}()
+		for i := 0; i < 3; i++ {
+			clock.Sleep(time.Minute)
+			log("worker")
+		}
+		wg.Wait()
+	}, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"worker", "background", "worker", "worker"}, order)
+	assert.Equal(t, time.Unix(180, 0), clock.Now())
+}
+
+// Ensure that Run gives up at its virtual time limit.
+func TestMock_Run_Limit(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	err := clock.Run(func(ctx context.Context) {
+		ticker := clock.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, time.Hour)
+
+	assert.Equal(t, ErrVirtualTimeLimit, err)
+	assert.Equal(t, time.Unix(3600, 0), clock.Now())
+}
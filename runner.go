@@ -0,0 +1,112 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OverlapPolicy decides what a Runner does when its job is due while the
+// previous run is still going.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips runs that are due while the job is running.
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs the job again as soon as the current run finishes.
+	// Any number of runs that come due meanwhile are merged into that one,
+	// so the job never falls more than one run behind.
+	OverlapQueue
+	// OverlapConcurrent starts every run on time, alongside any that are
+	// still going.
+	OverlapConcurrent
+)
+
+// Runner runs a job every Interval on a clock until it is shut down. With a
+// mock clock, periodic jobs can be tested by advancing it; the Runner starts
+// one ticker, when Run is called.
+type Runner struct {
+	Clock    MockableClock
+	Interval time.Duration
+	Overlap  OverlapPolicy
+
+	// OnPanic, if set, is called with the value of any panic in the job.
+	// Panics are recovered either way, so a failing run doesn't stop later
+	// ones.
+	OnPanic func(v interface{})
+
+	// Checkpoint, if set, is marked Done each time the runner has handled a
+	// tick or the end of a run, so that a test can wait for the runner to
+	// act on an advance of a mock before advancing it again.
+	Checkpoint Checkpoint
+}
+
+// NewRunner returns a Runner that runs its job every interval on c, skipping
+// runs while the job is still running.
+func NewRunner(c MockableClock, interval time.Duration) *Runner {
+	return &Runner{Clock: c, Interval: interval}
+}
+
+// Run calls job every Interval, the first time one Interval after Run is
+// called, until ctx is done. The job's context is ctx, so running jobs see
+// the shutdown. Run then waits for them to return, and returns ctx's error.
+func (r *Runner) Run(ctx context.Context, job func(ctx context.Context)) error {
+	ticker := r.Clock.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	// Unless runs are concurrent, there's at most one, so room for one
+	// finish is enough for it never to block.
+	finished := make(chan struct{}, 1)
+	start := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.run(ctx, job)
+			if r.Overlap != OverlapConcurrent {
+				finished <- struct{}{}
+			}
+		}()
+	}
+
+	running, queued := false, false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			switch {
+			case r.Overlap == OverlapConcurrent || !running:
+				running = true
+				start()
+			case r.Overlap == OverlapQueue:
+				queued = true
+			}
+			r.handled()
+		case <-finished:
+			running = false
+			if queued {
+				queued = false
+				running = true
+				start()
+			}
+			r.handled()
+		}
+	}
+}
+
+func (r *Runner) handled() {
+	if r.Checkpoint != nil {
+		r.Checkpoint.Done()
+	}
+}
+
+func (r *Runner) run(ctx context.Context, job func(ctx context.Context)) {
+	defer func() {
+		if v := recover(); v != nil && r.OnPanic != nil {
+			r.OnPanic(v)
+		}
+	}()
+	job(ctx)
+}
@@ -0,0 +1,137 @@
+package clock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startRunner runs r in the background with a job that reports each start on
+// started and blocks until release is closed or a value is sent on it.
+func startRunner(t *testing.T, r *Runner) (started chan int, release chan struct{}, stop func() error) {
+	started = make(chan int, 10)
+	release = make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs int32
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Run(ctx, func(ctx context.Context) {
+			started <- int(atomic.AddInt32(&runs, 1))
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return started, release, func() error {
+		cancel()
+		return <-done
+	}
+}
+
+// advanceRunner advances mock by a second, and waits for the runner to have
+// handled that many more ticks and finished runs.
+func advanceRunner(t *testing.T, mock *UnsynchronizedMock, cp Checkpoint, events int, opts ...Option) {
+	t.Helper()
+	cp.Add(events)
+	mock.Add(time.Second, opts...)
+	WaitCheckpoint(t, cp, 5*time.Second)
+}
+
+// releaseRunner lets the running job return, and waits for the runner to
+// have handled it.
+func releaseRunner(t *testing.T, cp Checkpoint, release chan struct{}) {
+	t.Helper()
+	cp.Add(1)
+	release <- struct{}{}
+	WaitCheckpoint(t, cp, 5*time.Second)
+}
+
+// assertNoStart checks that the runner hasn't started the job. The runner
+// must have handled every event that might have started it.
+func assertNoStart(t *testing.T, started chan int) {
+	t.Helper()
+	select {
+	case n := <-started:
+		t.Fatalf("unexpected run %d", n)
+	default:
+	}
+}
+
+// Ensure that runs due while the job is running are skipped.
+func TestRunner_Skip(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	cp := NewOptionalCheckPoint("handled")
+	r := NewRunner(mock, time.Second)
+	r.Checkpoint = cp
+	started, release, stop := startRunner(t, r)
+
+	mock.ExpectStarts(1)
+	advanceRunner(t, mock, cp, 1, WaitBefore)
+	assert.Equal(t, 1, <-started)
+	advanceRunner(t, mock, cp, 1)
+	advanceRunner(t, mock, cp, 1)
+	assertNoStart(t, started)
+
+	releaseRunner(t, cp, release)
+	assertNoStart(t, started)
+	advanceRunner(t, mock, cp, 1)
+	assert.Equal(t, 2, <-started)
+	assert.Equal(t, context.Canceled, stop())
+}
+
+// Ensure that runs due while the job is running are merged into one that
+// starts when it finishes.
+func TestRunner_Queue(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	cp := NewOptionalCheckPoint("handled")
+	started, release, stop := startRunner(t, &Runner{Clock: mock, Interval: time.Second, Overlap: OverlapQueue, Checkpoint: cp})
+
+	mock.ExpectStarts(1)
+	advanceRunner(t, mock, cp, 1, WaitBefore)
+	assert.Equal(t, 1, <-started)
+	advanceRunner(t, mock, cp, 1)
+	advanceRunner(t, mock, cp, 1)
+	assertNoStart(t, started)
+
+	releaseRunner(t, cp, release)
+	assert.Equal(t, 2, <-started)
+	releaseRunner(t, cp, release)
+	assertNoStart(t, started)
+	assert.Equal(t, context.Canceled, stop())
+}
+
+// Ensure that concurrent runs start on time, and shutdown waits for them.
+func TestRunner_Concurrent(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	started, _, stop := startRunner(t, &Runner{Clock: mock, Interval: time.Second, Overlap: OverlapConcurrent})
+
+	mock.ExpectStarts(1)
+	mock.Add(time.Second, WaitBefore)
+	assert.Equal(t, 1, <-started)
+	mock.Add(time.Second)
+	assert.Equal(t, 2, <-started)
+	assert.Equal(t, context.Canceled, stop())
+	assert.Empty(t, mock.PendingTimers())
+}
+
+// Ensure that a panicking job is reported and doesn't stop later runs.
+func TestRunner_Panic(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	panics := make(chan interface{}, 2)
+	cp := NewOptionalCheckPoint("handled")
+	r := &Runner{Clock: mock, Interval: time.Second, OnPanic: func(v interface{}) { panics <- v }, Checkpoint: cp}
+	ctx, cancel := context.WithCancel(context.Background())
+	go r.Run(ctx, func(ctx context.Context) { panic("boom") })
+	defer cancel()
+
+	// Each advance starts a run, which panics and finishes.
+	mock.ExpectStarts(1)
+	advanceRunner(t, mock, cp, 2, WaitBefore)
+	assert.Equal(t, "boom", <-panics)
+	advanceRunner(t, mock, cp, 2)
+	assert.Equal(t, "boom", <-panics)
+}
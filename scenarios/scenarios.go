@@ -0,0 +1,121 @@
+// Package scenarios provides prebuilt sequences of times for the classic
+// calendar edge cases: daylight saving transitions, leap seconds and year
+// rollovers. Each scenario sets a mock clock to a few times around its edge,
+// in the time zone that has it, so calendar-sensitive code can be tested
+// against them without working out the instants by hand.
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kraney/clock"
+)
+
+// Scenario is a sequence of times for a mock clock to be set to, in order.
+// Times are in the scenario's time zone, and are not necessarily
+// increasing.
+type Scenario struct {
+	Name  string
+	Times []time.Time
+}
+
+// Run sets mock to each of the scenario's times in turn, calling fn, if it is
+// not nil, after each. Timers due on the way fire as with Set.
+func (s Scenario) Run(mock clock.Controllable, fn func(now time.Time)) {
+	for _, t := range s.Times {
+		mock.Set(t)
+		if fn != nil {
+			fn(t)
+		}
+	}
+}
+
+// SpringForward returns the scenario around the first transition in year at
+// which loc's clocks go forward: an hour and a second before, the instant
+// itself, and a second and an hour after.
+func SpringForward(loc *time.Location, year int) (Scenario, error) {
+	return transition(loc, year, "spring forward", func(from, to int) bool { return to > from })
+}
+
+// FallBack returns the scenario around the first transition in year at
+// which loc's clocks go back, at the same offsets as SpringForward. The wall
+// clock reads the hour before the transition twice.
+func FallBack(loc *time.Location, year int) (Scenario, error) {
+	return transition(loc, year, "fall back", func(from, to int) bool { return to < from })
+}
+
+// LeapSecond returns the scenario around a leap second inserted just before
+// midnight, the instant after which, in UTC, is at. Go's times have no
+// 23:59:60, so as on most systems the clock repeats 23:59:59 instead: it runs
+// up to midnight, steps back a second, and runs on.
+func LeapSecond(at time.Time) Scenario {
+	at = at.UTC()
+	return Scenario{
+		Name: fmt.Sprintf("leap second before %s", at.Format(time.RFC3339)),
+		Times: []time.Time{
+			at.Add(-2 * time.Second),
+			at.Add(-time.Second),
+			at.Add(-time.Second / 2),
+			at.Add(-time.Second),
+			at.Add(-time.Second / 2),
+			at,
+			at.Add(time.Second),
+		},
+	}
+}
+
+// YearRollover returns the scenario around the start of year in loc: an hour
+// and a second before midnight, midnight, and a second and an hour after.
+func YearRollover(loc *time.Location, year int) Scenario {
+	at := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return Scenario{
+		Name:  fmt.Sprintf("%d rollover in %v", year, loc),
+		Times: around(at),
+	}
+}
+
+func around(at time.Time) []time.Time {
+	return []time.Time{
+		at.Add(-time.Hour),
+		at.Add(-time.Second),
+		at,
+		at.Add(time.Second),
+		at.Add(time.Hour),
+	}
+}
+
+// transition finds the first change of loc's UTC offset in year that match
+// accepts, and returns the scenario around it.
+func transition(loc *time.Location, year int, name string, match func(from, to int) bool) (Scenario, error) {
+	t := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := t.AddDate(1, 0, 0)
+	_, offset := t.Zone()
+	for t.Before(end) {
+		next := t.Add(time.Hour)
+		if _, o := next.Zone(); o != offset {
+			if match(offset, o) {
+				at := changeBetween(t, next)
+				return Scenario{Name: fmt.Sprintf("%d %s in %v", year, name, loc), Times: around(at)}, nil
+			}
+			offset = o
+		}
+		t = next
+	}
+	return Scenario{}, fmt.Errorf("no %s transition in %v in %d", name, loc, year)
+}
+
+// changeBetween returns the first second after lo at which the UTC offset
+// differs from lo's, which it does by hi.
+func changeBetween(lo, hi time.Time) time.Time {
+	_, offset := lo.Zone()
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if _, o := mid.Zone(); o == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
@@ -0,0 +1,67 @@
+package scenarios
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kraney/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func wallClock(times []time.Time) []string {
+	ret := make([]string, len(times))
+	for i, t := range times {
+		ret[i] = t.Format("2006-01-02 15:04:05 MST")
+	}
+	return ret
+}
+
+// Ensure that the daylight saving scenarios straddle the transitions.
+func TestSpringForward_FallBack(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	s, err := SpringForward(loc, 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"2024-03-10 01:00:00 EST", "2024-03-10 01:59:59 EST", "2024-03-10 03:00:00 EDT",
+		"2024-03-10 03:00:01 EDT", "2024-03-10 04:00:00 EDT",
+	}, wallClock(s.Times))
+
+	s, err = FallBack(loc, 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"2024-11-03 01:00:00 EDT", "2024-11-03 01:59:59 EDT", "2024-11-03 01:00:00 EST",
+		"2024-11-03 01:00:01 EST", "2024-11-03 02:00:00 EST",
+	}, wallClock(s.Times))
+
+	_, err = SpringForward(time.UTC, 2024)
+	assert.EqualError(t, err, "no spring forward transition in UTC in 2024")
+}
+
+// Ensure that the leap second scenario repeats 23:59:59.
+func TestLeapSecond(t *testing.T) {
+	s := LeapSecond(time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC))
+	mock := clock.NewUnsynchronizedMock()
+	var seen []string
+	s.Run(mock, func(now time.Time) {
+		assert.Equal(t, now, mock.Now())
+		seen = append(seen, now.Format("15:04:05.0"))
+	})
+	assert.Equal(t, []string{
+		"23:59:58.0", "23:59:59.0", "23:59:59.5", "23:59:59.0", "23:59:59.5", "00:00:00.0", "00:00:01.0",
+	}, seen)
+}
+
+// Ensure that the year rollover scenario fires timers on the way.
+func TestYearRollover(t *testing.T) {
+	s := YearRollover(time.UTC, 2025)
+	mock := clock.NewUnsynchronizedMock()
+	mock.Set(s.Times[0])
+	timer := mock.NewTimer(time.Hour)
+	s.Run(mock, nil)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), <-timer.C)
+	assert.Equal(t, "2025 rollover in UTC", s.Name)
+}
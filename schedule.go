@@ -0,0 +1,65 @@
+package clock
+
+import "time"
+
+// TimerSpec describes a timer or ticker for Schedule to create.
+type TimerSpec struct {
+	// Kind is KindTimer, KindTicker or KindAfterFunc.
+	Kind TimerKind
+	// Duration is the timer's delay, or the ticker's interval.
+	Duration time.Duration
+	// Func is called when an AfterFunc timer fires.
+	Func func()
+}
+
+// Scheduled is a timer or ticker created by Schedule. Timer is set for
+// timers, including AfterFunc timers, and Ticker for tickers.
+type Scheduled struct {
+	Timer  *Timer
+	Ticker *Ticker
+}
+
+// Schedule creates a timer or ticker for each spec, in order, as one step:
+// the clock can't move while they are being created, so all their deadlines
+// are measured from the same time, and no Add or Set running concurrently
+// sees only some of them. Each counts as a start, and they are logged as a
+// single CheckpointDone event.
+//
+// It panics if a spec has an unknown kind, a ticker has a non-positive
+// interval, or an AfterFunc has no Func, before creating any of them.
+func (m *UnsynchronizedMock) Schedule(specs ...TimerSpec) []Scheduled {
+	for _, spec := range specs {
+		switch {
+		case spec.Kind == KindTicker && spec.Duration <= 0:
+			panic("non-positive interval for NewTicker")
+		case spec.Kind == KindAfterFunc && spec.Func == nil:
+			panic("nil Func for AfterFunc in Schedule")
+		case spec.Kind != KindTimer && spec.Kind != KindTicker && spec.Kind != KindAfterFunc:
+			panic("unknown timer kind " + string(spec.Kind) + " in Schedule")
+		}
+	}
+
+	stack := m.callers()
+	ret := make([]Scheduled, len(specs))
+	events := make([]Event, 0, len(specs)+1)
+	m.mu.Lock()
+	for i, spec := range specs {
+		var e Event
+		switch spec.Kind {
+		case KindTicker:
+			ret[i].Ticker, e = m.newTickerLocked(spec.Duration, CoalesceTicks, 1, false, stack)
+		default:
+			ret[i].Timer, e = m.newTimerLocked(m.now.Add(spec.Duration), false, spec.Func, stack)
+		}
+		events = append(events, e)
+		m.startCheckpoint.Done()
+	}
+	if len(specs) > 0 {
+		events = append(events, Event{Type: CheckpointDone, Time: m.now, Checkpoint: checkpointName(m.startCheckpoint), Delta: -len(specs)})
+	}
+	m.mu.Unlock()
+	for _, e := range events {
+		m.logEvent(e)
+	}
+	return ret
+}
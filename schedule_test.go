@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Schedule creates every kind of timer from the same time and
+// counts them all as starts.
+func TestMock_Schedule(t *testing.T) {
+	mock := NewMock(t, 3)
+
+	called := false
+	s := mock.Schedule(
+		TimerSpec{Kind: KindTimer, Duration: time.Second},
+		TimerSpec{Kind: KindTicker, Duration: time.Second},
+		TimerSpec{Kind: KindAfterFunc, Duration: 2 * time.Second, Func: func() { called = true }},
+	)
+	if !assert.Len(t, s, 3) {
+		return
+	}
+	assert.Nil(t, s[0].Ticker)
+	assert.Nil(t, s[1].Timer)
+	assert.Nil(t, s[2].Timer.C)
+	events := mock.History()
+	assert.Equal(t, CheckpointDone, events[len(events)-1].Type)
+	assert.Equal(t, -3, events[len(events)-1].Delta)
+
+	mock.Add(time.Second)
+	<-s[0].Timer.C
+	<-s[1].Ticker.C
+	assert.False(t, called)
+	mock.Add(time.Second)
+	assert.True(t, called)
+	s[1].Ticker.Stop()
+}
+
+// Ensure that an invalid spec panics before anything is created.
+func TestMock_Schedule_Invalid(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	assert.Panics(t, func() {
+		mock.Schedule(TimerSpec{Kind: KindTimer, Duration: time.Second}, TimerSpec{Kind: KindTicker})
+	})
+	assert.Panics(t, func() { mock.Schedule(TimerSpec{Kind: KindAfterFunc, Duration: time.Second}) })
+	assert.Empty(t, mock.PendingTimers())
+}
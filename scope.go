@@ -0,0 +1,27 @@
+package clock
+
+import "context"
+
+// clockKey is the context key for a clock scoped by NewContext.
+type clockKey struct{}
+
+// NewContext returns a copy of ctx that carries c. Code that reads its clock
+// with FromContext then uses c for everything run with the context, and
+// whatever that passes it on to, so parallel tests can each give their code
+// its own mock.
+//
+// Go has no goroutine-local storage, so the package-level functions, such as
+// Now and After, can't see a clock scoped this way; they use the clock set
+// by SetSystemClock, which is shared by the whole process.
+func NewContext(ctx context.Context, c MockableClock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// FromContext returns the clock carried by ctx, or, if there isn't one, the
+// clock the package-level functions use.
+func FromContext(ctx context.Context) MockableClock {
+	if c, ok := ctx.Value(clockKey{}).(MockableClock); ok {
+		return c
+	}
+	return systemClock
+}
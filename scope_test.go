@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that parallel users of scoped clocks each see their own mock.
+func TestFromContext(t *testing.T) {
+	for i := 1; i <= 3; i++ {
+		i := i
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			mock := NewUnsynchronizedMock()
+			mock.Set(time.Unix(int64(i), 0))
+			ctx, cancel := context.WithCancel(NewContext(context.Background(), mock))
+			defer cancel()
+			assert.Equal(t, time.Unix(int64(i), 0), FromContext(ctx).Now())
+		})
+	}
+}
+
+// Ensure that a context without a clock gets the package-level one.
+func TestFromContext_Default(t *testing.T) {
+	assert.Equal(t, systemClock, FromContext(context.Background()))
+}
@@ -0,0 +1,104 @@
+package clock
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Script is a declarative timeline of steps to run against a mock, so that
+// time scenarios can be defined as data and shared between tests. Scripts
+// are loaded from YAML or JSON by LoadScript:
+//
+//	start: 2024-01-01T00:00:00Z
+//	steps:
+//	  - advance: 1m
+//	    starts: 1
+//	    checkpoints: {flushed: 1}
+//	  - set: 2024-01-02T00:00:00Z
+type Script struct {
+	Start *time.Time `yaml:"start"` // time to set before the first step, if any
+	Steps []Step     `yaml:"steps"`
+}
+
+// Step moves a mock in a Script, by setting it or advancing it.
+type Step struct {
+	Name    string        `yaml:"name"`    // describes the step in errors
+	Set     *time.Time    `yaml:"set"`     // time to set the mock to
+	Advance time.Duration `yaml:"advance"` // duration to advance the mock by
+
+	// Starts is the number of timers expected to start after the step,
+	// before the next step may run.
+	Starts int `yaml:"starts"`
+
+	// Checkpoints maps names of checkpoints to the number of times each is
+	// expected to be done as a result of the step. The step waits for them
+	// before it completes.
+	Checkpoints map[CheckpointName]int `yaml:"checkpoints"`
+}
+
+// LoadScript reads a Script from YAML, or from JSON, which is a subset of
+// YAML. Durations are written as accepted by time.ParseDuration, and times in
+// RFC 3339 format.
+func LoadScript(r io.Reader) (*Script, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var s Script
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("script: %w", err)
+	}
+	for i, step := range s.Steps {
+		if (step.Set == nil) == (step.Advance == 0) {
+			return nil, fmt.Errorf("script: %s: expected exactly one of set and advance", step.describe(i))
+		}
+		if step.Advance < 0 {
+			return nil, fmt.Errorf("script: %s: negative advance", step.describe(i))
+		}
+	}
+	return &s, nil
+}
+
+// Run executes the script against mock. Checkpoints named by the steps are
+// looked up in checkpoints, and must be the ones the code under test marks as
+// done. It returns an error, without running any steps, if a checkpoint is
+// missing.
+func (s *Script) Run(mock Controllable, checkpoints map[CheckpointName]Checkpoint) error {
+	for i, step := range s.Steps {
+		for name := range step.Checkpoints {
+			if checkpoints[name] == nil {
+				return fmt.Errorf("script: %s: unknown checkpoint %q", step.describe(i), name)
+			}
+		}
+	}
+
+	if s.Start != nil {
+		mock.Set(*s.Start)
+	}
+	for _, step := range s.Steps {
+		for name, n := range step.Checkpoints {
+			checkpoints[name].Add(n)
+		}
+		var opts []Option
+		if step.Starts > 0 {
+			opts = append(opts, ExpectUpcomingStarts(step.Starts))
+		}
+		if step.Set != nil {
+			mock.Set(*step.Set, opts...)
+		} else {
+			mock.Add(step.Advance, opts...)
+		}
+		for name := range step.Checkpoints {
+			checkpoints[name].Wait()
+		}
+	}
+	return nil
+}
+
+func (step Step) describe(i int) string {
+	if step.Name != "" {
+		return fmt.Sprintf("step %d (%s)", i+1, step.Name)
+	}
+	return fmt.Sprintf("step %d", i+1)
+}
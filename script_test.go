@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a YAML script drives the mock and waits for its checkpoints.
+func TestScript_Run(t *testing.T) {
+	script, err := LoadScript(strings.NewReader(`
+start: 1970-01-01T00:00:00Z
+steps:
+  - name: first tick
+    advance: 10s
+    starts: 1
+    checkpoints: {ticked: 1}
+  - advance: 10s
+    checkpoints: {ticked: 1}
+  - set: 1970-01-01T00:00:25Z
+`))
+	assert.NoError(t, err)
+
+	clock := NewMock(t, 1)
+	ticked := NewFailOnUnexpectedCheckpoint("ticked", t)
+	ticker := clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	go func() {
+		first := true
+		for range ticker.C {
+			ticked.Done()
+			// The first step expects this timer to start.
+			if first {
+				clock.After(time.Hour)
+				first = false
+			}
+		}
+	}()
+
+	err = script.Run(clock, map[CheckpointName]Checkpoint{"ticked": ticked})
+	assert.NoError(t, err)
+	assert.True(t, time.Unix(25, 0).Equal(clock.Now()))
+}
+
+// Ensure that JSON scripts load, and invalid scripts are rejected.
+func TestLoadScript(t *testing.T) {
+	script, err := LoadScript(strings.NewReader(`{"steps": [{"advance": "1m30s"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, script.Steps[0].Advance)
+
+	_, err = LoadScript(strings.NewReader(`{"steps": [{"name": "both", "advance": "1s", "set": "2020-01-01T00:00:00Z"}]}`))
+	assert.EqualError(t, err, "script: step 1 (both): expected exactly one of set and advance")
+	_, err = LoadScript(strings.NewReader(`{"steps": [{"wait": "1s"}]}`))
+	assert.Error(t, err)
+
+	err = script.Run(NewUnsynchronizedMock(), nil)
+	assert.NoError(t, err)
+	script.Steps[0].Checkpoints = map[CheckpointName]int{"missing": 1}
+	err = script.Run(NewUnsynchronizedMock(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package clock
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Simulation advances several mock clocks together, each with its own offset
+// from the simulation's reference time and its own drift rate, so that code
+// for distributed systems, such as leases and heartbeats, can be tested under
+// clock skew deterministically.
+//
+// Timers fire in the order of the reference time at which they are due,
+// across all the clocks.
+type Simulation struct {
+	mu     sync.Mutex
+	start  time.Time
+	now    time.Time
+	clocks []*simClock
+}
+
+type simClock struct {
+	mock   *UnsynchronizedMock
+	offset time.Duration
+	rate   float64 // local seconds per reference second
+}
+
+// NewSimulation returns a Simulation whose reference time starts at start.
+func NewSimulation(start time.Time) *Simulation {
+	return &Simulation{start: start, now: start}
+}
+
+// NewClock adds a mock clock to the simulation. Its time is offset from the
+// reference time by offset, and it runs fast by drift, so that a drift of
+// 0.001 gains a millisecond every second and -0.001 loses one. Options are
+// applied to the new mock as by NewUnsynchronizedMock.
+func (s *Simulation) NewClock(offset time.Duration, drift float64, opts ...Option) *UnsynchronizedMock {
+	if drift <= -1 {
+		panic("clock drift must be greater than -1")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &simClock{mock: NewUnsynchronizedMock(opts...), offset: offset, rate: 1 + drift}
+	c.mock.Set(c.local(s.start, s.now))
+	s.clocks = append(s.clocks, c)
+	return c.mock
+}
+
+// Now returns the simulation's reference time.
+func (s *Simulation) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Add moves the reference time forward by d, and every clock forward by its
+// own equivalent. Timers due on any of the clocks fire in reference time
+// order, with all the clocks moved to the time each one fires. This should
+// only be called from a single goroutine at a time.
+func (s *Simulation) Add(d time.Duration) {
+	s.mu.Lock()
+	target := s.now.Add(d)
+	s.mu.Unlock()
+	for {
+		s.mu.Lock()
+		clocks, now := s.clocks, s.now
+		s.mu.Unlock()
+
+		var owner *simClock
+		var deadline, next time.Time
+		for _, c := range clocks {
+			local, ok := c.mock.nextDeadline()
+			if !ok {
+				continue
+			}
+			at := c.reference(s.start, local)
+			if !at.After(target) && (owner == nil || at.Before(next)) {
+				owner, deadline, next = c, local, at
+			}
+		}
+		if owner == nil {
+			break
+		}
+		if next.Before(now) {
+			next = now
+		}
+		s.moveTo(clocks, next, owner, deadline)
+	}
+	s.mu.Lock()
+	clocks := s.clocks
+	s.mu.Unlock()
+	s.moveTo(clocks, target, nil, time.Time{})
+}
+
+// moveTo sets the reference time to t, and moves each clock to match. The
+// owner of a timer that is due at t is moved to at least the timer's
+// deadline, since rounding to nanoseconds can leave it just short. The lock
+// is not held while the clocks move, so that timer callbacks may use the
+// simulation.
+func (s *Simulation) moveTo(clocks []*simClock, t time.Time, owner *simClock, deadline time.Time) {
+	s.mu.Lock()
+	s.now = t
+	s.mu.Unlock()
+	for _, c := range clocks {
+		local := c.local(s.start, t)
+		if c == owner && deadline.After(local) {
+			local = deadline
+		}
+		if local.After(c.mock.current()) {
+			c.mock.Set(local)
+		}
+	}
+}
+
+// local returns the clock's time at reference time t.
+func (c *simClock) local(start, t time.Time) time.Time {
+	elapsed := float64(t.Sub(start)) * c.rate
+	return start.Add(c.offset + time.Duration(math.Round(elapsed)))
+}
+
+// reference returns the earliest reference time at which the clock reads at
+// least local.
+func (c *simClock) reference(start, local time.Time) time.Time {
+	elapsed := float64(local.Sub(start)-c.offset) / c.rate
+	return start.Add(time.Duration(math.Ceil(elapsed)))
+}
+
+// nextDeadline returns when the next timer or ticker on the mock is due, if
+// there is one.
+func (m *UnsynchronizedMock) nextDeadline() (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.timers.peek()
+	if t == nil {
+		return time.Time{}, false
+	}
+	return t.Next(), true
+}
@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that timers on skewed clocks fire in reference time order.
+func TestSimulation_Add(t *testing.T) {
+	start := time.Unix(1000, 0)
+	sim := NewSimulation(start)
+	fast := sim.NewClock(0, 0.5)
+	ahead := sim.NewClock(10*time.Second, 0)
+	assert.Equal(t, start.Add(10*time.Second), ahead.Now())
+
+	var fired []string
+	var at []time.Time
+	fast.AfterFunc(15*time.Second, func() {
+		fired = append(fired, "fast")
+		at = append(at, sim.Now())
+	})
+	ahead.AfterFunc(5*time.Second, func() {
+		fired = append(fired, "ahead")
+		at = append(at, sim.Now())
+	})
+
+	sim.Add(20 * time.Second)
+	assert.Equal(t, []string{"ahead", "fast"}, fired)
+	assert.Equal(t, []time.Time{start.Add(5 * time.Second), start.Add(10 * time.Second)}, at)
+	assert.Equal(t, start.Add(20*time.Second), sim.Now())
+	assert.Equal(t, start.Add(30*time.Second), fast.Now())
+	assert.Equal(t, start.Add(30*time.Second), ahead.Now())
+}
+
+// Ensure that a slow clock's ticker ticks less often in reference time.
+func TestSimulation_Drift(t *testing.T) {
+	sim := NewSimulation(time.Unix(0, 0))
+	slow := sim.NewClock(0, -0.01)
+	ticker := slow.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var ticks int
+	for i := 0; i < 100; i++ {
+		sim.Add(time.Second)
+		select {
+		case <-ticker.C:
+			ticks++
+		default:
+		}
+	}
+	assert.Equal(t, 99, ticks)
+	assert.Equal(t, time.Unix(99, 0), slow.Now())
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package clock
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is an slog.Handler that stamps records with the time from a
+// clock instead of time.Now, so that logs written under a mock show virtual
+// time and can be compared against golden output.
+type SlogHandler struct {
+	clock   NowClock
+	handler slog.Handler
+}
+
+// NewSlogHandler returns a handler that sets the time of each record to
+// c.Now() before passing it to h. Records with a zero time, which handlers
+// treat as having no time, are passed on unchanged.
+func NewSlogHandler(c NowClock, h slog.Handler) *SlogHandler {
+	return &SlogHandler{clock: c, handler: h}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !r.Time.IsZero() {
+		r.Time = h.clock.Now()
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SlogHandler{clock: h.clock, handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{clock: h.clock, handler: h.handler.WithGroup(name)}
+}
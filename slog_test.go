@@ -0,0 +1,30 @@
+//go:build go1.21
+
+package clock
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that log records carry the mock's time, including through
+// WithAttrs and WithGroup.
+func TestSlogHandler(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(mock, slog.NewTextHandler(&buf, nil)))
+
+	logger.With("a", 1).WithGroup("g").Info("hello", "b", 2)
+	assert.Equal(t, "time=2024-01-02T03:04:05.000Z level=INFO msg=hello a=1 g.b=2\n", buf.String())
+
+	buf.Reset()
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "untimed", 0)
+	assert.NoError(t, logger.Handler().Handle(context.Background(), r))
+	assert.Equal(t, "level=INFO msg=untimed\n", buf.String())
+}
@@ -0,0 +1,78 @@
+package clock
+
+import "time"
+
+// Snapshot is the state of a mock at one point, captured by Snapshot so that
+// it can be restored later.
+type Snapshot struct {
+	now        time.Time
+	timers     []timerState
+	starts     int
+	afterFuncs int
+}
+
+// timerState is the part of a timer or ticker that changes as it runs.
+type timerState struct {
+	timer clockTimer
+	next  time.Time
+	d     time.Duration
+}
+
+// Snapshot captures the mock's current time, its pending timers and tickers,
+// and the counts of its checkpoints, so that tests can branch from a common
+// prepared state by calling Restore. It should not be called while the clock
+// is being advanced or waited on.
+func (m *UnsynchronizedMock) Snapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &Snapshot{now: m.now, afterFuncs: m.afterFuncCheckpoint.Outstanding(), starts: m.startCheckpoint.Outstanding()}
+	for _, t := range m.timers.heap {
+		state := timerState{timer: t, next: t.Next()}
+		if ticker, ok := t.(*internalTicker); ok {
+			state.d = ticker.d
+		}
+		s.timers = append(s.timers, state)
+	}
+	return s
+}
+
+// Restore returns the mock to the state captured by s. Timers and tickers
+// that were pending are rescheduled as they were, even if they have since
+// fired or been stopped, and those created since are stopped. Values already
+// sent on timer and ticker channels are not taken back, and ticks queued for
+// slow consumers are dropped.
+func (m *UnsynchronizedMock) Restore(s *Snapshot) {
+	m.mu.Lock()
+	for _, t := range m.timers.heap {
+		*t.position() = 0
+		switch t := t.(type) {
+		case *internalTimer:
+			t.stopped = true
+		case *internalTicker:
+			t.dropBacklog()
+		}
+	}
+	m.timers = timerQueue{}
+	for _, state := range s.timers {
+		switch t := state.timer.(type) {
+		case *internalTimer:
+			t.next = state.next
+			t.stopped = false
+		case *internalTicker:
+			t.next = state.next
+			t.d = state.d
+		case *registeredEvent:
+			t.next = state.next
+			t.canceled = false
+		}
+		m.timers.add(state.timer)
+	}
+	m.now = s.now
+	m.publishNowLocked()
+
+	m.afterFuncCheckpoint.Add(s.afterFuncs - m.afterFuncCheckpoint.Outstanding())
+	m.startCheckpoint.Add(s.starts - m.startCheckpoint.Outstanding())
+	e := Event{Type: ClockRestored, Time: m.now}
+	m.mu.Unlock()
+	m.logEvent(e)
+}
@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that Restore returns the time and timers to their captured state.
+func TestMock_SnapshotRestore(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	timer := clock.NewTimer(10 * time.Second)
+	ticker := clock.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	clock.Add(time.Second)
+
+	snap := clock.Snapshot()
+
+	// The first branch fires the timer, and starts another.
+	clock.Add(10 * time.Second)
+	<-timer.C
+	later := clock.NewTimer(time.Second)
+	ticker.Reset(time.Minute)
+
+	clock.Restore(snap)
+	assert.Equal(t, time.Unix(1, 0), clock.Now())
+	pending := clock.PendingTimers()
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, time.Unix(3, 0), pending[0].Deadline)
+		assert.Equal(t, 3*time.Second, pending[0].Interval)
+		assert.Equal(t, time.Unix(10, 0), pending[1].Deadline)
+	}
+	assert.False(t, later.Stop(), "timer created after the snapshot should be stopped")
+
+	// The second branch sees the timer fire again at the same time.
+	clock.Add(9 * time.Second)
+	select {
+	case v := <-timer.C:
+		assert.Equal(t, time.Unix(10, 0), v)
+	default:
+		t.Fatal("restored timer did not fire")
+	}
+	assert.Equal(t, ClockRestored, findEvent(clock.History(), ClockRestored).Type)
+}
+
+// Ensure that timers dropped by Restore can be rescheduled alongside the
+// restored ones.
+func TestMock_SnapshotRestore_Reset(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	first := clock.NewTimer(5 * time.Second)
+	snap := clock.Snapshot()
+	second := clock.NewTimer(time.Second)
+	third := clock.NewTimer(2 * time.Second)
+
+	clock.Restore(snap)
+	second.Reset(7 * time.Second)
+	third.Reset(3 * time.Second)
+	var deadlines []time.Time
+	for _, p := range clock.PendingTimers() {
+		deadlines = append(deadlines, p.Deadline)
+	}
+	assert.Equal(t, []time.Time{time.Unix(3, 0), time.Unix(5, 0), time.Unix(7, 0)}, deadlines)
+
+	assert.True(t, third.Stop())
+	clock.Add(10 * time.Second)
+	<-first.C
+	<-second.C
+	assert.Empty(t, clock.PendingTimers())
+}
+
+// Ensure that Restore returns checkpoint counts to their captured state.
+func TestMock_SnapshotRestore_Checkpoints(t *testing.T) {
+	clock := NewMock(t, 1)
+	snap := clock.Snapshot()
+
+	clock.NewTimer(time.Second)
+	clock.ExpectStarts(2)
+	clock.Restore(snap)
+
+	// The restored mock still expects the one start.
+	clock.NewTimer(time.Second)
+	clock.Wait()
+}
+
+func findEvent(events []Event, typ EventType) Event {
+	for _, e := range events {
+		if e.Type == typ {
+			return e
+		}
+	}
+	return Event{}
+}
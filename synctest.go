@@ -0,0 +1,28 @@
+//go:build go1.25
+
+package clock
+
+import (
+	"testing"
+	"testing/synctest"
+)
+
+// SyncTest runs f in a testing/synctest bubble, passing it a realtime clock.
+// Within the bubble the time package uses the bubble's fake clock, so the
+// realtime clock, and code written against MockableClock that is given it,
+// runs in virtual time without changes. The system clock is also set to the
+// realtime clock while f runs, so that package-level functions like After and
+// Sleep use the bubble's time even if an earlier test installed a mock.
+//
+// Because the system clock is shared, tests that use SyncTest must not run in
+// parallel with tests that call SetSystemClock.
+func SyncTest(t *testing.T, f func(t *testing.T, c MockableClock)) {
+	t.Helper()
+	prev := systemClock
+	c := New()
+	SetSystemClock(c)
+	defer SetSystemClock(prev)
+	synctest.Test(t, func(t *testing.T) {
+		f(t, c)
+	})
+}
@@ -0,0 +1,49 @@
+//go:build go1.25
+
+package clock
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// Ensure that clock-injected code runs on the bubble's fake time.
+func TestSyncTest(t *testing.T) {
+	start := time.Now()
+	SyncTest(t, func(t *testing.T, c MockableClock) {
+		begin := c.Now()
+
+		done := make(chan struct{})
+		go func() {
+			Sleep(time.Hour)
+			close(done)
+		}()
+		<-After(30 * time.Minute)
+		if since := c.Since(begin); since != 30*time.Minute {
+			t.Fatalf("expected 30m, got %v", since)
+		}
+		<-done
+		if since := Since(begin); since != time.Hour {
+			t.Fatalf("expected 1h, got %v", since)
+		}
+
+		var attempts int
+		err := RetryWith(context.Background(), c, ConstantBackoff{Interval: time.Minute, MaxAttempts: 3}, func(ctx context.Context) error {
+			attempts++
+			return nil
+		})
+		if err != nil || attempts != 1 {
+			t.Fatalf("unexpected retry result: %v after %d attempts", err, attempts)
+		}
+
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+		<-ticker.C
+		synctest.Wait()
+	})
+	if time.Since(start) > time.Second {
+		t.Fatal("bubble used real time")
+	}
+}
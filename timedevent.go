@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimedEvent is something a test schedules on a mock's event loop directly,
+// such as a simulated network packet arriving, rather than faking it with
+// AfterFunc. The mock fires it in order with its timers, ties included, as
+// it advances.
+type TimedEvent interface {
+	// Next returns the time the event next happens, or the zero time if it
+	// doesn't. The mock reads it when the event is registered and after
+	// each time it fires.
+	Next() time.Time
+	// Fire is called when the mock reaches Next, with the mock's time, on
+	// the goroutine advancing it.
+	Fire(now time.Time)
+}
+
+// registeredEvent adapts a TimedEvent to the mock's timer queue. It keeps
+// its own copy of the event's Next, so that the queue's order only changes
+// under the mock's lock.
+type registeredEvent struct {
+	e        TimedEvent
+	mock     *UnsynchronizedMock
+	id       uint64
+	next     time.Time
+	pos      int
+	stack    []uintptr
+	canceled bool
+}
+
+// RegisterEvent schedules e on the mock, to fire each time the clock
+// reaches its Next, until Next returns the zero time or the event is
+// canceled with the returned function, which reports whether e was still
+// scheduled. To move e other than by firing it, cancel it and register it
+// again. A Next that is not after the current time fires at the next
+// advance. Registering an event is not a timer start, so it doesn't count
+// towards ExpectStarts. If e implements fmt.Stringer, PendingTimers names
+// it by its String.
+func (m *UnsynchronizedMock) RegisterEvent(e TimedEvent) (cancel func() bool) {
+	next := e.Next()
+	r := &registeredEvent{e: e, mock: m, stack: m.callers()}
+	m.mu.Lock()
+	m.nextID++
+	r.id = m.nextID
+	if !next.IsZero() {
+		r.scheduleLocked(next)
+	}
+	ev := Event{Type: TimerCreated, Time: m.now, TimerID: r.id, Deadline: r.next, Duration: r.next.Sub(m.now)}
+	m.mu.Unlock()
+	m.logEvent(ev)
+	return r.cancel
+}
+
+// scheduleLocked queues the event for next, or for the current time if next
+// has passed. It must be called with the mock's mu held.
+func (r *registeredEvent) scheduleLocked(next time.Time) {
+	if next.Before(r.mock.now) {
+		next = r.mock.now
+	}
+	r.next = next
+	r.mock.timers.add(r)
+}
+
+func (r *registeredEvent) cancel() bool {
+	r.mock.mu.Lock()
+	queued := r.pos != 0
+	r.mock.removeClockTimer(r)
+	r.canceled = true
+	e := Event{Type: TimerStopped, Time: r.mock.now, TimerID: r.id, Deadline: r.next}
+	r.mock.mu.Unlock()
+	r.mock.logEvent(e)
+	return queued
+}
+
+func (r *registeredEvent) Next() time.Time { return r.next }
+func (r *registeredEvent) seq() uint64     { return r.id }
+func (r *registeredEvent) position() *int  { return &r.pos }
+
+func (r *registeredEvent) info() TimerInfo {
+	var name string
+	if s, ok := r.e.(fmt.Stringer); ok {
+		name = s.String()
+	}
+	return TimerInfo{ID: r.id, Name: name, Kind: KindEvent, Deadline: r.next, Stack: formatStack(r.stack)}
+}
+
+func (r *registeredEvent) Tick(now time.Time) {
+	r.mock.mu.Lock()
+	r.mock.removeClockTimer(r)
+	deadline := r.next
+	r.mock.mu.Unlock()
+
+	r.e.Fire(now)
+	r.mock.logEvent(Event{Type: TimerFired, Time: now, TimerID: r.id, Deadline: deadline})
+
+	next := r.e.Next()
+	r.mock.mu.Lock()
+	if !r.canceled && !next.IsZero() {
+		r.scheduleLocked(next)
+	}
+	r.mock.mu.Unlock()
+	gosched()
+}
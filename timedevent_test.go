@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packets is a TimedEvent delivering simulated packets at given times.
+type packets struct {
+	arrivals []time.Time
+	got      []time.Time
+	log      *[]string
+}
+
+func (p *packets) Next() time.Time {
+	if len(p.arrivals) == 0 {
+		return time.Time{}
+	}
+	return p.arrivals[0]
+}
+
+func (p *packets) Fire(now time.Time) {
+	p.arrivals = p.arrivals[1:]
+	p.got = append(p.got, now)
+	*p.log = append(*p.log, "packet")
+}
+
+func (p *packets) String() string { return "packets" }
+
+func TestMock_RegisterEvent(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	var log []string
+	p := &packets{arrivals: []time.Time{start.Add(time.Second), start.Add(3 * time.Second)}, log: &log}
+	mock.RegisterEvent(p)
+	mock.AfterFunc(time.Second, func() { log = append(log, "timer") })
+	mock.AfterFunc(2*time.Second, func() { log = append(log, "timer") })
+
+	pending := mock.PendingTimers()
+	assert.Equal(t, KindEvent, pending[0].Kind)
+	assert.Equal(t, "packets", pending[0].Name)
+
+	mock.Add(time.Minute)
+	assert.Equal(t, []time.Time{start.Add(time.Second), start.Add(3 * time.Second)}, p.got)
+	assert.Equal(t, []string{"packet", "timer", "timer", "packet"}, log)
+	assert.Empty(t, mock.PendingTimers())
+}
+
+func TestMock_RegisterEvent_Cancel(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	start := mock.Now()
+	var log []string
+	p := &packets{arrivals: []time.Time{start.Add(-time.Second), start.Add(time.Second)}, log: &log}
+	cancel := mock.RegisterEvent(p)
+
+	// A time in the past fires at the next advance, without going back.
+	mock.Add(0)
+	assert.Equal(t, []time.Time{start}, p.got)
+	assert.True(t, cancel())
+	assert.False(t, cancel())
+	mock.Add(time.Minute)
+	assert.Equal(t, []time.Time{start}, p.got)
+}
@@ -1,33 +1,179 @@
 package clock
 
-import "time"
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
 
 // clockTimer represents an object with an associated start time.
 type clockTimer interface {
 	Next() time.Time
 	Tick(time.Time)
+	seq() uint64
+	info() TimerInfo
+	// position points to the timer's position in the mock's timerQueue,
+	// plus one, or 0 if it is not queued.
+	position() *int
 }
 
-// clockTimers represents a list of sortable timers.
+// clockTimers represents a list of sortable timers. Timers are ordered by
+// their next tick time, and timers with identical tick times are ordered by
+// creation, so that they fire first-in first-out.
 type clockTimers []clockTimer
 
-func (a clockTimers) Len() int           { return len(a) }
-func (a clockTimers) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a clockTimers) Less(i, j int) bool { return a[i].Next().Before(a[j].Next()) }
+func (a clockTimers) Len() int      { return len(a) }
+func (a clockTimers) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a clockTimers) Less(i, j int) bool {
+	if a[i].Next().Equal(a[j].Next()) {
+		return a[i].seq() < a[j].seq()
+	}
+	return a[i].Next().Before(a[j].Next())
+}
+
+// timerQueue holds the mock's pending timers and tickers as a min-heap in the
+// order of clockTimers. Each timer keeps its own position in the heap, so
+// that the next to fire can be found, and any of them removed or
+// rescheduled, without sorting or searching them all. Whoever changes the
+// Next of a queued timer must call fix.
+type timerQueue struct {
+	heap clockTimers
+}
+
+func (q *timerQueue) Len() int           { return len(q.heap) }
+func (q *timerQueue) Less(i, j int) bool { return q.heap.Less(i, j) }
+func (q *timerQueue) Swap(i, j int) {
+	q.heap.Swap(i, j)
+	*q.heap[i].position() = i + 1
+	*q.heap[j].position() = j + 1
+}
+
+func (q *timerQueue) Push(x interface{}) {
+	t := x.(clockTimer)
+	q.heap = append(q.heap, t)
+	*t.position() = len(q.heap)
+}
+
+func (q *timerQueue) Pop() interface{} {
+	n := len(q.heap) - 1
+	t := q.heap[n]
+	q.heap[n] = nil
+	q.heap = q.heap[:n]
+	*t.position() = 0
+	return t
+}
+
+// find returns the position of t in the heap, and whether it is queued.
+func (q *timerQueue) find(t clockTimer) (int, bool) {
+	pos := *t.position()
+	return pos - 1, pos > 0
+}
+
+// add queues t, or moves it to its new place if it is already queued.
+func (q *timerQueue) add(t clockTimer) {
+	if i, ok := q.find(t); ok {
+		heap.Fix(q, i)
+		return
+	}
+	heap.Push(q, t)
+}
+
+// remove dequeues t, if it is queued.
+func (q *timerQueue) remove(t clockTimer) {
+	if i, ok := q.find(t); ok {
+		heap.Remove(q, i)
+	}
+}
+
+// fix moves t to its new place after its Next has changed, if it is queued.
+func (q *timerQueue) fix(t clockTimer) {
+	if i, ok := q.find(t); ok {
+		heap.Fix(q, i)
+	}
+}
+
+// peek returns the next timer to fire, or nil if there are none.
+func (q *timerQueue) peek() clockTimer {
+	if len(q.heap) == 0 {
+		return nil
+	}
+	return q.heap[0]
+}
+
+// tied returns the timers due at the same time as the next one, in the
+// order they were created.
+func (q *timerQueue) tied() clockTimers {
+	var ret clockTimers
+	var walk func(i int)
+	walk = func(i int) {
+		if i >= len(q.heap) || !q.heap[i].Next().Equal(q.heap[0].Next()) {
+			return
+		}
+		ret = append(ret, q.heap[i])
+		walk(2*i + 1)
+		walk(2*i + 2)
+	}
+	walk(0)
+	sort.Sort(ret)
+	return ret
+}
+
+// sorted returns the queued timers in the order they will fire.
+func (q *timerQueue) sorted() clockTimers {
+	ret := make(clockTimers, len(q.heap))
+	copy(ret, q.heap)
+	sort.Sort(ret)
+	return ret
+}
+
+// TimerBackend is the part of a timer that a Timer delegates to when it
+// isn't driven by a mock. *time.Timer implements it. A backend may also
+// have a Reschedule(time.Time) bool method, which Timer.Reschedule calls.
+type TimerBackend interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// TickerBackend is the part of a ticker that a Ticker delegates to when it
+// isn't driven by a mock. *time.Ticker implements it.
+type TickerBackend interface {
+	Stop()
+	Reset(d time.Duration)
+}
+
+// WrapTimer returns a Timer that receives on c and delegates Stop and Reset
+// to b. It lets other clock implementations satisfy MockableClock. c may be
+// nil for timers created by AfterFunc.
+func WrapTimer(c <-chan time.Time, b TimerBackend) *Timer {
+	return &Timer{C: c, timer: b}
+}
+
+// WrapTicker returns a Ticker that receives on c and delegates Stop and Reset
+// to b. It lets other clock implementations satisfy MockableClock.
+func WrapTicker(c <-chan time.Time, b TickerBackend) *Ticker {
+	return &Ticker{C: c, ticker: b}
+}
 
 // Timer represents a single event.
 // The current time will be sent on C, unless the timer was created by AfterFunc.
 type Timer struct {
 	C       <-chan time.Time
 	c       chan time.Time
-	timer   *time.Timer         // realtime impl, if set
+	timer   TimerBackend        // realtime or wrapped impl, if set
+	id      uint64              // mock-assigned identifier
 	next    time.Time           // next tick time
 	mock    *UnsynchronizedMock // mock clock, if set
 	fn      func()              // AfterFunc function, if set
 	stopped bool                // True if stopped, false if running
+	stack   []uintptr           // call stack that created the timer
+	name    string              // name given with TimerName, if any
+	confirm Checkpoint          // waited on after each fire, if set
+	pos     int                 // position in the mock's timerQueue, plus one
 }
 
-// Stop turns off the ticker.
+// Stop prevents the timer from firing. It returns true if the call stops the
+// timer, or false if the timer has already expired or been stopped. On a
+// mock, a fire that has not been received from C is discarded.
 func (t *Timer) Stop() bool {
 	if t.timer != nil {
 		return t.timer.Stop()
@@ -37,11 +183,25 @@ func (t *Timer) Stop() bool {
 	registered := !t.stopped
 	t.mock.removeClockTimer((*internalTimer)(t))
 	t.stopped = true
+	t.drainLocked()
+	e := Event{Type: TimerStopped, Time: t.mock.now, TimerID: t.id, Deadline: t.next}
 	t.mock.mu.Unlock()
+	t.mock.logEvent(e)
 	return registered
 }
 
-// Reset changes the expiry time of the timer
+// Chan returns C, so that code holding the timer as a MockableTimer can
+// receive from it.
+func (t *Timer) Chan() <-chan time.Time { return t.C }
+
+// ID returns the identifier the mock assigned to the timer when it was
+// created. It matches the TimerID of the timer's Events, and is zero for
+// timers created by the realtime clock.
+func (t *Timer) ID() uint64 { return t.id }
+
+// Reset changes the timer to expire after duration d. It returns true if the
+// timer had been active, or false if the timer had expired or been stopped.
+// On a mock, a fire that has not been received from C is discarded.
 func (t *Timer) Reset(d time.Duration) bool {
 	if t.timer != nil {
 		return t.timer.Reset(d)
@@ -49,25 +209,91 @@ func (t *Timer) Reset(d time.Duration) bool {
 
 	t.mock.mu.Lock()
 	t.next = t.mock.now.Add(d)
-	defer t.mock.mu.Unlock()
+	t.drainLocked()
 
 	registered := !t.stopped
-	if t.stopped {
-		t.mock.timers = append(t.mock.timers, (*internalTimer)(t))
+	t.mock.timers.add((*internalTimer)(t))
+
+	t.stopped = false
+	e := Event{Type: TimerReset, Time: t.mock.now, TimerID: t.id, Deadline: t.next, Duration: d}
+	t.mock.mu.Unlock()
+	t.mock.logEvent(e)
+	return registered
+}
+
+// rescheduler is implemented by timer backends that can move their deadline
+// to an absolute time, measured on their own clock.
+type rescheduler interface {
+	Reschedule(t time.Time) bool
+}
+
+// Reschedule changes the timer to expire at t rather than after a duration,
+// so that code tracking absolute deadlines needn't convert them, and a mock
+// fires the timer at exactly t. A t that is not after the current time
+// fires the timer at once, or on a mock at its next advance. Timers from
+// other implementations reset to the time remaining until t, on the clock
+// that created them if their backend has a Reschedule method, and by
+// time.Until otherwise. It returns true if the timer had been active.
+func (t *Timer) Reschedule(at time.Time) bool {
+	if t.timer != nil {
+		if r, ok := t.timer.(rescheduler); ok {
+			return r.Reschedule(at)
+		}
+		return t.timer.Reset(time.Until(at))
+	}
+
+	t.mock.mu.Lock()
+	// A deadline in the past fires at the current time, as the clock can't
+	// go back to it.
+	if at.Before(t.mock.now) {
+		at = t.mock.now
 	}
+	t.next = at
+	d := at.Sub(t.mock.now)
+	t.drainLocked()
+
+	registered := !t.stopped
+	t.mock.timers.add((*internalTimer)(t))
 
 	t.stopped = false
+	e := Event{Type: TimerReset, Time: t.mock.now, TimerID: t.id, Deadline: t.next, Duration: d}
+	t.mock.mu.Unlock()
+	t.mock.logEvent(e)
 	return registered
 }
 
+// drainLocked discards a fire that hasn't been received, so that, as with
+// time.Timer since Go 1.23, no stale time is received after Stop or Reset,
+// and there is room in C for the next fire. It must be called with the
+// mock's mu held.
+func (t *Timer) drainLocked() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
 // Ticker holds a channel that receives "ticks" at regular intervals.
 type Ticker struct {
-	C      <-chan time.Time
-	c      chan time.Time
-	ticker *time.Ticker        // realtime impl, if set
-	next   time.Time           // next tick time
-	mock   *UnsynchronizedMock // mock clock, if set
-	d      time.Duration       // time between ticks
+	C       <-chan time.Time
+	c       chan time.Time
+	ticker  TickerBackend       // realtime or wrapped impl, if set
+	id      uint64              // mock-assigned identifier
+	next    time.Time           // next tick time
+	mock    *UnsynchronizedMock // mock clock, if set
+	d       time.Duration       // time between ticks
+	stack   []uintptr           // call stack that created the ticker
+	name    string              // name given with TimerName, if any
+	confirm Checkpoint          // waited on after each tick, if set
+	pos     int                 // position in the mock's timerQueue, plus one
+
+	confirmLag bool // wait for confirm before the next tick, not after this one
+
+	policy   BacklogPolicy // what to do with ticks the consumer isn't ready for
+	backlog  []time.Time   // ticks waiting to be delivered, for QueueTicks
+	pumpDone chan struct{} // closed to stop delivering the backlog, if running
+	stop     chan struct{} // closed by Stop, to abandon a blocked delivery
+	aligned  bool          // ticks fall on multiples of d
 }
 
 // Stop turns off the ticker.
@@ -77,7 +303,15 @@ func (t *Ticker) Stop() {
 	} else {
 		t.mock.mu.Lock()
 		t.mock.removeClockTimer((*internalTicker)(t))
+		(*internalTicker)(t).dropBacklog()
+		select {
+		case <-t.stop:
+		default:
+			close(t.stop)
+		}
+		e := Event{Type: TickerStopped, Time: t.mock.now, TimerID: t.id, Deadline: t.next}
 		t.mock.mu.Unlock()
+		t.mock.logEvent(e)
 	}
 }
 
@@ -89,8 +323,15 @@ func (t *Ticker) Reset(dur time.Duration) {
 	}
 
 	t.mock.mu.Lock()
-	defer t.mock.mu.Unlock()
-
 	t.d = dur
-	t.next = t.mock.now.Add(dur)
+	t.next = (*internalTicker)(t).after(t.mock.now)
+	t.mock.timers.fix((*internalTicker)(t))
+	e := Event{Type: TickerReset, Time: t.mock.now, TimerID: t.id, Deadline: t.next, Duration: dur}
+	t.mock.mu.Unlock()
+	t.mock.logEvent(e)
 }
+
+// ID returns the identifier the mock assigned to the ticker when it was
+// created. It matches the TimerID of the ticker's Events, and is zero for
+// tickers created by the realtime clock.
+func (t *Ticker) ID() uint64 { return t.id }
@@ -0,0 +1,126 @@
+package clock
+
+import "time"
+
+// TimerOption configures a single timer or ticker created by NewTimerWith,
+// NewTickerWith or AfterFuncWith, so that timers in one test can be given
+// different synchronization treatment.
+type TimerOption func(*timerOptions)
+
+type timerOptions struct {
+	name       string
+	checkpoint Checkpoint
+	buffer     int
+	confirm    Checkpoint
+	confirmLag bool
+	policy     BacklogPolicy
+}
+
+// TimerName names the timer. The name is reported in its TimerInfo.
+func TimerName(name string) TimerOption {
+	return func(o *timerOptions) { o.name = name }
+}
+
+// TimerCheckpoint counts the timer's start against cp, instead of the mock's
+// start checkpoint, so it neither needs nor satisfies ExpectStarts.
+func TimerCheckpoint(cp Checkpoint) TimerOption {
+	return func(o *timerOptions) { o.checkpoint = cp }
+}
+
+// TimerBuffer gives the timer's or ticker's channel room for n values,
+// instead of one.
+func TimerBuffer(n int) TimerOption {
+	return func(o *timerOptions) { o.buffer = n }
+}
+
+// TimerConfirm makes the mock wait, each time the timer fires or the ticker
+// ticks, for the receiver to confirm it has handled it by calling cp.Done.
+// The clock doesn't move on until it does. The mock adds one to cp before
+// each delivery.
+func TimerConfirm(cp Checkpoint) TimerOption {
+	return func(o *timerOptions) { o.confirm = cp }
+}
+
+// RequireConfirmEachTick is like TimerConfirm, but a ticker waits for the
+// confirm just before its next tick, rather than straight after each tick.
+// The clock keeps moving, and other timers keep firing, while the consumer
+// handles a tick, but the ticker doesn't tick again, even within a single
+// large Add, until the previous tick was confirmed. It models consumers that
+// must finish processing each tick. On timers it is the same as
+// TimerConfirm.
+func RequireConfirmEachTick(cp Checkpoint) TimerOption {
+	return func(o *timerOptions) { o.confirm, o.confirmLag = cp, true }
+}
+
+// TickerStrict makes a ticker hold up the advancing clock, rather than drop
+// a tick, when its channel is full, so that the consumer receives every tick
+// exactly once. With the default buffer of one, the clock doesn't pass a
+// tick's deadline until the previous tick has been received. It is the
+// BlockTicks backlog policy, and has no effect on timers.
+func TickerStrict() TimerOption {
+	return func(o *timerOptions) { o.policy = BlockTicks }
+}
+
+func newTimerOptions(opts []TimerOption) timerOptions {
+	o := timerOptions{buffer: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewTimerWith is like NewTimer, configured by opts.
+func (m *UnsynchronizedMock) NewTimerWith(d time.Duration, opts ...TimerOption) *Timer {
+	return m.newTimerWith(d, nil, opts, m.callers())
+}
+
+// AfterFuncWith is like AfterFunc, configured by opts.
+func (m *UnsynchronizedMock) AfterFuncWith(d time.Duration, f func(), opts ...TimerOption) *Timer {
+	return m.newTimerWith(d, f, opts, m.callers())
+}
+
+func (m *UnsynchronizedMock) newTimerWith(d time.Duration, f func(), opts []TimerOption, stack []uintptr) *Timer {
+	o := newTimerOptions(opts)
+	m.mu.Lock()
+	t, created := m.newTimerLocked(m.now.Add(d), false, f, stack)
+	if f == nil && o.buffer != 1 {
+		t.c = make(chan time.Time, o.buffer)
+		t.C = t.c
+	}
+	t.name, t.confirm = o.name, o.confirm
+	started := m.startedLocked(o.checkpoint)
+	m.mu.Unlock()
+	m.logEvent(created)
+	m.logEvent(started)
+	return t
+}
+
+// NewTickerWith is like NewTicker, configured by opts.
+func (m *UnsynchronizedMock) NewTickerWith(d time.Duration, opts ...TimerOption) *Ticker {
+	o := newTimerOptions(opts)
+	m.mu.Lock()
+	t, created := m.newTickerLocked(d, o.policy, o.buffer, false, m.callers())
+	t.name, t.confirm, t.confirmLag = o.name, o.confirm, o.confirmLag
+	started := m.startedLocked(o.checkpoint)
+	m.mu.Unlock()
+	m.logEvent(created)
+	m.logEvent(started)
+	return t
+}
+
+// startedLocked counts a start against cp, or the mock's start checkpoint if
+// cp is nil, and returns the event to log for it. It must be called with mu
+// held.
+func (m *UnsynchronizedMock) startedLocked(cp Checkpoint) Event {
+	if cp == nil {
+		cp = m.startCheckpoint
+	}
+	cp.Done()
+	return Event{Type: CheckpointDone, Time: m.now, Checkpoint: checkpointName(cp), Delta: -1}
+}
+
+// waitConfirm waits for the receiver of a timer to confirm it has handled it.
+func (m *UnsynchronizedMock) waitConfirm(cp Checkpoint) {
+	cp.Wait()
+	m.logEvent(Event{Type: CheckpointWaited, Time: m.current(), Checkpoint: checkpointName(cp)})
+}
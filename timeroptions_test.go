@@ -0,0 +1,111 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that a timer's name, buffer and checkpoint are its own.
+func TestMock_TimerOptions(t *testing.T) {
+	mock := NewMock(t, 1)
+	background := NewOptionalCheckPoint("background")
+	background.Add(1)
+
+	ticker := mock.NewTickerWith(time.Second, TimerName("poll"), TimerBuffer(3), TimerCheckpoint(background))
+	defer ticker.Stop()
+	background.Wait()
+	mock.AfterFuncWith(time.Hour, func() {}, TimerName("expiry"))
+
+	pending := mock.PendingTimers()
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "poll", pending[0].Name)
+		assert.Equal(t, "expiry", pending[1].Name)
+	}
+
+	mock.Add(3 * time.Second)
+	assert.Len(t, ticker.C, 3)
+}
+
+// Ensure that the mock waits for each fire of a timer to be confirmed before
+// moving on.
+func TestMock_TimerConfirm(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	handled := NewOptionalCheckPoint("handled")
+	ticker := mock.NewTickerWith(time.Second, TimerConfirm(handled))
+	defer ticker.Stop()
+	timer := mock.NewTimerWith(1500*time.Millisecond, TimerConfirm(handled))
+
+	var seen []time.Time
+	go func() {
+		for {
+			select {
+			case now := <-ticker.C:
+				seen = append(seen, now)
+			case now := <-timer.C:
+				seen = append(seen, now)
+			}
+			time.Sleep(time.Millisecond)
+			handled.Done()
+		}
+	}()
+
+	mock.Add(3 * time.Second)
+	start := time.Unix(0, 0)
+	assert.Equal(t, []time.Time{
+		start.Add(time.Second), start.Add(1500 * time.Millisecond),
+		start.Add(2 * time.Second), start.Add(3 * time.Second),
+	}, seen)
+}
+
+// Ensure that a ticker requiring confirms waits for each tick's confirm
+// before its next tick, while other timers carry on.
+func TestMock_RequireConfirmEachTick(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	handled := NewOptionalCheckPoint("handled")
+	ticker := mock.NewTickerWith(time.Second, RequireConfirmEachTick(handled))
+	defer ticker.Stop()
+	var unconfirmed int
+	mock.AfterFunc(1500*time.Millisecond, func() { unconfirmed = handled.Outstanding() })
+
+	var mu sync.Mutex
+	var seen []time.Time
+	go func() {
+		for now := range ticker.C {
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			seen = append(seen, now)
+			mu.Unlock()
+			handled.Done()
+		}
+	}()
+
+	mock.Add(3 * time.Second)
+	assert.Equal(t, 1, unconfirmed, "AfterFunc waited for the tick's confirm")
+	handled.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []time.Time{time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0)}, seen)
+}
+
+// Ensure that a strict ticker delivers every tick, holding up the clock for
+// a slow consumer.
+func TestMock_TickerStrict(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	ticker := mock.NewTickerWith(time.Second, TickerStrict())
+	defer ticker.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		mock.Add(5 * time.Second)
+		close(done)
+	}()
+	for i := 1; i <= 5; i++ {
+		time.Sleep(time.Millisecond)
+		assert.Equal(t, time.Unix(int64(i), 0), <-ticker.C)
+	}
+	<-done
+	assert.Len(t, ticker.C, 0)
+}
@@ -0,0 +1,51 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// TimestampSource returns strictly increasing timestamps from a clock, for
+// code that generates time-ordered IDs or versions. When the clock hasn't
+// advanced by a whole resolution since the last timestamp, it counts on from
+// that timestamp instead, one resolution at a time, and returns to the
+// clock's time once it has caught up. For timestamps that must also order
+// events between processes, use an HLC.
+type TimestampSource struct {
+	clock      NowClock
+	resolution time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewTimestampSource returns a TimestampSource reading the time from c, with
+// timestamps truncated to multiples of resolution, such as time.Microsecond
+// for a database that stores microseconds. It panics if resolution is not
+// positive.
+func NewTimestampSource(c NowClock, resolution time.Duration) *TimestampSource {
+	if resolution <= 0 {
+		panic("non-positive resolution for NewTimestampSource")
+	}
+	return &TimestampSource{clock: c, resolution: resolution}
+}
+
+// Next returns a timestamp later than any it has returned before.
+func (s *TimestampSource) Next() time.Time {
+	now := s.clock.Now().Round(0).Truncate(s.resolution)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !now.After(s.last) {
+		now = s.last.Add(s.resolution)
+	}
+	s.last = now
+	return now
+}
+
+// Last returns the last timestamp returned by Next, or the zero time if it
+// hasn't been called.
+func (s *TimestampSource) Last() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampSource(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	s := NewTimestampSource(mock, time.Microsecond)
+	assert.True(t, s.Last().IsZero())
+
+	// Without the clock moving, timestamps count on a microsecond at a time.
+	assert.Equal(t, time.Unix(0, 0), s.Next())
+	assert.Equal(t, time.Unix(0, 1000), s.Next())
+	assert.Equal(t, time.Unix(0, 2000), s.Next())
+
+	// Moving less than the timestamps ran ahead continues the count...
+	mock.Add(1500 * time.Nanosecond)
+	assert.Equal(t, time.Unix(0, 3000), s.Next())
+
+	// ...and moving past them returns to the clock, truncated.
+	mock.Add(time.Millisecond)
+	assert.Equal(t, time.Unix(0, 1001000), s.Next())
+	assert.Equal(t, time.Unix(0, 1001000), s.Last())
+
+	// Setting the clock back doesn't reorder them.
+	mock.Set(time.Unix(0, 0))
+	assert.Equal(t, time.Unix(0, 1002000), s.Next())
+
+	assert.PanicsWithValue(t, "non-positive resolution for NewTimestampSource", func() {
+		NewTimestampSource(mock, 0)
+	})
+}
+
+// Ensure that timestamps are unique across goroutines.
+func TestTimestampSource_Concurrent(t *testing.T) {
+	s := NewTimestampSource(New(), time.Nanosecond)
+	var mu sync.Mutex
+	seen := map[time.Time]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				ts := s.Next()
+				mu.Lock()
+				assert.False(t, seen[ts], "duplicate timestamp %v", ts)
+				seen[ts] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, 8000)
+}
@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"time"
+)
+
+// TOTP generates and validates RFC 6238 time-based one-time passwords from
+// the time on a clock, so that authentication flows that depend on their
+// windows can be tested by setting a mock to specific instants. Its fields
+// default to the values authenticator apps use, and can be changed before
+// it is used.
+type TOTP struct {
+	// Digits is the length of each code.
+	Digits int
+	// Period is how long each code is valid for, in whole seconds.
+	Period time.Duration
+	// Hash is the HMAC hash function.
+	Hash func() hash.Hash
+	// Skew is how many periods either side of the current one Validate
+	// accepts codes from, to allow for clock differences and slow typing.
+	Skew int
+
+	clock  NowClock
+	secret []byte
+}
+
+// NewTOTP returns a TOTP for secret that reads the time from c, with 6 digit
+// codes, a 30 second period, HMAC-SHA1 and a skew of one period.
+func NewTOTP(c NowClock, secret []byte) *TOTP {
+	return &TOTP{Digits: 6, Period: 30 * time.Second, Hash: sha1.New, Skew: 1, clock: c, secret: secret}
+}
+
+// Code returns the code for the clock's current time.
+func (p *TOTP) Code() string { return p.CodeAt(p.clock.Now()) }
+
+// CodeAt returns the code for t.
+func (p *TOTP) CodeAt(t time.Time) string { return p.code(p.step(t)) }
+
+// Validate reports whether code is the code for the current period, or one
+// within Skew periods of it.
+func (p *TOTP) Validate(code string) bool {
+	step := p.step(p.clock.Now())
+	valid := false
+	for i := -int64(p.Skew); i <= int64(p.Skew); i++ {
+		if subtle.ConstantTimeCompare([]byte(p.code(step+i)), []byte(code)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// Remaining returns how long the current code has left before it changes.
+func (p *TOTP) Remaining() time.Duration {
+	now := p.clock.Now()
+	return time.Unix(0, 0).Add(time.Duration(p.step(now)+1) * p.Period).Sub(now)
+}
+
+// step returns the number of periods from the Unix epoch to t.
+func (p *TOTP) step(t time.Time) int64 {
+	if p.Period < time.Second {
+		panic("TOTP period shorter than a second")
+	}
+	return t.Unix() / int64(p.Period/time.Second)
+}
+
+// code returns the HOTP value of counter, as in RFC 4226.
+func (p *TOTP) code(counter int64) string {
+	mac := hmac.New(p.Hash, p.secret)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	off := sum[len(sum)-1] & 0xf
+	v := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < p.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", p.Digits, v%mod)
+}
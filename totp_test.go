@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The test vectors of RFC 6238, appendix B.
+func TestTOTP_RFC6238(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	totp := NewTOTP(mock, []byte("12345678901234567890"))
+	totp.Digits = 8
+	for unix, want := range map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	} {
+		mock.Set(time.Unix(unix, 0))
+		assert.Equal(t, want, totp.Code(), "%d", unix)
+	}
+
+	sha := NewTOTP(mock, []byte("12345678901234567890123456789012"))
+	sha.Digits, sha.Hash = 8, sha256.New
+	assert.Equal(t, "46119246", sha.CodeAt(time.Unix(59, 0)))
+}
+
+func TestTOTP_Validate(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.Set(time.Unix(1111111111, 0))
+	totp := NewTOTP(mock, []byte("12345678901234567890"))
+	code := totp.Code()
+	assert.Len(t, code, 6)
+	assert.True(t, totp.Validate(code))
+	assert.Equal(t, 29*time.Second, totp.Remaining())
+
+	// The code is accepted until the end of the next period...
+	mock.Add(totp.Remaining() + 29*time.Second)
+	assert.True(t, totp.Validate(code))
+	assert.NotEqual(t, code, totp.Code())
+
+	// ...and not after.
+	mock.Add(time.Second)
+	assert.False(t, totp.Validate(code))
+
+	totp.Skew = 0
+	assert.True(t, totp.Validate(totp.Code()))
+	assert.False(t, totp.Validate("000000"))
+
+	totp.Period = time.Millisecond
+	assert.PanicsWithValue(t, "TOTP period shorter than a second", func() { totp.Code() })
+}
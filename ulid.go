@@ -0,0 +1,145 @@
+package clock
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ULID is a universally unique lexicographically sortable identifier: a
+// 48-bit millisecond timestamp followed by 80 bits of entropy, big-endian,
+// so that IDs sort by the time they were made.
+type ULID [16]byte
+
+// crockford is the Crockford base 32 alphabet that ULIDs are written in.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrULIDOverflow is returned by a ULIDGenerator that has made 2^80 IDs in
+// the same millisecond.
+var ErrULIDOverflow = errors.New("ulid: entropy exhausted within a millisecond")
+
+// String returns the 26 character canonical form of u.
+func (u ULID) String() string {
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	var b [26]byte
+	for i := range b {
+		off := uint(25-i) * 5
+		var v uint64
+		switch {
+		case off >= 64:
+			v = hi >> (off - 64)
+		case off+5 <= 64:
+			v = lo >> off
+		default:
+			v = lo>>off | hi<<(64-off)
+		}
+		b[i] = crockford[v&31]
+	}
+	return string(b[:])
+}
+
+// Time returns the time encoded in u, to the millisecond.
+func (u ULID) Time() time.Time {
+	var ms [8]byte
+	copy(ms[2:], u[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+}
+
+// ParseULID parses the canonical form of a ULID. As in Crockford base 32, it
+// is not case sensitive, and reads I and L as 1 and O as 0.
+func ParseULID(s string) (ULID, error) {
+	if len(s) != 26 {
+		return ULID{}, fmt.Errorf("ulid %q: expected 26 characters, found %d", s, len(s))
+	}
+	if s[0] > '7' {
+		return ULID{}, fmt.Errorf("ulid %q: overflows 128 bits", s)
+	}
+	var hi, lo uint64
+	for _, r := range strings.ToUpper(s) {
+		switch r {
+		case 'I', 'L':
+			r = '1'
+		case 'O':
+			r = '0'
+		}
+		v := strings.IndexRune(crockford, r)
+		if v < 0 {
+			return ULID{}, fmt.Errorf("ulid %q: invalid character %q", s, r)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	var u ULID
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+// ULIDGenerator makes ULIDs from the time on a clock. IDs made in the same
+// millisecond increment the entropy of the last, rather than drawing new
+// entropy, so they sort in the order they were made; the same holds if the
+// clock is set back, in which case the last millisecond is kept until the
+// clock passes it. On a mock with the default entropy, the IDs are the same
+// on every run, so snapshot tests of output that embeds them are stable. On
+// any other clock, the default entropy is crypto/rand, so that IDs can't be
+// predicted.
+type ULIDGenerator struct {
+	clock   NowClock
+	entropy io.Reader
+
+	mu   sync.Mutex
+	last ULID
+}
+
+// NewULIDGenerator returns a ULIDGenerator that reads the time from c and
+// entropy from entropy. If entropy is nil, it reads from Rand(c) on a mock,
+// and from crypto/rand otherwise.
+func NewULIDGenerator(c MockableClock, entropy io.Reader) *ULIDGenerator {
+	if entropy == nil {
+		if _, ok := c.(interface{ randSource() rand.Source }); ok {
+			entropy = Rand(c)
+		} else {
+			entropy = crand.Reader
+		}
+	}
+	return &ULIDGenerator{clock: c, entropy: entropy}
+}
+
+// New returns a ULID later than any the generator has made before. It
+// returns an error if reading the entropy fails, or ErrULIDOverflow.
+func (g *ULIDGenerator) New() (ULID, error) {
+	ms := uint64(g.clock.Now().UnixMilli())
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var u ULID
+	var last [8]byte
+	copy(last[2:], g.last[:6])
+	if prev := binary.BigEndian.Uint64(last[:]); g.last != (ULID{}) && ms <= prev {
+		u = g.last
+		for i := len(u) - 1; ; i-- {
+			if i < 6 {
+				return ULID{}, ErrULIDOverflow
+			}
+			u[i]++
+			if u[i] != 0 {
+				break
+			}
+		}
+	} else {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], ms)
+		copy(u[:6], b[2:])
+		if _, err := io.ReadFull(g.entropy, u[6:]); err != nil {
+			return ULID{}, fmt.Errorf("ulid: reading entropy: %w", err)
+		}
+	}
+	g.last = u
+	return u, nil
+}
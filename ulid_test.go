@@ -0,0 +1,89 @@
+package clock
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseULID(t *testing.T) {
+	u, err := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1469918176385), u.Time().UnixMilli())
+	assert.Equal(t, "01ARYZ6S41TSV4RRFFQ69G5FAV", u.String())
+
+	lower, err := ParseULID("01aryz6s41tsv4rrffq69g5fav")
+	assert.NoError(t, err)
+	assert.Equal(t, u, lower)
+
+	max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	assert.NoError(t, err)
+	assert.Equal(t, ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, max)
+	assert.Equal(t, strings.Repeat("0", 26), ULID{}.String())
+
+	for _, s := range []string{"", "01ARYZ6S41TSV4RRFFQ69G5FA", "81ARYZ6S41TSV4RRFFQ69G5FAV", "01ARYZ6S41TSV4RRFFQ69G5FAU"} {
+		_, err := ParseULID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	mock := NewUnsynchronizedMock(Seed(1))
+	mock.Set(time.UnixMilli(1469918176385))
+	g := NewULIDGenerator(mock, nil)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		u, err := g.New()
+		assert.NoError(t, err)
+		assert.Equal(t, mock.Now(), u.Time())
+		ids = append(ids, u.String())
+	}
+	mock.Add(time.Millisecond)
+	u, err := g.New()
+	assert.NoError(t, err)
+	ids = append(ids, u.String())
+
+	// Setting the clock back keeps them in order.
+	mock.Set(time.Unix(0, 0))
+	u, err = g.New()
+	assert.NoError(t, err)
+	ids = append(ids, u.String())
+
+	assert.True(t, sort.StringsAreSorted(ids), "ids out of order: %v", ids)
+	assert.True(t, strings.HasPrefix(ids[0], "01ARYZ6S41"), ids[0])
+	assert.Equal(t, ids[0][:24], ids[1][:24], "same millisecond should increment the entropy")
+
+	// The same seed and times give the same IDs.
+	again := NewUnsynchronizedMock(Seed(1))
+	again.Set(time.UnixMilli(1469918176385))
+	first, _ := NewULIDGenerator(again, nil).New()
+	assert.Equal(t, ids[0], first.String())
+}
+
+func TestULIDGenerator_Entropy(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	g := NewULIDGenerator(mock, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	u, err := g.New()
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000ZZZZZZZZZZZZZZZZ", u.String())
+	_, err = g.New()
+	assert.True(t, errors.Is(err, ErrULIDOverflow), err)
+
+	mock.Add(time.Millisecond)
+	_, err = g.New()
+	assert.Error(t, err, "entropy should be exhausted")
+}
+
+// Ensure that only a mock's generator defaults to reproducible entropy.
+func TestULIDGenerator_DefaultEntropy(t *testing.T) {
+	assert.Equal(t, crand.Reader, NewULIDGenerator(New(), nil).entropy)
+	assert.Equal(t, crand.Reader, NewULIDGenerator(NewLeakTracking(), nil).entropy)
+	assert.NotEqual(t, crand.Reader, NewULIDGenerator(NewUnsynchronizedMock(), nil).entropy)
+}
@@ -1,25 +1,70 @@
 package clock
 
 import (
-	"sort"
+	"context"
+	"math"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 const (
-	TimerStart CheckpointName = "TimerStart"
+	TimerStart    CheckpointName = "TimerStart"
+	AfterFuncDone CheckpointName = "AfterFuncDone"
 )
 
 var (
-	WaitBefore = &WaitBeforeOption{}
+	WaitBefore       = &WaitBeforeOption{}
+	WaitAfter        = &WaitAfterOption{}
+	AsyncAfterFuncs  = &AsyncAfterFuncsOption{}
+	InlineAfterFuncs = &InlineAfterFuncsOption{}
 )
 
+// Option changes how the mock behaves around an advance. Options act in
+// three phases: PriorEventsOption before the clock moves, for events that
+// are already underway; UpcomingEventsOption before the clock moves, for
+// events the advance will cause; and, for options that also implement
+// AfterAdvanceOption, once the advance is done.
 type Option interface {
 	PriorEventsOption(*UnsynchronizedMock)
 	UpcomingEventsOption(*UnsynchronizedMock)
 }
 
+// AfterAdvanceOption is implemented by options that also act once an
+// advance is done, such as to wait for the work it started.
+type AfterAdvanceOption interface {
+	Option
+	AfterAdvanceOption(*UnsynchronizedMock)
+}
+
+// PhasedOption is an Option made of a function for each phase. Functions
+// that are nil are skipped.
+type PhasedOption struct {
+	Prior    func(*UnsynchronizedMock)
+	Upcoming func(*UnsynchronizedMock)
+	After    func(*UnsynchronizedMock)
+}
+
+func (o *PhasedOption) PriorEventsOption(mock *UnsynchronizedMock) {
+	if o.Prior != nil {
+		o.Prior(mock)
+	}
+}
+
+func (o *PhasedOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	if o.Upcoming != nil {
+		o.Upcoming(mock)
+	}
+}
+
+func (o *PhasedOption) AfterAdvanceOption(mock *UnsynchronizedMock) {
+	if o.After != nil {
+		o.After(mock)
+	}
+}
+
 type FailOnUnexpectedUpcomingEventOption struct {
 	t *testing.T
 }
@@ -34,6 +79,51 @@ func (o *FailOnUnexpectedUpcomingEventOption) UpcomingEventsOption(mock *Unsynch
 	mock.startCheckpoint = NewFailOnUnexpectedCheckpoint(TimerStart, o.t)
 }
 
+// OnUnexpectedUpcomingEventOption is like FailOnUnexpectedUpcomingEventOption,
+// but calls a function instead of failing a test.
+type OnUnexpectedUpcomingEventOption struct {
+	fn func(CheckpointName)
+}
+
+// OnUnexpectedUpcomingEvent calls fn with the checkpoint's name for each new
+// timer that isn't accounted for by a call to Expect, so the mock can police
+// timer starts without a *testing.T, such as in TestMain or an example
+// program. fn is called with the mock locked, so it must not use the mock.
+// Pass PanicOnUnexpected to panic.
+func OnUnexpectedUpcomingEvent(fn func(CheckpointName)) *OnUnexpectedUpcomingEventOption {
+	return &OnUnexpectedUpcomingEventOption{fn}
+}
+
+func (o *OnUnexpectedUpcomingEventOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *OnUnexpectedUpcomingEventOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.startCheckpoint = NewFailOnUnexpectedCheckpointFunc(TimerStart, o.fn)
+}
+
+// VerifyStartsOption fails a test that ends while expected timer starts or
+// AfterFunc callbacks are still outstanding.
+type VerifyStartsOption struct {
+	tb testing.TB
+}
+
+// VerifyStarts registers a cleanup with tb that verifies the mock's
+// checkpoints once the test is over. It is meant to be passed to
+// NewUnsynchronizedMock; each advance it is passed to registers another.
+func VerifyStarts(tb testing.TB) *VerifyStartsOption {
+	return &VerifyStartsOption{tb}
+}
+
+func (o *VerifyStartsOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *VerifyStartsOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	o.tb.Cleanup(func() {
+		mock.mu.Lock()
+		cps := []Checkpoint{mock.startCheckpoint, mock.afterFuncCheckpoint}
+		mock.mu.Unlock()
+		verifyAll(o.tb, cps)
+	})
+}
+
 type IgnoreUnexpectedUpcomingEventOption struct{}
 
 func (o *IgnoreUnexpectedUpcomingEventOption) PriorEventsOption(mock *UnsynchronizedMock) {}
@@ -64,6 +154,18 @@ func (o *WaitBeforeOption) PriorEventsOption(mock *UnsynchronizedMock) {
 
 func (o *WaitBeforeOption) UpcomingEventsOption(mock *UnsynchronizedMock) {}
 
+// WaitAfterOption waits, once an advance is done, for AfterFunc callbacks
+// it started on their own goroutine to return.
+type WaitAfterOption struct{}
+
+func (o *WaitAfterOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *WaitAfterOption) UpcomingEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *WaitAfterOption) AfterAdvanceOption(mock *UnsynchronizedMock) {
+	mock.WaitAfterFuncs()
+}
+
 type OptimisticSchedOption struct{}
 
 func (o *OptimisticSchedOption) PriorEventsOption(mock *UnsynchronizedMock) {}
@@ -72,24 +174,89 @@ func (o *OptimisticSchedOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
 	gosched()
 }
 
+// AsyncAfterFuncsOption causes AfterFunc callbacks to run on their own
+// goroutine, as time.AfterFunc does, rather than inline from Add or Set.
+// Use WaitAfterFuncs to wait for callbacks that have been started.
+type AsyncAfterFuncsOption struct{}
+
+func (o *AsyncAfterFuncsOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *AsyncAfterFuncsOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.asyncAfterFuncs = true
+}
+
+// InlineAfterFuncsOption restores the default behavior of running AfterFunc
+// callbacks inline from Add or Set.
+type InlineAfterFuncsOption struct{}
+
+func (o *InlineAfterFuncsOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *InlineAfterFuncsOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.asyncAfterFuncs = false
+}
+
 // UnsynchronizedMock represents a mock clock that only moves forward programmatically.
 // It can be preferable to a real-time clock when testing time-based functionality. By
 // default, it does not enforce synchronization although options may be passed in to
 // cause sync.
+//
+// Timers and tickers fire in order of their deadlines. When several share the
+// same deadline, they fire in the order they were created, unless the
+// ShuffleTies option is in effect.
 type UnsynchronizedMock struct {
-	mu     sync.Mutex
-	now    time.Time   // current time
-	timers clockTimers // tickers & timers
-
-	startCheckpoint Checkpoint
+	// nowNanos mirrors now as nanoseconds after *nowBase, so that Now can
+	// read it without taking mu, or is slowNow if Now must take mu. It is
+	// first so that it is 64-bit aligned for atomic access.
+	nowNanos int64
+	nowBase  atomic.Value // *time.Time
+	guard    atomic.Value // *guard, in guard mode
+
+	captureStacks int32 // set to 1 to record where timers are created
+
+	mu      sync.Mutex
+	now     time.Time   // current time
+	timers  timerQueue  // tickers & timers
+	nextID  uint64      // id assigned to the next timer or ticker
+	logger  func(Event) // receives mock events, if set
+	history []Event     // the latest events produced, a ring from historyStart
+	events  uint64      // number of events produced, for detecting activity
+	usage   UsageReport // summary of the events produced, for Report
+
+	historyStart int // index in history of the oldest event
+	historyLimit int // most events kept in history, or negative for no limit
+
+	asyncAfterFuncs bool        // run AfterFunc callbacks on their own goroutine
+	ties            *rand.Rand  // picks among timers with equal deadlines, if set
+	rng             *rand.Rand  // source for Rand and Jitter, seeded with 0 if unset
+	jump            *JumpPolicy // how the next advance jumps, if set
+	capped          bool        // a MaxVirtualTime cap is in effect
+	overlapTB       testing.TB  // fails on overlapping advances, if set
+	advancing       []uintptr   // call stack of the advance underway, if checked
+
+	onAdvance       []func(from, to time.Time)       // OnAdvance hooks
+	onBeforeAdvance []func(from, to time.Time) error // OnBeforeAdvance hooks
+	onTimerFire     []func(TimerInfo, time.Time)     // OnTimerFire hooks
+
+	drift     float64       // seconds Now gains per second, from driftFrom
+	driftFrom time.Time     // when drift was last set
+	skew      time.Duration // how far Now had drifted by driftFrom
+
+	startCheckpoint     Checkpoint
+	afterFuncCheckpoint *OptionalCheckpoint
 }
 
 // NewUnsynchronizedMock returns an instance of a mock clock.
 // The current time of the mock clock on initialization is the Unix epoch.
 func NewUnsynchronizedMock(opts ...Option) *UnsynchronizedMock {
 	ret := &UnsynchronizedMock{
-		now:             time.Unix(0, 0),
-		startCheckpoint: NewOptionalCheckPoint(TimerStart),
+		now:                 time.Unix(0, 0),
+		startCheckpoint:     NewOptionalCheckPoint(TimerStart),
+		afterFuncCheckpoint: NewOptionalCheckPoint(AfterFuncDone),
+		historyLimit:        DefaultHistoryLimit,
 	}
 	for _, opt := range opts {
 		opt.UpcomingEventsOption(ret)
@@ -100,8 +267,10 @@ func NewUnsynchronizedMock(opts ...Option) *UnsynchronizedMock {
 // ExpectStarts informs the mock how many timers should have been created before we advance the clock
 func (m *UnsynchronizedMock) ExpectStarts(delta int) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.startCheckpoint.Add(delta)
+	e := Event{Type: CheckpointAdded, Time: m.now, Checkpoint: checkpointName(m.startCheckpoint), Delta: delta}
+	m.mu.Unlock()
+	m.logEvent(e)
 }
 
 // Wait will block until all expected timers have started
@@ -110,47 +279,83 @@ func (m *UnsynchronizedMock) Wait() {
 	sp := m.startCheckpoint
 	m.mu.Unlock()
 	sp.Wait()
+	m.logEvent(Event{Type: CheckpointWaited, Time: m.current(), Checkpoint: checkpointName(sp)})
+}
+
+// WaitAfterFuncs will block until all AfterFunc callbacks that have been
+// started on their own goroutine have returned. It returns immediately
+// unless the AsyncAfterFuncs option is in effect.
+func (m *UnsynchronizedMock) WaitAfterFuncs() {
+	m.afterFuncCheckpoint.Wait()
+	m.logEvent(Event{Type: CheckpointWaited, Time: m.current(), Checkpoint: AfterFuncDone})
 }
 
 // Add moves the current time of the mock clock forward by the specified duration.
 // This should only be called from a single goroutine at a time.
 func (m *UnsynchronizedMock) Add(d time.Duration, opts ...Option) {
-	for _, opt := range opts {
-		opt.PriorEventsOption(m)
-	}
-
-	for _, opt := range opts {
-		opt.UpcomingEventsOption(m)
-	}
+	m.applyOptions(opts)
 	// Calculate the final current time.
-	t := m.now.Add(d)
-
-	// Continue to execute timers until there are no more before the new time.
-	for {
-		if !m.runNextTimer(t) {
-			break
-		}
-	}
-
-	// Ensure that we end with the new time.
 	m.mu.Lock()
-	m.now = t
+	t := m.now.Add(d)
 	m.mu.Unlock()
+
+	m.advance(t, nil)
+	m.finishOptions(opts)
 }
 
 // Set sets the current time of the mock clock to a specific one.
 // This should only be called from a single goroutine at a time.
 func (m *UnsynchronizedMock) Set(t time.Time, opts ...Option) {
+	m.applyOptions(opts)
+	m.advance(t, nil)
+	m.finishOptions(opts)
+}
+
+// applyOptions applies the options to an advance: first those for events
+// before it, then those for events after it.
+func (m *UnsynchronizedMock) applyOptions(opts []Option) {
 	for _, opt := range opts {
 		opt.PriorEventsOption(m)
 	}
-
 	for _, opt := range opts {
 		opt.UpcomingEventsOption(m)
 	}
+}
+
+// finishOptions applies the options that act once an advance is done.
+func (m *UnsynchronizedMock) finishOptions(opts []Option) {
+	for _, opt := range opts {
+		if after, ok := opt.(AfterAdvanceOption); ok {
+			after.AfterAdvanceOption(m)
+		}
+	}
+}
+
+// advance executes all timers due up to t, then moves the current time to t.
+// If report is not nil, the timers executed are added to it.
+func (m *UnsynchronizedMock) advance(t time.Time, report *AdvanceReport) {
+	defer m.beginAdvance()()
+	m.mu.Lock()
+	from := m.now
+	jump := m.jump
+	m.jump = nil
+	m.mu.Unlock()
+	if err := m.vetoAdvance(from, t); err != nil {
+		if report != nil {
+			report.To = from
+			report.Vetoed = err
+		}
+		return
+	}
+	defer m.advanced(from, t)
+	if jump != nil {
+		m.jumpTo(t, *jump, report)
+		return
+	}
+
 	// Continue to execute timers until there are no more before the new time.
 	for {
-		if !m.runNextTimer(t) {
+		if !m.runNextTimer(t, report) {
 			break
 		}
 	}
@@ -158,37 +363,43 @@ func (m *UnsynchronizedMock) Set(t time.Time, opts ...Option) {
 	// Ensure that we end with the new time.
 	m.mu.Lock()
 	m.now = t
+	m.publishNowLocked()
 	m.mu.Unlock()
+	m.logEvent(Event{Type: ClockAdvanced, Time: t, Duration: t.Sub(from)})
 }
 
 // runNextTimer executes the next timer in chronological order and moves the
 // current time to the timer's next tick time. The next time is not executed if
 // its next time is after the max time. Returns true if a timer was executed.
-func (m *UnsynchronizedMock) runNextTimer(max time.Time) bool {
+// If report is not nil, the timer is added to it.
+func (m *UnsynchronizedMock) runNextTimer(max time.Time, report *AdvanceReport) bool {
 	m.mu.Lock()
 
-	// Sort timers by time.
-	sort.Sort(m.timers)
-
-	// If we have no more timers then exit.
-	if len(m.timers) == 0 {
+	// Retrieve next timer. Exit if there are none, or the next tick is after
+	// the new time.
+	t := m.timers.peek()
+	if t == nil || t.Next().After(max) {
 		m.mu.Unlock()
 		return false
 	}
-
-	// Retrieve next timer. Exit if next tick is after new time.
-	t := m.timers[0]
-	if t.Next().After(max) {
-		m.mu.Unlock()
-		return false
+	if m.ties != nil {
+		tied := m.timers.tied()
+		t = tied[m.ties.Intn(len(tied))]
 	}
 
 	// Move "now" forward and unlock clock.
 	m.now = t.Next()
+	m.publishNowLocked()
+	if report != nil {
+		report.Fired = append(report.Fired, t.info())
+	}
+	now := m.now
+	firing := m.firingLocked(t, now)
 	m.mu.Unlock()
 
 	// Execute timer.
-	t.Tick(m.now)
+	firing()
+	t.Tick(now)
 	return true
 }
 
@@ -197,17 +408,93 @@ func (m *UnsynchronizedMock) After(d time.Duration) <-chan time.Time {
 	return m.NewTimer(d).C
 }
 
+// AfterAt waits until the mock clock reaches t and then sends the current
+// time on the returned channel. If t is not after the current time, the
+// time is sent immediately.
+func (m *UnsynchronizedMock) AfterAt(t time.Time) <-chan time.Time {
+	m.guardTime("AfterAt", t)
+	return m.newTimer(t, true, m.callers()).C
+}
+
 // AfterFunc waits for the duration to elapse and then executes a function.
 // A Timer is returned that can be stopped.
+//
+// As with time.AfterFunc, the timer has expired by the time f runs, so Stop
+// and Reset report it as inactive from then on, including from within f.
+// Reset reschedules the timer whether or not it has fired or been stopped,
+// and f runs again when the clock reaches the new deadline. A deadline that
+// is not after the current time is reached by the next advance.
 func (m *UnsynchronizedMock) AfterFunc(d time.Duration, f func()) MockableTimer {
 	t := m.NewTimer(d)
+	m.mu.Lock()
 	t.C = nil
 	t.fn = f
+	m.mu.Unlock()
 	return t
 }
 
-// Now returns the current wall time on the mock clock.
+// AtFunc executes f when the mock clock reaches t, however the clock gets
+// there, so a Set past t fires it without the caller having to recompute a
+// duration. Like AfterFunc, a t that is not after the current time is
+// reached by the next advance, and the returned timer can be stopped or
+// Reset to a duration.
+func (m *UnsynchronizedMock) AtFunc(t time.Time, f func()) MockableTimer {
+	m.guardTime("AtFunc", t)
+	m.mu.Lock()
+	timer, created := m.newTimerLocked(t, false, f, m.callers())
+	started := m.startedLocked(nil)
+	m.mu.Unlock()
+	m.logEvent(created)
+	m.logEvent(started)
+	return timer
+}
+
+// slowNow is stored in nowNanos when Now must take mu.
+const slowNow = math.MinInt64
+
+// Now returns the current wall time on the mock clock. It doesn't take the
+// mock's lock unless DriftPerSecond is in effect, so code under test can call
+// it in hot loops.
 func (m *UnsynchronizedMock) Now() time.Time {
+	if base, ok := m.nowBase.Load().(*time.Time); ok {
+		// The base is read again after the offset, in case it was replaced
+		// in between; see publishNowLocked.
+		if n := atomic.LoadInt64(&m.nowNanos); n != slowNow && m.nowBase.Load() == base {
+			return base.Add(time.Duration(n))
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.publishNowLocked()
+	return m.now.Add(m.skewLocked())
+}
+
+// publishNowLocked updates the mirror of now that Now reads. It must be
+// called with mu held whenever now or the drift changes. The base is
+// replaced only when now's location changes or it moves too far to be an
+// offset from it, and slowNow is stored while it is, so a Now that reads
+// the new offset and the old base sees the base change and takes mu.
+func (m *UnsynchronizedMock) publishNowLocked() {
+	if m.drift != 0 || m.skew != 0 {
+		atomic.StoreInt64(&m.nowNanos, slowNow)
+		return
+	}
+	if base, ok := m.nowBase.Load().(*time.Time); ok && base.Location() == m.now.Location() {
+		// Sub saturates, so the extremes can't be trusted as offsets.
+		if d := m.now.Sub(*base); d > math.MinInt64 && d < math.MaxInt64 {
+			atomic.StoreInt64(&m.nowNanos, int64(d))
+			return
+		}
+	}
+	atomic.StoreInt64(&m.nowNanos, slowNow)
+	base := m.now
+	m.nowBase.Store(&base)
+	atomic.StoreInt64(&m.nowNanos, 0)
+}
+
+// current returns the time the mock's timers run on, which differs from Now
+// when the DriftPerSecond option is in effect.
+func (m *UnsynchronizedMock) current() time.Time {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.now
@@ -215,6 +502,7 @@ func (m *UnsynchronizedMock) Now() time.Time {
 
 // Since returns time since the mock clock's wall time.
 func (m *UnsynchronizedMock) Since(t time.Time) time.Duration {
+	m.guardTime("Since", t)
 	return m.Now().Sub(t)
 }
 
@@ -224,6 +512,21 @@ func (m *UnsynchronizedMock) Sleep(d time.Duration) {
 	<-m.After(d)
 }
 
+// SleepUntil pauses the goroutine until the mock clock reaches t. It returns
+// immediately if t is not after the current time.
+// The clock must be moved forward in a separate goroutine.
+func (m *UnsynchronizedMock) SleepUntil(t time.Time) {
+	m.guardTime("SleepUntil", t)
+	<-m.newTimer(t, true, m.callers()).C
+}
+
+// SleepContext pauses the goroutine for the given duration on the mock clock,
+// or until ctx is done, in which case it returns ctx's error.
+// The clock must be moved forward in a separate goroutine.
+func (m *UnsynchronizedMock) SleepContext(ctx context.Context, d time.Duration) error {
+	return sleepContext(ctx, m.NewTimer(d))
+}
+
 // Tick is a convenience function for Ticker().
 // It will return a ticker channel that cannot be stopped.
 func (m *UnsynchronizedMock) Tick(d time.Duration) <-chan time.Time {
@@ -232,78 +535,167 @@ func (m *UnsynchronizedMock) Tick(d time.Duration) <-chan time.Time {
 
 // NewTicker creates a new instance of NewTicker.
 func (m *UnsynchronizedMock) NewTicker(d time.Duration) *Ticker {
+	return m.newTicker(d, CoalesceTicks, 1, false, m.callers())
+}
+
+func (m *UnsynchronizedMock) newTicker(d time.Duration, policy BacklogPolicy, size int, aligned bool, stack []uintptr) *Ticker {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	ch := make(chan time.Time, 1)
+	t, created := m.newTickerLocked(d, policy, size, aligned, stack)
+	started := m.startedLocked(nil)
+	m.mu.Unlock()
+	m.logEvent(created)
+	m.logEvent(started)
+	return t
+}
+
+// newTickerLocked registers a new ticker, and returns it with the event to
+// log for it. It must be called with mu held, and the caller is responsible
+// for the start checkpoint.
+func (m *UnsynchronizedMock) newTickerLocked(d time.Duration, policy BacklogPolicy, size int, aligned bool, stack []uintptr) (*Ticker, Event) {
+	ch := make(chan time.Time, size)
+	m.nextID++
 	t := &Ticker{
-		C:    ch,
-		c:    ch,
-		mock: m,
-		d:    d,
-		next: m.now.Add(d),
+		C:       ch,
+		c:       ch,
+		id:      m.nextID,
+		mock:    m,
+		d:       d,
+		stack:   stack,
+		policy:  policy,
+		aligned: aligned,
+		stop:    make(chan struct{}),
 	}
-	m.timers = append(m.timers, (*internalTicker)(t))
-	m.startCheckpoint.Done()
-	return t
+	t.next = (*internalTicker)(t).after(m.now)
+	m.timers.add((*internalTicker)(t))
+	return t, Event{Type: TickerCreated, Time: m.now, TimerID: t.id, Deadline: t.next, Duration: d}
 }
 
 // NewTimer creates a new instance of NewTimer.
 func (m *UnsynchronizedMock) NewTimer(d time.Duration) *Timer {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	next := m.now.Add(d)
+	m.mu.Unlock()
+	return m.newTimer(next, false, m.callers())
+}
+
+// newTimer creates a timer that fires at next. If fireNow is set and next is
+// not after the current time, the timer fires immediately.
+func (m *UnsynchronizedMock) newTimer(next time.Time, fireNow bool, stack []uintptr) *Timer {
+	m.mu.Lock()
+	t, created := m.newTimerLocked(next, fireNow, nil, stack)
+	started := m.startedLocked(nil)
+	m.mu.Unlock()
+	m.logEvent(created)
+	m.logEvent(started)
+	return t
+}
+
+// newTimerLocked registers a new timer, which calls fn if it is not nil,
+// and returns it with the event to log for it. It must be called with mu
+// held, and the caller is responsible for the start checkpoint.
+func (m *UnsynchronizedMock) newTimerLocked(next time.Time, fireNow bool, fn func(), stack []uintptr) (*Timer, Event) {
 	ch := make(chan time.Time, 1)
+	m.nextID++
 	t := &Timer{
 		C:       ch,
 		c:       ch,
+		id:      m.nextID,
 		mock:    m,
-		next:    m.now.Add(d),
+		next:    next,
+		fn:      fn,
 		stopped: false,
+		stack:   stack,
 	}
-	m.timers = append(m.timers, (*internalTimer)(t))
-	m.startCheckpoint.Done()
-	return t
+	if fn != nil {
+		t.C = nil
+	}
+	if fireNow && !next.After(m.now) {
+		t.stopped = true
+		t.c <- m.now
+	} else {
+		m.timers.add((*internalTimer)(t))
+	}
+	return t, Event{Type: TimerCreated, Time: m.now, TimerID: t.id, Deadline: t.next, Duration: next.Sub(m.now)}
 }
 
 func (m *UnsynchronizedMock) removeClockTimer(t clockTimer) {
-	for i, timer := range m.timers {
-		if timer == t {
-			copy(m.timers[i:], m.timers[i+1:])
-			m.timers[len(m.timers)-1] = nil
-			m.timers = m.timers[:len(m.timers)-1]
-			break
-		}
-	}
-	sort.Sort(m.timers)
+	m.timers.remove(t)
 }
 
 type internalTimer Timer
 
 func (t *internalTimer) Next() time.Time { return t.next }
+func (t *internalTimer) seq() uint64     { return t.id }
+func (t *internalTimer) position() *int  { return &t.pos }
 func (t *internalTimer) Tick(now time.Time) {
+	if t.confirm != nil {
+		t.confirm.Add(1)
+	}
 	t.mock.mu.Lock()
-	if t.fn != nil {
+	// The timer has expired before it is delivered, so that Stop and Reset
+	// called from the receiver or callback report it as inactive.
+	t.mock.removeClockTimer((*internalTimer)(t))
+	t.stopped = true
+	// The callback may Reset the timer, so the deadline that fired is kept.
+	deadline := t.next
+	if t.fn != nil && t.mock.asyncAfterFuncs {
+		fn, cp := t.fn, t.mock.afterFuncCheckpoint
+		cp.Add(1)
+		go func() {
+			defer cp.Done()
+			fn()
+		}()
+	} else if t.fn != nil {
 		t.mock.mu.Unlock()
 		t.fn()
 		t.mock.mu.Lock()
 	} else {
-		t.c <- now
+		// Reset drains C, so it has room; never block the advance with
+		// mu held regardless.
+		select {
+		case t.c <- now:
+		default:
+		}
 	}
-	t.mock.removeClockTimer((*internalTimer)(t))
-	t.stopped = true
 	t.mock.mu.Unlock()
+	t.mock.logEvent(Event{Type: TimerFired, Time: now, TimerID: t.id, Deadline: deadline})
 	gosched()
+	if t.confirm != nil {
+		t.mock.waitConfirm(t.confirm)
+	}
 }
 
 type internalTicker Ticker
 
 func (t *internalTicker) Next() time.Time { return t.next }
+func (t *internalTicker) seq() uint64     { return t.id }
+func (t *internalTicker) position() *int  { return &t.pos }
 func (t *internalTicker) Tick(now time.Time) {
-	select {
-	case t.c <- now:
-	default:
+	if t.confirm != nil {
+		if t.confirmLag {
+			t.mock.waitConfirm(t.confirm)
+		}
+		t.confirm.Add(1)
 	}
-	t.next = now.Add(t.d)
+	t.deliver(now)
+	t.mock.mu.Lock()
+	t.next = t.after(now)
+	t.mock.timers.fix(t)
+	e := Event{Type: TickerFired, Time: now, TimerID: t.id, Deadline: now}
+	t.mock.mu.Unlock()
+	t.mock.logEvent(e)
 	gosched()
+	if t.confirm != nil && !t.confirmLag {
+		t.mock.waitConfirm(t.confirm)
+	}
+}
+
+// after returns the first tick time following now.
+func (t *internalTicker) after(now time.Time) time.Time {
+	if t.aligned {
+		return now.Truncate(t.d).Add(t.d)
+	}
+	return now.Add(t.d)
 }
 
 // Sleep momentarily so that other goroutines can process.
@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// UsageReport summarizes how a test used a mock's virtual time.
+type UsageReport struct {
+	Elapsed       time.Duration // total virtual time the clock was advanced by
+	Advances      int           // number of advances
+	TimersCreated int           // timers, tickers and events created
+	TimersFired   int           // timer firings and ticks
+	LargestJump   time.Duration // largest single advance
+}
+
+func (r UsageReport) String() string {
+	return fmt.Sprintf("virtual time: %v elapsed in %d advances (largest %v), %d timers created, %d fired",
+		r.Elapsed, r.Advances, r.LargestJump, r.TimersCreated, r.TimersFired)
+}
+
+// Report returns a summary of the mock's use of virtual time since it was
+// created. Unlike History, it is not cleared by ResetHistory.
+func (m *UnsynchronizedMock) Report() UsageReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
+// countLocked adds e to the usage report. It must be called with mu held.
+func (m *UnsynchronizedMock) countLocked(e Event) {
+	switch e.Type {
+	case TimerCreated, TickerCreated:
+		m.usage.TimersCreated++
+	case TimerFired, TickerFired:
+		m.usage.TimersFired++
+	case ClockAdvanced:
+		m.usage.Advances++
+		m.usage.Elapsed += e.Duration
+		if e.Duration > m.usage.LargestJump {
+			m.usage.LargestJump = e.Duration
+		}
+	}
+}
+
+// LogReportOption logs the mock's usage report when a test ends.
+type LogReportOption struct {
+	tb testing.TB
+}
+
+// LogReport registers a cleanup with tb that logs the mock's Report once
+// the test is over, for visibility into how much virtual time it used. It
+// is meant to be passed to NewUnsynchronizedMock.
+func LogReport(tb testing.TB) *LogReportOption {
+	return &LogReportOption{tb}
+}
+
+func (o *LogReportOption) PriorEventsOption(mock *UnsynchronizedMock) {}
+
+func (o *LogReportOption) UpcomingEventsOption(mock *UnsynchronizedMock) {
+	o.tb.Cleanup(func() {
+		o.tb.Logf("%v", mock.Report())
+	})
+}
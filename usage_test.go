@@ -0,0 +1,33 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMock_Report(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	mock := NewUnsynchronizedMock(LogReport(tb))
+	ticker := mock.NewTicker(time.Minute)
+	defer ticker.Stop()
+	mock.AfterFunc(90*time.Second, func() {})
+
+	mock.Add(time.Minute)
+	mock.ResetHistory()
+	mock.Add(time.Hour)
+	mock.Add(0)
+
+	r := mock.Report()
+	assert.Equal(t, UsageReport{
+		Elapsed:       time.Hour + time.Minute,
+		Advances:      3,
+		TimersCreated: 2,
+		TimersFired:   61 + 1,
+		LargestJump:   time.Hour,
+	}, r)
+
+	tb.runCleanups()
+	assert.Equal(t, []string{"virtual time: 1h1m0s elapsed in 3 advances (largest 1h0m0s), 2 timers created, 62 fired"}, tb.logs)
+}
@@ -0,0 +1,71 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotYetValid is wrapped by the error returned for a window that
+	// hasn't started.
+	ErrNotYetValid = errors.New("not yet valid")
+	// ErrExpired is wrapped by the error returned for a window that has
+	// ended.
+	ErrExpired = errors.New("expired")
+)
+
+// ValidWindow checks the clock's time against a validity window, such as
+// the nbf and exp claims of a JWT or the lifetime of a session, with no
+// leeway. See Validator.
+func ValidWindow(c NowClock, notBefore, expiry time.Time) error {
+	return (&Validator{clock: c}).Check(notBefore, expiry)
+}
+
+// Validator checks validity windows against the time on a clock, allowing
+// leeway for skew between the clock that issued them and this one. Since
+// the time comes from the clock, expiry logic can be tested at exact
+// boundaries by setting a mock, rather than by sleeping.
+type Validator struct {
+	clock  NowClock
+	leeway time.Duration
+}
+
+// NewValidator returns a Validator that reads the time from c and allows
+// leeway either side of each window. It panics if leeway is negative.
+func NewValidator(c NowClock, leeway time.Duration) *Validator {
+	if leeway < 0 {
+		panic("negative leeway for NewValidator")
+	}
+	return &Validator{clock: c, leeway: leeway}
+}
+
+// Check returns nil if the clock is within the window from notBefore,
+// inclusive, to expiry, exclusive, widened by the leeway at each end. A zero
+// notBefore or expiry leaves that end of the window open. Otherwise it
+// returns an error wrapping ErrNotYetValid or ErrExpired.
+func (v *Validator) Check(notBefore, expiry time.Time) error {
+	now := v.clock.Now()
+	if !notBefore.IsZero() && now.Before(notBefore.Add(-v.leeway)) {
+		return fmt.Errorf("%w: starts in %v", ErrNotYetValid, notBefore.Sub(now))
+	}
+	if !expiry.IsZero() && !now.Before(expiry.Add(v.leeway)) {
+		return fmt.Errorf("%w: ended %v ago", ErrExpired, now.Sub(expiry))
+	}
+	return nil
+}
+
+// Valid reports whether Check would return nil.
+func (v *Validator) Valid(notBefore, expiry time.Time) bool {
+	return v.Check(notBefore, expiry) == nil
+}
+
+// Remaining returns how long is left before a window ending at expiry is
+// rejected, including the leeway, or 0 if it already is.
+func (v *Validator) Remaining(expiry time.Time) time.Duration {
+	left := expiry.Add(v.leeway).Sub(v.clock.Now())
+	if left < 0 {
+		return 0
+	}
+	return left
+}
@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidWindow(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	nbf, exp := time.Unix(100, 0), time.Unix(200, 0)
+
+	mock.Set(time.Unix(99, 0))
+	err := ValidWindow(mock, nbf, exp)
+	assert.True(t, errors.Is(err, ErrNotYetValid), err)
+	assert.EqualError(t, err, "not yet valid: starts in 1s")
+
+	mock.Set(nbf)
+	assert.NoError(t, ValidWindow(mock, nbf, exp))
+
+	mock.Set(exp.Add(-time.Nanosecond))
+	assert.NoError(t, ValidWindow(mock, nbf, exp))
+
+	mock.Set(exp)
+	err = ValidWindow(mock, nbf, exp)
+	assert.True(t, errors.Is(err, ErrExpired), err)
+	assert.EqualError(t, err, "expired: ended 0s ago")
+
+	// zero times leave the window open
+	assert.NoError(t, ValidWindow(mock, time.Time{}, time.Time{}))
+	mock.Set(time.Unix(0, 0))
+	assert.NoError(t, ValidWindow(mock, time.Time{}, exp))
+}
+
+func TestValidator(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	v := NewValidator(mock, 30*time.Second)
+	nbf, exp := time.Unix(100, 0), time.Unix(200, 0)
+
+	mock.Set(time.Unix(69, 0))
+	assert.False(t, v.Valid(nbf, exp))
+	mock.Set(time.Unix(70, 0))
+	assert.True(t, v.Valid(nbf, exp))
+
+	mock.Set(time.Unix(229, 0))
+	assert.True(t, v.Valid(nbf, exp))
+	assert.Equal(t, time.Second, v.Remaining(exp))
+	mock.Add(time.Second)
+	assert.True(t, errors.Is(v.Check(nbf, exp), ErrExpired))
+	assert.Equal(t, time.Duration(0), v.Remaining(exp))
+
+	assert.PanicsWithValue(t, "negative leeway for NewValidator", func() { NewValidator(mock, -time.Second) })
+}
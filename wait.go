@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// WaitCheckpoint waits for cp like cp.Wait, but gives up after timeout of
+// real time, failing tb with the number of events cp is still expecting. It
+// returns whether the wait completed.
+//
+// An OptionalCheckpoint abandons a wait that times out cleanly. Other
+// checkpoints can't, so their wait is left running in the background, and cp
+// should not be waited on again afterwards.
+func WaitCheckpoint(tb testing.TB, cp Checkpoint, timeout time.Duration) bool {
+	tb.Helper()
+	if waitFor(cp, timeout) {
+		return true
+	}
+	tb.Errorf("clock: timed out after %v waiting for checkpoint %s", timeout, describeCheckpoint(cp))
+	return false
+}
+
+// WaitTimeout is like Wait, but gives up after timeout of real time, failing
+// tb with the starts and AfterFunc callbacks that are still outstanding, and
+// the timers that are pending. It returns whether the wait completed.
+//
+// A goroutine that never creates its timer would otherwise hang Wait until
+// the test binary's own timeout, with no hint of which wait was stuck.
+func (m *UnsynchronizedMock) WaitTimeout(tb testing.TB, timeout time.Duration) bool {
+	tb.Helper()
+	m.mu.Lock()
+	sp := m.startCheckpoint
+	m.mu.Unlock()
+	if waitFor(sp, timeout) {
+		m.logEvent(Event{Type: CheckpointWaited, Time: m.current(), Checkpoint: checkpointName(sp)})
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "clock: timed out after %v waiting for timers to start\n", timeout)
+	fmt.Fprintf(&b, "  starts:     %s\n", describeCheckpoint(sp))
+	fmt.Fprintf(&b, "  AfterFuncs: %s\n", describeCheckpoint(m.afterFuncCheckpoint))
+	pending := m.PendingTimers()
+	fmt.Fprintf(&b, "  %d pending timers", len(pending))
+	for _, t := range pending {
+		fmt.Fprintf(&b, "\n    %v %v", t.Kind, t.Deadline.Format(time.RFC3339Nano))
+		if t.Name != "" {
+			fmt.Fprintf(&b, " %q", t.Name)
+		}
+	}
+	tb.Error(b.String())
+	return false
+}
+
+// timedWaiter is implemented by checkpoints that can give up on a wait.
+type timedWaiter interface {
+	waitTimeout(timeout time.Duration) bool
+}
+
+// waitFor waits for cp, and reports whether it finished within timeout.
+func waitFor(cp Checkpoint, timeout time.Duration) bool {
+	if w, ok := cp.(timedWaiter); ok {
+		return w.waitTimeout(timeout)
+	}
+	done := make(chan struct{})
+	go func() {
+		cp.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// describeCheckpoint names cp and says how many events it is still expecting.
+func describeCheckpoint(cp Checkpoint) string {
+	name := checkpointName(cp)
+	if name == "" {
+		name = CheckpointName(fmt.Sprintf("%T", cp))
+	}
+	return fmt.Sprintf("%s (%d outstanding)", name, cp.Outstanding())
+}
+
+func (s *OptionalCheckpoint) waitTimeout(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	os := <-s.outstanding
+	for os > 0 {
+		select {
+		case update := <-s.outstanding:
+			os += update
+		case <-timer.C:
+			// give the count back, merging any update that raced in
+			s.updateOutstanding(os)
+			return false
+		}
+	}
+	s.reset()
+	return true
+}
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that WaitTimeout returns once the expected timers have started.
+func TestMock_WaitTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.ExpectStarts(1)
+	go mock.NewTimer(time.Second)
+
+	tb := &recordingTB{TB: t}
+	assert.True(t, mock.WaitTimeout(tb, 5*time.Second))
+	assert.False(t, tb.failed)
+}
+
+// Ensure that WaitTimeout fails with what is still outstanding when a
+// timer never starts.
+func TestMock_WaitTimeout_Expired(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	mock.ExpectStarts(2)
+	mock.NewTimerWith(time.Second, TimerName("poll"))
+
+	tb := &recordingTB{TB: t}
+	assert.False(t, mock.WaitTimeout(tb, 10*time.Millisecond))
+	assert.True(t, tb.failed)
+	if assert.Len(t, tb.logs, 1) {
+		assert.Contains(t, tb.logs[0], "timed out after 10ms")
+		assert.Contains(t, tb.logs[0], "TimerStart (1 outstanding)")
+		assert.Contains(t, tb.logs[0], "1 pending timers")
+		assert.Contains(t, tb.logs[0], `Timer `+mock.Now().Add(time.Second).Format(time.RFC3339Nano)+` "poll"`)
+	}
+}
+
+// Ensure that WaitCheckpoint reports the checkpoint it gave up on.
+func TestWaitCheckpoint(t *testing.T) {
+	cp := NewOptionalCheckPoint("confirm")
+	cp.Add(1)
+
+	tb := &recordingTB{TB: t}
+	assert.False(t, WaitCheckpoint(tb, cp, 10*time.Millisecond))
+	assert.Equal(t, []string{"clock: timed out after 10ms waiting for checkpoint confirm (1 outstanding)"}, tb.logs)
+
+	cp = NewOptionalCheckPoint("confirm")
+	cp.Add(1)
+	cp.Done()
+	tb = &recordingTB{TB: t}
+	assert.True(t, WaitCheckpoint(tb, cp, 10*time.Millisecond))
+	assert.False(t, tb.failed)
+}
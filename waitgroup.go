@@ -0,0 +1,56 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WaitGroup is a sync.WaitGroup whose waits can be bounded by a timeout
+// measured on a clock, so that shutdown paths waiting on workers can be
+// tested on a mock without real waits.
+type WaitGroup struct {
+	sync.WaitGroup
+	clock MockableClock
+}
+
+// NewWaitGroup returns a WaitGroup whose timeouts are measured on c.
+func NewWaitGroup(c MockableClock) *WaitGroup {
+	return &WaitGroup{clock: c}
+}
+
+// WaitTimeout is like Wait, but gives up after d on the group's clock. It
+// returns true if the counter reached zero in time.
+func (wg *WaitGroup) WaitTimeout(d time.Duration) bool {
+	t := AcquireTimer(wg.clock, d)
+	defer ReleaseTimer(t)
+	select {
+	case <-wg.done():
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
+// WaitContext is like Wait, but gives up when ctx is done, returning its
+// error. It returns nil if the counter reached zero first.
+func (wg *WaitGroup) WaitContext(ctx context.Context) error {
+	select {
+	case <-wg.done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// done returns a channel that is closed once Wait returns. A wait that is
+// given up on leaves a goroutine blocked in Wait until the counter reaches
+// zero.
+func (wg *WaitGroup) done() <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that WaitTimeout gives up after the timeout on the clock, and
+// returns as soon as the workers are done.
+func TestWaitGroup_WaitTimeout(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	wg := NewWaitGroup(mock)
+	wg.Add(1)
+
+	mock.ExpectStarts(1)
+	go mock.Add(time.Minute, WaitBefore)
+	assert.False(t, wg.WaitTimeout(time.Minute))
+
+	go wg.Done()
+	assert.True(t, wg.WaitTimeout(time.Minute))
+	assert.Empty(t, mock.PendingTimers())
+}
+
+// Ensure that WaitContext gives up when the context is done.
+func TestWaitGroup_WaitContext(t *testing.T) {
+	mock := NewUnsynchronizedMock()
+	wg := NewWaitGroup(mock)
+	wg.Add(1)
+
+	mock.ExpectStarts(1)
+	ctx, cancel := ContextWithTimeout(context.Background(), mock, time.Second)
+	defer cancel()
+	go mock.Add(time.Second, WaitBefore)
+	assert.Equal(t, context.DeadlineExceeded, wg.WaitContext(ctx))
+
+	wg.Done()
+	assert.NoError(t, wg.WaitContext(context.Background()))
+}
@@ -0,0 +1,265 @@
+package clock
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TimingWheel is a real-time clock whose timers and tickers are kept in a
+// hashed timing wheel instead of the runtime's timer heap. Scheduling,
+// stopping and resetting a timer are O(1), which suits workloads with
+// hundreds of thousands of pending timeouts, such as proxies and connection
+// managers, that are mostly stopped before they fire.
+//
+// The price is precision: durations are rounded up to a whole number of
+// ticks, plus one for the part of the current tick already gone, so timers
+// never fire early but may fire up to two ticks late. Now, Since and the
+// sleeps are those of the realtime clock.
+type TimingWheel struct {
+	clock
+	tick time.Duration
+
+	mu      sync.Mutex
+	slots   []wheelList
+	pos     int
+	pending int
+
+	ticker *time.Ticker
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewTimingWheel returns a running timing wheel that advances every tick
+// and has the given number of slots. Timers within slots ticks of now are
+// placed directly; later ones wait out whole turns of the wheel. Stop the
+// wheel when it is no longer needed.
+func NewTimingWheel(tick time.Duration, slots int) *TimingWheel {
+	w := newTimingWheel(tick, slots)
+	w.ticker = time.NewTicker(tick)
+	go w.run()
+	return w
+}
+
+// newTimingWheel returns a wheel that doesn't advance by itself.
+func newTimingWheel(tick time.Duration, slots int) *TimingWheel {
+	if tick <= 0 {
+		panic("non-positive tick for NewTimingWheel")
+	}
+	if slots <= 0 {
+		panic("non-positive slots for NewTimingWheel")
+	}
+	return &TimingWheel{tick: tick, slots: make([]wheelList, slots), done: make(chan struct{})}
+}
+
+// Stop stops the wheel. Its pending timers and tickers never fire.
+func (w *TimingWheel) Stop() {
+	w.once.Do(func() {
+		w.ticker.Stop()
+		close(w.done)
+	})
+}
+
+// Pending returns the number of timers and tickers waiting to fire.
+func (w *TimingWheel) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pending
+}
+
+func (w *TimingWheel) run() {
+	for {
+		select {
+		case <-w.ticker.C:
+			w.advance()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// advance moves the wheel on one tick and fires the timers that are due.
+func (w *TimingWheel) advance() {
+	w.mu.Lock()
+	w.pos = (w.pos + 1) % len(w.slots)
+	var due []*wheelTimer
+	for t := w.slots[w.pos].head; t != nil; {
+		next := t.next
+		if t.rounds > 0 {
+			t.rounds--
+		} else {
+			w.remove(t)
+			due = append(due, t)
+		}
+		t = next
+	}
+	for _, t := range due {
+		if t.period > 0 {
+			w.schedule(t, t.period)
+		}
+	}
+	w.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range due {
+		t.fire(now)
+	}
+}
+
+// schedule must be called with mu held.
+func (w *TimingWheel) schedule(t *wheelTimer, d time.Duration) {
+	// Count whole ticks, rounding up, without overflowing, and wait no
+	// longer than can be counted, so that a duration such as math.MaxInt64,
+	// for a timer that never fires, doesn't wrap around.
+	var n int64
+	if d > 0 {
+		n = int64(d / w.tick)
+		if d%w.tick != 0 {
+			n++
+		}
+	}
+	max := int64(math.MaxInt - len(w.slots))
+	if n >= max {
+		n = max - 1
+	}
+	ticks := int(n) + 1
+	t.slot = (w.pos + ticks) % len(w.slots)
+	t.rounds = (ticks - 1) / len(w.slots)
+	w.slots[t.slot].push(t)
+	t.active = true
+	w.pending++
+}
+
+// remove must be called with mu held. It reports whether t was pending.
+func (w *TimingWheel) remove(t *wheelTimer) bool {
+	if !t.active {
+		return false
+	}
+	w.slots[t.slot].remove(t)
+	t.active = false
+	w.pending--
+	return true
+}
+
+func (w *TimingWheel) After(d time.Duration) <-chan time.Time { return w.NewTimer(d).C }
+
+func (w *TimingWheel) AfterAt(t time.Time) <-chan time.Time { return w.NewTimer(time.Until(t)).C }
+
+func (w *TimingWheel) AfterFunc(d time.Duration, f func()) MockableTimer {
+	t := &wheelTimer{w: w, f: f}
+	w.add(t, d)
+	return WrapTimer(nil, t)
+}
+
+func (w *TimingWheel) AtFunc(t time.Time, f func()) MockableTimer {
+	return w.AfterFunc(time.Until(t), f)
+}
+
+func (w *TimingWheel) NewTimer(d time.Duration) *Timer {
+	t := &wheelTimer{w: w, c: make(chan time.Time, 1)}
+	w.add(t, d)
+	return WrapTimer(t.c, t)
+}
+
+func (w *TimingWheel) Tick(d time.Duration) <-chan time.Time { return w.NewTicker(d).C }
+
+func (w *TimingWheel) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	t := &wheelTimer{w: w, c: make(chan time.Time, 1), period: d}
+	w.add(t, d)
+	return WrapTicker(t.c, wheelTicker{t})
+}
+
+func (w *TimingWheel) NewAlignedTicker(d time.Duration) *Ticker { return AlignTicker(w, d) }
+
+func (w *TimingWheel) add(t *wheelTimer, d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.schedule(t, d)
+}
+
+// wheelTimer is a timer or ticker in a TimingWheel, and an entry in the
+// list of its slot.
+type wheelTimer struct {
+	w      *TimingWheel
+	c      chan time.Time
+	f      func()
+	period time.Duration // non-zero for tickers
+
+	// Guarded by w.mu.
+	slot       int
+	rounds     int // turns of the wheel left before firing
+	active     bool
+	prev, next *wheelTimer
+}
+
+func (t *wheelTimer) fire(now time.Time) {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+func (t *wheelTimer) Stop() bool {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+	return t.w.remove(t)
+}
+
+func (t *wheelTimer) Reset(d time.Duration) bool {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+	active := t.w.remove(t)
+	t.w.schedule(t, d)
+	return active
+}
+
+// wheelTicker adapts a wheelTimer to TickerBackend.
+type wheelTicker struct {
+	t *wheelTimer
+}
+
+func (t wheelTicker) Stop() { t.t.Stop() }
+
+func (t wheelTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.t.w.mu.Lock()
+	defer t.t.w.mu.Unlock()
+	t.t.period = d
+	t.t.w.remove(t.t)
+	t.t.w.schedule(t.t, d)
+}
+
+// wheelList is a doubly linked list of timers, for O(1) removal.
+type wheelList struct {
+	head *wheelTimer
+}
+
+func (l *wheelList) push(t *wheelTimer) {
+	t.prev = nil
+	t.next = l.head
+	if l.head != nil {
+		l.head.prev = t
+	}
+	l.head = t
+}
+
+func (l *wheelList) remove(t *wheelTimer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		l.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+	t.prev, t.next = nil, nil
+}
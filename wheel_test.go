@@ -0,0 +1,96 @@
+package clock
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that timers fire on the tick after the one their duration rounds up
+// to, including those that wait out turns of the wheel.
+func TestTimingWheel_Advance(t *testing.T) {
+	w := newTimingWheel(time.Second, 4)
+	short := w.NewTimer(1500 * time.Millisecond)
+	long := w.NewTimer(9 * time.Second)
+	fired := make(chan struct{})
+	w.AfterFunc(4*time.Second, func() { close(fired) })
+	assert.Equal(t, 3, w.Pending())
+
+	for tick := 1; tick <= 10; tick++ {
+		w.advance()
+		select {
+		case <-short.C:
+			assert.Equal(t, 3, tick)
+		case <-long.C:
+			assert.Equal(t, 10, tick)
+		default:
+		}
+		if tick == 5 {
+			<-fired
+		}
+	}
+	assert.Equal(t, 0, w.Pending())
+}
+
+// Ensure that tickers reschedule themselves, and Stop and Reset take timers
+// out of the wheel.
+func TestTimingWheel_StopReset(t *testing.T) {
+	w := newTimingWheel(time.Second, 4)
+	ticker := w.NewTicker(2 * time.Second)
+	timer := w.NewTimer(time.Second)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	assert.Equal(t, 1, w.Pending())
+
+	for tick := 1; tick <= 6; tick++ {
+		w.advance()
+		select {
+		case <-ticker.C:
+			assert.Equal(t, 0, tick%3, "tick %d", tick)
+		default:
+			assert.NotEqual(t, 0, tick%3, "tick %d", tick)
+		}
+	}
+
+	assert.False(t, timer.Reset(time.Second))
+	ticker.Stop()
+	assert.Equal(t, 1, w.Pending())
+	w.advance()
+	w.advance()
+	<-timer.C
+}
+
+// Ensure that a running wheel fires timers in real time.
+// Ensure that durations too long to count in ticks, like the math.MaxInt64
+// used for timers that never fire, are scheduled without overflowing.
+func TestTimingWheel_HugeDuration(t *testing.T) {
+	for _, tick := range []time.Duration{time.Nanosecond, time.Second} {
+		w := newTimingWheel(tick, 4)
+		timer := w.NewTimer(math.MaxInt64)
+		w.NewTimer(math.MaxInt64 - 1)
+		assert.Equal(t, 2, w.Pending())
+		for i := 0; i < 10; i++ {
+			w.advance()
+		}
+		select {
+		case <-timer.C:
+			t.Fatal("timer for math.MaxInt64 fired")
+		default:
+		}
+		assert.Equal(t, 2, w.Pending())
+	}
+}
+
+func TestNewTimingWheel(t *testing.T) {
+	w := NewTimingWheel(time.Millisecond, 8)
+	defer w.Stop()
+
+	start := time.Now()
+	<-w.After(20 * time.Millisecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	ticker := w.NewAlignedTicker(5 * time.Millisecond)
+	<-ticker.C
+	ticker.Stop()
+}
@@ -0,0 +1,136 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WindowLimiter is a sliding window rate limiter: it allows at most limit
+// events in any period of length window, as many API quotas are defined.
+// It keeps a log of the events in the current window, so the limit is
+// exact, and reads the time from a MockableClock, so quota logic can be
+// tested by advancing a mock across window boundaries.
+type WindowLimiter struct {
+	clock  MockableClock
+	limit  int
+	window time.Duration
+
+	mu     sync.Mutex
+	events []time.Time // times of the events in the window, oldest first
+}
+
+// NewWindowLimiter returns a WindowLimiter timed by c that allows limit
+// events per window. It panics if window is not positive.
+func NewWindowLimiter(c MockableClock, limit int, window time.Duration) *WindowLimiter {
+	if window <= 0 {
+		panic("non-positive window for NewWindowLimiter")
+	}
+	return &WindowLimiter{clock: c, limit: limit, window: window}
+}
+
+// Allow reports whether an event may happen now, and records it if so.
+func (lim *WindowLimiter) Allow() bool {
+	return lim.AllowN(lim.clock.Now(), 1)
+}
+
+// AllowN reports whether n events may happen at time t, and records them if
+// so. An event counts against the limit until window has passed since it.
+func (lim *WindowLimiter) AllowN(t time.Time, n int) bool {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if lim.count(t)+n > lim.limit {
+		return false
+	}
+	lim.record(t, n)
+	return true
+}
+
+// Remaining returns how many more events the limiter allows now.
+func (lim *WindowLimiter) Remaining() int {
+	now := lim.clock.Now()
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return lim.limit - lim.count(now)
+}
+
+// Wait is shorthand for WaitN(ctx, 1).
+func (lim *WindowLimiter) Wait(ctx context.Context) error {
+	return lim.WaitN(ctx, 1)
+}
+
+// WaitN blocks until the limiter permits n events to happen, waiting on the
+// limiter's clock, and records them. It returns an error if n exceeds the
+// limit, the context is canceled, or the wait would go past the context's
+// deadline as measured on the clock.
+func (lim *WindowLimiter) WaitN(ctx context.Context, n int) error {
+	if n > lim.limit {
+		return fmt.Errorf("window: Wait(n=%d) exceeds limit %d", n, lim.limit)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		now := lim.clock.Now()
+		lim.mu.Lock()
+		at := lim.freeAt(now, n)
+		if !at.After(now) {
+			lim.record(now, n)
+			lim.mu.Unlock()
+			return nil
+		}
+		lim.mu.Unlock()
+
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(at) {
+			return fmt.Errorf("window: Wait(n=%d) would exceed context deadline", n)
+		}
+		t := lim.clock.NewTimer(at.Sub(now))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// freeAt returns the earliest time from t at which n more events fit in the
+// window. n must not exceed the limit, and it must be called with mu held.
+func (lim *WindowLimiter) freeAt(t time.Time, n int) time.Time {
+	over := lim.count(t) + n - lim.limit
+	if over <= 0 {
+		return t
+	}
+	return lim.events[over-1].Add(lim.window)
+}
+
+// count returns the number of events in the window ending at t. It must be
+// called with mu held.
+func (lim *WindowLimiter) count(t time.Time) int {
+	lim.prune(t)
+	return len(lim.events)
+}
+
+// prune forgets the events that are out of the window ending at t. It must
+// be called with mu held.
+func (lim *WindowLimiter) prune(t time.Time) {
+	start := t.Add(-lim.window)
+	i := sort.Search(len(lim.events), func(i int) bool { return lim.events[i].After(start) })
+	lim.events = append(lim.events[:0], lim.events[i:]...)
+}
+
+// record adds n events at t, keeping the log in order. It must be called
+// with mu held.
+func (lim *WindowLimiter) record(t time.Time, n int) {
+	i := sort.Search(len(lim.events), func(i int) bool { return lim.events[i].After(t) })
+	for ; n > 0; n-- {
+		lim.events = append(lim.events, time.Time{})
+		copy(lim.events[i+1:], lim.events[i:])
+		lim.events[i] = t
+	}
+}
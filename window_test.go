@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ensure that events leave the window exactly when it has passed them.
+func TestWindowLimiter_Allow(t *testing.T) {
+	clock := NewUnsynchronizedMock()
+	lim := NewWindowLimiter(clock, 3, time.Minute)
+
+	assert.True(t, lim.Allow())
+	clock.Add(20 * time.Second)
+	assert.True(t, lim.AllowN(clock.Now(), 2))
+	assert.False(t, lim.Allow())
+	assert.Equal(t, 0, lim.Remaining())
+
+	// Unlike a token bucket, nothing frees up until the first event leaves
+	// the window...
+	clock.Add(39 * time.Second)
+	assert.False(t, lim.Allow())
+	clock.Add(time.Second)
+	assert.Equal(t, 1, lim.Remaining())
+	assert.True(t, lim.Allow())
+
+	// ...and then the next two leave together.
+	clock.Add(20 * time.Second)
+	assert.Equal(t, 2, lim.Remaining())
+	assert.False(t, lim.AllowN(clock.Now(), 3))
+	assert.True(t, lim.AllowN(clock.Now(), 2))
+	assert.False(t, lim.AllowN(clock.Now(), 4), "more than the limit")
+
+	assert.PanicsWithValue(t, "non-positive window for NewWindowLimiter", func() {
+		NewWindowLimiter(clock, 1, 0)
+	})
+}
+
+func TestWindowLimiter_Wait(t *testing.T) {
+	clock := NewMock(t, 0)
+	lim := NewWindowLimiter(clock, 2, time.Minute)
+	assert.NoError(t, lim.WaitN(context.Background(), 2))
+
+	done := make(chan error)
+	clock.ExpectStarts(1)
+	go func() { done <- lim.Wait(context.Background()) }()
+	clock.Add(59 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("wait returned before the window passed")
+	default:
+	}
+	clock.Add(time.Second)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, lim.Remaining())
+
+	clock.ExpectStarts(1)
+	ctx, cancel := ContextWithTimeout(context.Background(), clock, 30*time.Second)
+	defer cancel()
+	assert.EqualError(t, lim.WaitN(ctx, 2), "window: Wait(n=2) would exceed context deadline")
+	assert.EqualError(t, lim.WaitN(context.Background(), 3), "window: Wait(n=3) exceeds limit 2")
+}